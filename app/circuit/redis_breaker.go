@@ -0,0 +1,327 @@
+package circuit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrOpenState 熔断器处于打开状态
+	ErrOpenState = errors.New("circuit breaker is open")
+	// ErrTooManyRequests 半开状态探测请求数已满
+	ErrTooManyRequests = errors.New("circuit breaker is half-open, too many requests")
+)
+
+// redisKeyPrefix Redis 熔断器状态 key 前缀
+const redisKeyPrefix = "circuit_breaker:"
+
+// redisStateTTL 熔断器状态在 Redis 中的兜底过期时间
+const redisStateTTL = 24 * time.Hour
+
+// allowScript 请求前检查（原子操作）
+// 半开状态的 expiry 为探测超时时间：探测名额用完且超时仍未上报结果（如 worker 崩溃、上下文取消）时，
+// 重新发放探测名额，避免熔断器一直停留在半开状态拒绝所有请求
+// 返回 {是否放行, 当前状态}
+var allowScript = redis.NewScript(`
+	local key = KEYS[1]
+	local now = tonumber(ARGV[1])
+	local maxRequests = tonumber(ARGV[2])
+	local interval = tonumber(ARGV[3])
+	local ttl = tonumber(ARGV[4])
+	local timeout = tonumber(ARGV[5])
+
+	local state = tonumber(redis.call('HGET', key, 'state') or '0')
+	local expiry = tonumber(redis.call('HGET', key, 'expiry') or '0')
+
+	if state == 1 then
+		if expiry > now then
+			return {0, 1}
+		end
+		state = 2
+		redis.call('HSET', key, 'state', 2, 'requests', 0, 'failures', 0, 'consecutive_successes', 0, 'consecutive_failures', 0, 'expiry', now + timeout)
+	elseif state == 0 and (expiry == 0 or expiry <= now) then
+		redis.call('HSET', key, 'state', 0, 'requests', 0, 'failures', 0, 'consecutive_successes', 0, 'consecutive_failures', 0, 'expiry', now + interval)
+	end
+
+	if state == 2 then
+		local requests = tonumber(redis.call('HGET', key, 'requests') or '0')
+		if requests >= maxRequests then
+			if expiry > now then
+				return {0, 2}
+			end
+			redis.call('HSET', key, 'requests', 0, 'consecutive_successes', 0, 'expiry', now + timeout)
+		end
+	end
+
+	redis.call('HINCRBY', key, 'requests', 1)
+	redis.call('PEXPIRE', key, ttl)
+	return {1, state}
+`)
+
+// reportScript 请求后处理（原子操作）
+// 返回 {变更前状态, 变更后状态}
+var reportScript = redis.NewScript(`
+	local key = KEYS[1]
+	local now = tonumber(ARGV[1])
+	local success = tonumber(ARGV[2])
+	local maxRequests = tonumber(ARGV[3])
+	local minRequests = tonumber(ARGV[4])
+	local threshold = tonumber(ARGV[5])
+	local interval = tonumber(ARGV[6])
+	local timeout = tonumber(ARGV[7])
+	local ttl = tonumber(ARGV[8])
+
+	local state = tonumber(redis.call('HGET', key, 'state') or '0')
+
+	-- 熔断期间的迟到结果不再计数
+	if state == 1 then
+		return {1, 1}
+	end
+
+	if success == 1 then
+		local consecutive = redis.call('HINCRBY', key, 'consecutive_successes', 1)
+		redis.call('HSET', key, 'consecutive_failures', 0)
+		if state == 2 and consecutive >= maxRequests then
+			redis.call('HSET', key, 'state', 0, 'requests', 0, 'failures', 0, 'consecutive_successes', 0, 'consecutive_failures', 0, 'expiry', now + interval)
+			redis.call('PEXPIRE', key, ttl)
+			return {2, 0}
+		end
+		redis.call('PEXPIRE', key, ttl)
+		return {state, state}
+	end
+
+	local failures = redis.call('HINCRBY', key, 'failures', 1)
+	redis.call('HINCRBY', key, 'consecutive_failures', 1)
+	redis.call('HSET', key, 'consecutive_successes', 0, 'last_failure_at', now)
+
+	local open = false
+	if state == 2 then
+		open = true
+	else
+		local requests = tonumber(redis.call('HGET', key, 'requests') or '0')
+		if requests < failures then
+			requests = failures
+		end
+		if requests >= minRequests and failures / requests >= threshold then
+			open = true
+		end
+	end
+
+	if open then
+		redis.call('HSET', key, 'state', 1, 'requests', 0, 'failures', 0, 'consecutive_successes', 0, 'consecutive_failures', 0, 'expiry', now + timeout, 'opened_at', now)
+		redis.call('PEXPIRE', key, ttl)
+		return {state, 1}
+	end
+
+	redis.call('PEXPIRE', key, ttl)
+	return {state, state}
+`)
+
+// Snapshot 熔断器状态快照
+type Snapshot struct {
+	Name                string
+	State               State
+	Requests            uint32
+	Failures            uint32
+	ConsecutiveFailures uint32
+	OpenedAt            time.Time
+	Expiry              time.Time
+	LastFailureAt       time.Time
+}
+
+// RedisBreaker 基于 Redis 共享状态的熔断器
+// 状态与计数保存在 Redis Hash 中，多个实例共享同一熔断状态
+type RedisBreaker struct {
+	client        *redis.Client
+	name          string
+	maxRequests   uint32        // 半开状态下允许的最大请求数
+	interval      time.Duration // 统计窗口时间
+	timeout       time.Duration // 熔断超时时间（打开->半开）
+	threshold     float64       // 失败率阈值
+	minRequests   uint32        // 最小请求数（达到才触发熔断）
+	onStateChange func(name string, from State, to State)
+}
+
+// NewRedisBreaker 创建 Redis 共享熔断器
+func NewRedisBreaker(client *redis.Client, name string) *RedisBreaker {
+	return &RedisBreaker{
+		client:      client,
+		name:        name,
+		maxRequests: 3,
+		interval:    time.Minute,
+		timeout:     time.Minute,
+		threshold:   0.5,
+		minRequests: 10,
+	}
+}
+
+// WithMaxRequests 设置半开状态最大请求数
+func (b *RedisBreaker) WithMaxRequests(n uint32) *RedisBreaker {
+	b.maxRequests = n
+	return b
+}
+
+// WithInterval 设置统计窗口时间
+func (b *RedisBreaker) WithInterval(d time.Duration) *RedisBreaker {
+	b.interval = d
+	return b
+}
+
+// WithTimeout 设置熔断超时时间
+func (b *RedisBreaker) WithTimeout(d time.Duration) *RedisBreaker {
+	b.timeout = d
+	return b
+}
+
+// WithThreshold 设置失败率阈值
+func (b *RedisBreaker) WithThreshold(t float64) *RedisBreaker {
+	b.threshold = t
+	return b
+}
+
+// WithMinRequests 设置触发熔断的最小请求数
+func (b *RedisBreaker) WithMinRequests(n uint32) *RedisBreaker {
+	b.minRequests = n
+	return b
+}
+
+// WithOnStateChange 设置状态变更回调
+func (b *RedisBreaker) WithOnStateChange(fn func(name string, from State, to State)) *RedisBreaker {
+	b.onStateChange = fn
+	return b
+}
+
+// Name 获取熔断器名称
+func (b *RedisBreaker) Name() string {
+	return b.name
+}
+
+// key 构建 Redis key
+func (b *RedisBreaker) key() string {
+	return redisKeyPrefix + b.name
+}
+
+// Allow 请求前检查，放行时计入一次请求（半开状态下即为一次探测）
+func (b *RedisBreaker) Allow(ctx context.Context) error {
+	now := time.Now().UnixMilli()
+	result, err := allowScript.Run(ctx, b.client, []string{b.key()},
+		now, b.maxRequests, b.interval.Milliseconds(), redisStateTTL.Milliseconds(), b.timeout.Milliseconds()).Int64Slice()
+	if err != nil {
+		return err
+	}
+
+	if result[0] == 1 {
+		return nil
+	}
+
+	if State(result[1]) == StateHalfOpen {
+		return fmt.Errorf("%s: %w", b.name, ErrTooManyRequests)
+	}
+	return fmt.Errorf("%s: %w", b.name, ErrOpenState)
+}
+
+// Report 上报请求结果
+func (b *RedisBreaker) Report(ctx context.Context, success bool) error {
+	successFlag := 0
+	if success {
+		successFlag = 1
+	}
+
+	now := time.Now().UnixMilli()
+	result, err := reportScript.Run(ctx, b.client, []string{b.key()},
+		now, successFlag, b.maxRequests, b.minRequests, b.threshold,
+		b.interval.Milliseconds(), b.timeout.Milliseconds(), redisStateTTL.Milliseconds()).Int64Slice()
+	if err != nil {
+		return err
+	}
+
+	from, to := State(result[0]), State(result[1])
+	if from != to && b.onStateChange != nil {
+		b.onStateChange(b.name, from, to)
+	}
+
+	return nil
+}
+
+// Snapshot 获取当前状态快照（只读，不改变计数）
+func (b *RedisBreaker) Snapshot(ctx context.Context) (*Snapshot, error) {
+	values, err := b.client.HGetAll(ctx, b.key()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		Name:                b.name,
+		State:               State(parseInt64(values["state"])),
+		Requests:            uint32(parseInt64(values["requests"])),
+		Failures:            uint32(parseInt64(values["failures"])),
+		ConsecutiveFailures: uint32(parseInt64(values["consecutive_failures"])),
+		OpenedAt:            parseMillis(values["opened_at"]),
+		Expiry:              parseMillis(values["expiry"]),
+		LastFailureAt:       parseMillis(values["last_failure_at"]),
+	}
+
+	// 熔断超时已过，下一次请求将进入半开探测
+	if snapshot.State == StateOpen && !snapshot.Expiry.After(time.Now()) {
+		snapshot.State = StateHalfOpen
+	}
+
+	return snapshot, nil
+}
+
+// Call 执行函数
+func (b *RedisBreaker) Call(ctx context.Context, fn func() error) error {
+	if err := b.Allow(ctx); err != nil {
+		return err
+	}
+
+	err := fn()
+	_ = b.Report(ctx, err == nil)
+
+	return err
+}
+
+// State 获取当前状态
+func (b *RedisBreaker) State() State {
+	snapshot, err := b.Snapshot(context.Background())
+	if err != nil {
+		return StateClosed
+	}
+	return snapshot.State
+}
+
+// Reset 重置熔断器
+func (b *RedisBreaker) Reset() {
+	_ = b.ResetContext(context.Background())
+}
+
+// ResetContext 重置熔断器（返回错误）
+func (b *RedisBreaker) ResetContext(ctx context.Context) error {
+	return b.client.Del(ctx, b.key()).Err()
+}
+
+// parseInt64 解析整数，失败返回 0
+func parseInt64(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseMillis 解析毫秒时间戳
+func parseMillis(value string) time.Time {
+	ms := parseInt64(value)
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -20,16 +20,23 @@ const (
 	CodeInvalidTimestamp = 20004 // 时间戳无效（防重放）
 	CodeIPNotAllowed     = 20005 // IP不在白名单
 	CodeAppDisabled      = 20006 // 应用已禁用
+	CodeNonceReused      = 20007 // 随机数重复使用（防重放）
 
 	// 3xxxx - 业务错误
-	CodeRateLimitExceeded  = 30001 // 超出速率限制
-	CodeQuotaExceeded      = 30002 // 超出配额限制
-	CodeChannelNotFound    = 30003 // 推送通道不存在
-	CodeChannelDisabled    = 30004 // 推送通道已禁用
-	CodeTemplateNotFound   = 30005 // 模板不存在
-	CodeNoAvailableChannel = 30006 // 无可用通道
-	CodeTaskNotFound       = 30007 // 任务不存在
-	CodeBatchNotFound      = 30008 // 批量任务不存在
+	CodeRateLimitExceeded   = 30001 // 超出速率限制
+	CodeQuotaExceeded       = 30002 // 超出配额限制
+	CodeChannelNotFound     = 30003 // 推送通道不存在
+	CodeChannelDisabled     = 30004 // 推送通道已禁用
+	CodeTemplateNotFound    = 30005 // 模板不存在
+	CodeNoAvailableChannel  = 30006 // 无可用通道
+	CodeTaskNotFound        = 30007 // 任务不存在
+	CodeBatchNotFound       = 30008 // 批量任务不存在
+	CodeIdempotencyConflict = 30009 // 幂等键冲突
+	CodeFrequencyLimited    = 30010 // 接收者超出频率限制
+	CodeOTPCooldown         = 30011 // 验证码发送过于频繁
+	CodeOTPInvalid          = 30012 // 验证码错误
+	CodeOTPExpired          = 30013 // 验证码已过期或已使用
+	CodeOTPAttemptsExceeded = 30014 // 验证码校验次数超限
 
 	// 4xxxx - 系统错误
 	CodeInternalError  = 40001 // 内部错误
@@ -56,6 +63,7 @@ var ErrorMessages = map[int]string{
 	CodeInvalidTimestamp:      "invalid timestamp",
 	CodeIPNotAllowed:          "ip not allowed",
 	CodeAppDisabled:           "app disabled",
+	CodeNonceReused:           "nonce already used",
 	CodeRateLimitExceeded:     "rate limit exceeded",
 	CodeQuotaExceeded:         "quota exceeded",
 	CodeChannelNotFound:       "channel not found",
@@ -64,6 +72,12 @@ var ErrorMessages = map[int]string{
 	CodeNoAvailableChannel:    "no available channel",
 	CodeTaskNotFound:          "task not found",
 	CodeBatchNotFound:         "batch not found",
+	CodeIdempotencyConflict:   "idempotency key conflict",
+	CodeFrequencyLimited:      "receiver frequency limit exceeded",
+	CodeOTPCooldown:           "verification code requested too frequently",
+	CodeOTPInvalid:            "invalid verification code",
+	CodeOTPExpired:            "verification code expired",
+	CodeOTPAttemptsExceeded:   "too many verification attempts",
 	CodeInternalError:         "internal server error",
 	CodeDatabaseError:         "database error",
 	CodeRedisError:            "redis error",
@@ -6,24 +6,41 @@ const (
 	MessageTypeEmail      = "email"       // 邮件
 	MessageTypeWeChatWork = "wechat_work" // 企业微信
 	MessageTypeDingTalk   = "dingtalk"    // 钉钉
+	MessageTypeFeishu     = "feishu"      // 飞书
 	MessageTypeWebhook    = "webhook"     // Webhook
 	MessageTypePush       = "push"        // 推送通知
 )
 
+// MessageTypes 全部消息类型
+var MessageTypes = []string{
+	MessageTypeSMS,
+	MessageTypeEmail,
+	MessageTypeWeChatWork,
+	MessageTypeDingTalk,
+	MessageTypeFeishu,
+	MessageTypeWebhook,
+	MessageTypePush,
+}
+
 // 服务商代码常量
 const (
-	ProviderAliyunSMS  = "aliyun_sms"  // 阿里云短信
-	ProviderTencentSMS = "tencent_sms" // 腾讯云短信
-	ProviderZrwinfoSMS = "zrwinfo_sms" // 掌榕网短信
-	ProviderSMTP       = "smtp"        // SMTP邮件
-	ProviderWeChatWork = "wechat_work" // 企业微信
-	ProviderDingTalk   = "dingtalk"    // 钉钉
+	ProviderAliyunSMS       = "aliyun_sms"        // 阿里云短信
+	ProviderTencentSMS      = "tencent_sms"       // 腾讯云短信
+	ProviderZrwinfoSMS      = "zrwinfo_sms"       // 掌榕网短信
+	ProviderSMTP            = "smtp"              // SMTP邮件
+	ProviderWeChatWork      = "wechat_work"       // 企业微信
+	ProviderDingTalk        = "dingtalk"          // 钉钉
+	ProviderWeChatWorkRobot = "wechat_work_robot" // 企业微信群机器人
+	ProviderDingTalkRobot   = "dingtalk_robot"    // 钉钉群机器人
+	ProviderFeishu          = "feishu"            // 飞书
+	ProviderWebhook         = "webhook"           // 通用Webhook
+	ProviderMock            = "mock"              // 模拟服务商（压测、沙箱）
 )
 
 // IsValidMessageType 检查消息类型是否有效
 func IsValidMessageType(msgType string) bool {
 	switch msgType {
-	case MessageTypeSMS, MessageTypeEmail, MessageTypeWeChatWork, MessageTypeDingTalk, MessageTypeWebhook, MessageTypePush:
+	case MessageTypeSMS, MessageTypeEmail, MessageTypeWeChatWork, MessageTypeDingTalk, MessageTypeFeishu, MessageTypeWebhook, MessageTypePush:
 		return true
 	default:
 		return false
@@ -33,7 +50,8 @@ func IsValidMessageType(msgType string) bool {
 // IsValidProviderCode 检查服务商代码是否有效
 func IsValidProviderCode(code string) bool {
 	switch code {
-	case ProviderAliyunSMS, ProviderTencentSMS, ProviderZrwinfoSMS, ProviderSMTP, ProviderWeChatWork, ProviderDingTalk:
+	case ProviderAliyunSMS, ProviderTencentSMS, ProviderZrwinfoSMS, ProviderSMTP, ProviderWeChatWork, ProviderDingTalk,
+		ProviderWeChatWorkRobot, ProviderDingTalkRobot, ProviderFeishu, ProviderWebhook, ProviderMock:
 		return true
 	default:
 		return false
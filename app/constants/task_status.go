@@ -7,6 +7,7 @@ const (
 	TaskStatusSent       = "sent"       // 已发送（短信等待回调）
 	TaskStatusSuccess    = "success"    // 成功
 	TaskStatusFailed     = "failed"     // 失败
+	TaskStatusSuppressed = "suppressed" // 接收者命中屏蔽名单，未发送
 )
 
 // 批量任务状态
@@ -19,7 +20,7 @@ const (
 // IsValidTaskStatus 检查任务状态是否有效
 func IsValidTaskStatus(status string) bool {
 	switch status {
-	case TaskStatusPending, TaskStatusProcessing, TaskStatusSent, TaskStatusSuccess, TaskStatusFailed:
+	case TaskStatusPending, TaskStatusProcessing, TaskStatusSent, TaskStatusSuccess, TaskStatusFailed, TaskStatusSuppressed:
 		return true
 	default:
 		return false
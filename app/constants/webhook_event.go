@@ -0,0 +1,54 @@
+package constants
+
+import "slices"
+
+// WebhookEventVersion Webhook 事件载荷的结构版本
+// 仅在载荷出现不兼容变更时递增，新增字段不改变版本
+const WebhookEventVersion = "1"
+
+// Webhook 事件类型（v1 事件目录）
+const (
+	WebhookEventTaskAccepted   = "task.accepted"   // 任务已受理（入库并进入发送队列）
+	WebhookEventTaskSent       = "task.sent"       // 已提交服务商，等待回执
+	WebhookEventTaskRetrying   = "task.retrying"   // 发送或回执失败，已安排重试
+	WebhookEventTaskDelivered  = "task.delivered"  // 已送达（同步成功或回执确认送达）
+	WebhookEventTaskFailed     = "task.failed"     // 最终失败，不再重试
+	WebhookEventTaskExpired    = "task.expired"    // 等待回执超时
+	WebhookEventTaskSuppressed = "task.suppressed" // 接收者命中屏蔽名单，未发送
+	WebhookEventBatchCompleted = "batch.completed" // 批次完成
+	WebhookEventInbound        = "inbound"         // 收到用户上行回复（短信）
+	WebhookEventTest           = "webhook.test"    // 测试事件（仅手动触发，无需订阅）
+)
+
+// WebhookEvents 可订阅的事件列表
+var WebhookEvents = []string{
+	WebhookEventTaskAccepted,
+	WebhookEventTaskSent,
+	WebhookEventTaskRetrying,
+	WebhookEventTaskDelivered,
+	WebhookEventTaskFailed,
+	WebhookEventTaskExpired,
+	WebhookEventTaskSuppressed,
+	WebhookEventBatchCompleted,
+	WebhookEventInbound,
+}
+
+// legacyWebhookEvents 旧版事件名到事件目录的映射，兼容已有订阅
+var legacyWebhookEvents = map[string]string{
+	"delivered": WebhookEventTaskDelivered,
+	"failed":    WebhookEventTaskFailed,
+	"rejected":  WebhookEventTaskFailed,
+}
+
+// NormalizeWebhookEvent 将旧版事件名转换为事件目录中的名称
+func NormalizeWebhookEvent(event string) string {
+	if normalized, ok := legacyWebhookEvents[event]; ok {
+		return normalized
+	}
+	return event
+}
+
+// IsValidWebhookEvent 是否为可订阅的事件
+func IsValidWebhookEvent(event string) bool {
+	return slices.Contains(WebhookEvents, event)
+}
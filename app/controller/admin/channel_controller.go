@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -192,6 +193,104 @@ func (c ChannelController) DeleteChannelBinding(ctx *gin.Context, helper interfa
 	controller.SuccessResponse(ctx, gin.H{"message": "deleted successfully"})
 }
 
+// GetBindingCircuitBreaker 获取通道绑定的熔断器状态
+func (c ChannelController) GetBindingCircuitBreaker(ctx *gin.Context, helper interfaces.HelperInterface) {
+	adminService := service.NewAdminChannelService()
+	bindingIDStr := ctx.Param("bindingId")
+	bindingID, err := strconv.ParseUint(bindingIDStr, 10, 32)
+	if err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid binding id")
+		return
+	}
+
+	resp, err := adminService.GetBindingCircuitBreaker(uint(bindingID))
+	if err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to get circuit breaker: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// ResetBindingCircuitBreaker 手动重置通道绑定的熔断器
+func (c ChannelController) ResetBindingCircuitBreaker(ctx *gin.Context, helper interfaces.HelperInterface) {
+	adminService := service.NewAdminChannelService()
+	bindingIDStr := ctx.Param("bindingId")
+	bindingID, err := strconv.ParseUint(bindingIDStr, 10, 32)
+	if err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid binding id")
+		return
+	}
+
+	if err := adminService.ResetBindingCircuitBreaker(uint(bindingID)); err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to reset circuit breaker: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, gin.H{"message": "reset successfully"})
+}
+
+// EnableChannelBinding 重新启用被自动停用的通道绑定
+func (c ChannelController) EnableChannelBinding(ctx *gin.Context, helper interfaces.HelperInterface) {
+	adminService := service.NewAdminChannelService()
+	bindingIDStr := ctx.Param("bindingId")
+	bindingID, err := strconv.ParseUint(bindingIDStr, 10, 32)
+	if err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid binding id")
+		return
+	}
+
+	operator := "admin"
+	if username, exists := ctx.Get("username"); exists {
+		operator = fmt.Sprintf("%v", username)
+	}
+
+	if err := adminService.EnableChannelBinding(uint(bindingID), operator); err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to enable channel binding: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, gin.H{"message": "enabled successfully"})
+}
+
+// GetChannelBindingEvents 获取通道绑定事件列表
+// 通过 query 参数 binding_id 可只查询指定绑定
+func (c ChannelController) GetChannelBindingEvents(ctx *gin.Context, helper interfaces.HelperInterface) {
+	adminService := service.NewAdminChannelService()
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid channel id")
+		return
+	}
+
+	var bindingID uint64
+	if bindingIDStr := ctx.Query("binding_id"); bindingIDStr != "" {
+		bindingID, err = strconv.ParseUint(bindingIDStr, 10, 32)
+		if err != nil {
+			controller.ErrorResponse(ctx, 400, "invalid binding id")
+			return
+		}
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	resp, err := adminService.GetChannelBindingEvents(uint(id), uint(bindingID), page, pageSize)
+	if err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to get channel binding events: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
 // GetActiveChannels 获取活跃通道列表
 func (c ChannelController) GetActiveChannels(ctx *gin.Context, helper interfaces.HelperInterface) {
 	adminService := service.NewAdminChannelService()
@@ -0,0 +1,148 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cnb.cool/mliev/push/message-push/app/controller"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/queue"
+	"cnb.cool/mliev/push/message-push/app/service"
+	"cnb.cool/mliev/push/message-push/internal/interfaces"
+)
+
+// DeadLetterController 死信队列管理控制器
+type DeadLetterController struct {
+}
+
+// GetDeadLetterList 获取死信消息列表
+func (c DeadLetterController) GetDeadLetterList(ctx *gin.Context, helper interfaces.HelperInterface) {
+	deadLetterService := service.NewAdminDeadLetterService()
+
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	req := &dto.DeadLetterListRequest{
+		Cursor:   ctx.Query("cursor"),
+		PageSize: pageSize,
+	}
+
+	resp, err := deadLetterService.GetDeadLetterList(ctx.Request.Context(), req)
+	if err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to get dead letters: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// GetDeadLetter 获取单条死信消息详情
+func (c DeadLetterController) GetDeadLetter(ctx *gin.Context, helper interfaces.HelperInterface) {
+	deadLetterService := service.NewAdminDeadLetterService()
+
+	resp, err := deadLetterService.GetDeadLetter(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			controller.ErrorResponse(ctx, 404, "dead letter not found")
+			return
+		}
+		controller.ErrorResponse(ctx, 500, "failed to get dead letter: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// ReplayDeadLetter 重放单条死信消息
+func (c DeadLetterController) ReplayDeadLetter(ctx *gin.Context, helper interfaces.HelperInterface) {
+	deadLetterService := service.NewAdminDeadLetterService()
+
+	// 请求体可选
+	var req dto.DeadLetterReplayRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+			return
+		}
+	}
+
+	if err := deadLetterService.ReplayDeadLetter(ctx.Request.Context(), ctx.Param("id"), req.TargetChannelID); err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			controller.ErrorResponse(ctx, 404, "dead letter not found")
+			return
+		}
+		controller.ErrorResponse(ctx, 500, "failed to replay dead letter: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, nil)
+}
+
+// ReplayDeadLetters 批量重放死信消息
+func (c DeadLetterController) ReplayDeadLetters(ctx *gin.Context, helper interfaces.HelperInterface) {
+	deadLetterService := service.NewAdminDeadLetterService()
+	var req dto.DeadLetterBatchReplayRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := deadLetterService.ReplayDeadLetters(ctx.Request.Context(), &req)
+	if err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to replay dead letters: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// DeleteDeadLetter 删除单条死信消息
+func (c DeadLetterController) DeleteDeadLetter(ctx *gin.Context, helper interfaces.HelperInterface) {
+	deadLetterService := service.NewAdminDeadLetterService()
+
+	if err := deadLetterService.DeleteDeadLetter(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			controller.ErrorResponse(ctx, 404, "dead letter not found")
+			return
+		}
+		controller.ErrorResponse(ctx, 500, "failed to delete dead letter: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, nil)
+}
+
+// TrimDeadLetters 裁剪死信队列
+func (c DeadLetterController) TrimDeadLetters(ctx *gin.Context, helper interfaces.HelperInterface) {
+	deadLetterService := service.NewAdminDeadLetterService()
+	var req dto.DeadLetterTrimRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := deadLetterService.TrimDeadLetters(ctx.Request.Context(), &req)
+	if err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to trim dead letters: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// PurgeDeadLetters 清空死信队列
+func (c DeadLetterController) PurgeDeadLetters(ctx *gin.Context, helper interfaces.HelperInterface) {
+	deadLetterService := service.NewAdminDeadLetterService()
+
+	resp, err := deadLetterService.PurgeDeadLetters(ctx.Request.Context())
+	if err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to purge dead letters: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
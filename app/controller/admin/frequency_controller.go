@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cnb.cool/mliev/push/message-push/app/controller"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/service"
+	"cnb.cool/mliev/push/message-push/internal/interfaces"
+)
+
+// FrequencyController 频率限制白名单管理控制器
+type FrequencyController struct {
+}
+
+// GetAllowlist 获取应用的频率限制白名单
+func (c FrequencyController) GetAllowlist(ctx *gin.Context, helper interfaces.HelperInterface) {
+	frequencyService := service.NewAdminFrequencyService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	resp, err := frequencyService.GetAllowlist(appID, ctx.Query("receiver"), page, pageSize)
+	if err != nil {
+		handleFrequencyError(ctx, "failed to get frequency allowlist", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// AddAllowlist 添加频率限制白名单
+func (c FrequencyController) AddAllowlist(ctx *gin.Context, helper interfaces.HelperInterface) {
+	frequencyService := service.NewAdminFrequencyService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateFrequencyAllowlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := frequencyService.AddAllowlist(appID, &req)
+	if err != nil {
+		handleFrequencyError(ctx, "failed to add frequency allowlist", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// DeleteAllowlist 删除频率限制白名单
+func (c FrequencyController) DeleteAllowlist(ctx *gin.Context, helper interfaces.HelperInterface) {
+	frequencyService := service.NewAdminFrequencyService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+	entryID, ok := parseWebhookIDParam(ctx, "entry_id")
+	if !ok {
+		return
+	}
+
+	if err := frequencyService.DeleteAllowlist(appID, entryID); err != nil {
+		handleFrequencyError(ctx, "failed to delete frequency allowlist", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, nil)
+}
+
+// handleFrequencyError 按错误类型返回对应的状态码
+func handleFrequencyError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, service.ErrApplicationNotFound), errors.Is(err, service.ErrFrequencyAllowlistNotFound):
+		controller.ErrorResponse(ctx, 404, err.Error())
+	case errors.Is(err, service.ErrFrequencyAllowlistExists):
+		controller.ErrorResponse(ctx, 400, err.Error())
+	default:
+		controller.ErrorResponse(ctx, 500, msg+": "+err.Error())
+	}
+}
@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"cnb.cool/mliev/push/message-push/app/controller"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/service"
+	"cnb.cool/mliev/push/message-push/internal/interfaces"
+)
+
+// InboundController 上行消息管理控制器
+type InboundController struct {
+}
+
+// GetInboundMessageList 获取上行消息（用户回复）列表
+func (c InboundController) GetInboundMessageList(ctx *gin.Context, helper interfaces.HelperInterface) {
+	var req dto.InboundMessageListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := service.NewAdminInboundService().GetInboundMessageList(&req)
+	if err != nil {
+		controller.ErrorResponse(ctx, 500, err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
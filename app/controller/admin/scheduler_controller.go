@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"cnb.cool/mliev/push/message-push/app/controller"
+	"cnb.cool/mliev/push/message-push/app/service"
+	"cnb.cool/mliev/push/message-push/internal/interfaces"
+)
+
+// SchedulerController 调度器管理控制器
+type SchedulerController struct {
+}
+
+// GetLeader 获取当前调度器 leader
+func (c SchedulerController) GetLeader(ctx *gin.Context, helper interfaces.HelperInterface) {
+	schedulerService := service.NewAdminSchedulerService()
+
+	resp, err := schedulerService.GetLeader(ctx.Request.Context())
+	if err != nil {
+		controller.ErrorResponse(ctx, 500, "failed to get scheduler leader: "+err.Error())
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
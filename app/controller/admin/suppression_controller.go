@@ -0,0 +1,139 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"cnb.cool/mliev/push/message-push/app/controller"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/service"
+	"cnb.cool/mliev/push/message-push/internal/interfaces"
+)
+
+// SuppressionController 屏蔽名单管理控制器
+type SuppressionController struct {
+}
+
+// GetSuppressionList 获取屏蔽名单列表
+func (c SuppressionController) GetSuppressionList(ctx *gin.Context, helper interfaces.HelperInterface) {
+	var req dto.SuppressionListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := service.NewAdminSuppressionService().GetSuppressionList(&req)
+	if err != nil {
+		handleSuppressionError(ctx, "failed to get suppression list", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// GetSuppression 获取屏蔽记录详情
+func (c SuppressionController) GetSuppression(ctx *gin.Context, helper interfaces.HelperInterface) {
+	id, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := service.NewAdminSuppressionService().GetSuppression(id)
+	if err != nil {
+		handleSuppressionError(ctx, "failed to get suppression", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// CreateSuppression 添加屏蔽记录
+func (c SuppressionController) CreateSuppression(ctx *gin.Context, helper interfaces.HelperInterface) {
+	var req dto.CreateSuppressionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := service.NewAdminSuppressionService().CreateSuppression(&req)
+	if err != nil {
+		handleSuppressionError(ctx, "failed to create suppression", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// UpdateSuppression 更新屏蔽记录
+func (c SuppressionController) UpdateSuppression(ctx *gin.Context, helper interfaces.HelperInterface) {
+	id, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateSuppressionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := service.NewAdminSuppressionService().UpdateSuppression(id, &req)
+	if err != nil {
+		handleSuppressionError(ctx, "failed to update suppression", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// DeleteSuppression 删除屏蔽记录
+func (c SuppressionController) DeleteSuppression(ctx *gin.Context, helper interfaces.HelperInterface) {
+	id, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := service.NewAdminSuppressionService().DeleteSuppression(id); err != nil {
+		handleSuppressionError(ctx, "failed to delete suppression", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, nil)
+}
+
+// ImportSuppressions 通过 CSV 文件批量导入屏蔽记录（multipart 表单字段 file）
+func (c SuppressionController) ImportSuppressions(ctx *gin.Context, helper interfaces.HelperInterface) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		controller.ErrorResponse(ctx, 400, "file is required: "+err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		controller.ErrorResponse(ctx, 400, "failed to open file: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	resp, err := service.NewAdminSuppressionService().ImportSuppressions(file)
+	if err != nil {
+		handleSuppressionError(ctx, "failed to import suppressions", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// handleSuppressionError 按错误类型返回对应的状态码
+func handleSuppressionError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, service.ErrSuppressionNotFound):
+		controller.ErrorResponse(ctx, 404, err.Error())
+	case errors.Is(err, service.ErrSuppressionExists), errors.Is(err, service.ErrInvalidSuppression):
+		controller.ErrorResponse(ctx, 400, err.Error())
+	default:
+		controller.ErrorResponse(ctx, 500, msg+": "+err.Error())
+	}
+}
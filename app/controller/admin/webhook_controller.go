@@ -0,0 +1,214 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cnb.cool/mliev/push/message-push/app/controller"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/service"
+	"cnb.cool/mliev/push/message-push/internal/interfaces"
+)
+
+// WebhookController 应用 Webhook 配置管理控制器
+type WebhookController struct {
+}
+
+// GetWebhookList 获取应用的 Webhook 配置列表
+func (c WebhookController) GetWebhookList(ctx *gin.Context, helper interfaces.HelperInterface) {
+	webhookService := service.NewAdminWebhookService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := webhookService.GetWebhookConfigs(appID)
+	if err != nil {
+		handleWebhookError(ctx, "failed to get webhook configs", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// CreateWebhook 创建 Webhook 配置
+func (c WebhookController) CreateWebhook(ctx *gin.Context, helper interfaces.HelperInterface) {
+	webhookService := service.NewAdminWebhookService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateWebhookConfigRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := webhookService.CreateWebhookConfig(appID, &req)
+	if err != nil {
+		handleWebhookError(ctx, "failed to create webhook config", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// UpdateWebhook 更新 Webhook 配置
+func (c WebhookController) UpdateWebhook(ctx *gin.Context, helper interfaces.HelperInterface) {
+	webhookService := service.NewAdminWebhookService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+	webhookID, ok := parseWebhookIDParam(ctx, "webhook_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookConfigRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := webhookService.UpdateWebhookConfig(appID, webhookID, &req)
+	if err != nil {
+		handleWebhookError(ctx, "failed to update webhook config", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// DeleteWebhook 删除 Webhook 配置
+func (c WebhookController) DeleteWebhook(ctx *gin.Context, helper interfaces.HelperInterface) {
+	webhookService := service.NewAdminWebhookService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+	webhookID, ok := parseWebhookIDParam(ctx, "webhook_id")
+	if !ok {
+		return
+	}
+
+	if err := webhookService.DeleteWebhookConfig(appID, webhookID); err != nil {
+		handleWebhookError(ctx, "failed to delete webhook config", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, nil)
+}
+
+// TestWebhook 发送测试事件
+func (c WebhookController) TestWebhook(ctx *gin.Context, helper interfaces.HelperInterface) {
+	webhookService := service.NewAdminWebhookService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+	webhookID, ok := parseWebhookIDParam(ctx, "webhook_id")
+	if !ok {
+		return
+	}
+
+	resp, err := webhookService.SendTestEvent(ctx.Request.Context(), appID, webhookID)
+	if err != nil {
+		handleWebhookError(ctx, "failed to send test event", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// GetWebhookDeliveries 获取 Webhook 投递记录
+func (c WebhookController) GetWebhookDeliveries(ctx *gin.Context, helper interfaces.HelperInterface) {
+	webhookService := service.NewAdminWebhookService()
+	appID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+	webhookID, ok := parseWebhookIDParam(ctx, "webhook_id")
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	resp, err := webhookService.GetDeliveries(appID, webhookID, ctx.Query("event"), ctx.Query("status"), page, pageSize)
+	if err != nil {
+		handleWebhookError(ctx, "failed to get webhook deliveries", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// RedeliverWebhookLog 重新投递单条 Webhook 记录
+func (c WebhookController) RedeliverWebhookLog(ctx *gin.Context, helper interfaces.HelperInterface) {
+	webhookService := service.NewAdminWebhookService()
+	logID, ok := parseWebhookIDParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	resp, err := webhookService.RedeliverWebhookLog(logID)
+	if err != nil {
+		handleWebhookError(ctx, "failed to redeliver webhook", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// RedeliverFailedWebhookLogs 重新投递时间范围内投递失败的记录
+func (c WebhookController) RedeliverFailedWebhookLogs(ctx *gin.Context, helper interfaces.HelperInterface) {
+	webhookService := service.NewAdminWebhookService()
+	var req dto.WebhookRedeliverRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid request: "+err.Error())
+		return
+	}
+
+	resp, err := webhookService.RedeliverFailedWebhookLogs(&req)
+	if err != nil {
+		handleWebhookError(ctx, "failed to redeliver webhooks", err)
+		return
+	}
+
+	controller.SuccessResponse(ctx, resp)
+}
+
+// parseWebhookIDParam 解析路径中的 ID 参数，失败时直接返回 400
+func parseWebhookIDParam(ctx *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 32)
+	if err != nil {
+		controller.ErrorResponse(ctx, 400, "invalid "+name)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleWebhookError 按错误类型返回对应的状态码
+func handleWebhookError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, service.ErrApplicationNotFound), errors.Is(err, service.ErrWebhookConfigNotFound),
+		errors.Is(err, service.ErrWebhookLogNotFound):
+		controller.ErrorResponse(ctx, 404, err.Error())
+	case errors.Is(err, service.ErrInvalidWebhookEvent), errors.Is(err, service.ErrInvalidTimeRange),
+		errors.Is(err, service.ErrWebhookConfigDisabled):
+		controller.ErrorResponse(ctx, 400, err.Error())
+	default:
+		controller.ErrorResponse(ctx, 500, msg+": "+err.Error())
+	}
+}
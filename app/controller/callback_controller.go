@@ -18,10 +18,39 @@ type CallbackController struct{}
 // Handle 处理服务商回调（动态路由）
 // POST/GET /api/callback/:id
 func (ctrl CallbackController) Handle(c *gin.Context, helper interfaces.HelperInterface) {
+	req, ok := buildCallbackRequest(c, helper)
+	if !ok {
+		return
+	}
+
+	// 处理回调
+	callbackService := service.NewCallbackService()
+	resp := callbackService.HandleCallback(c.Request.Context(), req.ProviderCode, req)
+
+	writeCallbackResponse(c, resp)
+}
+
+// HandleInbound 处理服务商推送的上行消息（用户回复）
+// POST/GET /api/callback/:id/inbound
+func (ctrl CallbackController) HandleInbound(c *gin.Context, helper interfaces.HelperInterface) {
+	req, ok := buildCallbackRequest(c, helper)
+	if !ok {
+		return
+	}
+
+	inboundService := service.NewInboundService()
+	resp := inboundService.HandleInbound(c.Request.Context(), req.ProviderCode, req)
+
+	writeCallbackResponse(c, resp)
+}
+
+// buildCallbackRequest 根据路由中的服务商账号ID和请求内容构造回调请求
+// 失败时已写入响应，返回 false
+func buildCallbackRequest(c *gin.Context, helper interfaces.HelperInterface) (*sender.CallbackRequest, bool) {
 	idStr := c.Param("id")
 	if idStr == "" {
 		c.JSON(200, gin.H{"code": 400, "message": "id is required"})
-		return
+		return nil, false
 	}
 
 	// 将 id 转换为 uint
@@ -29,7 +58,7 @@ func (ctrl CallbackController) Handle(c *gin.Context, helper interfaces.HelperIn
 	if err != nil {
 		helper.GetLogger().Error("invalid id: " + idStr)
 		c.JSON(200, gin.H{"code": 400, "message": "invalid id"})
-		return
+		return nil, false
 	}
 
 	// 通过 id 查找服务商账号
@@ -38,16 +67,14 @@ func (ctrl CallbackController) Handle(c *gin.Context, helper interfaces.HelperIn
 	if err != nil {
 		helper.GetLogger().Error("account not found: " + idStr)
 		c.JSON(200, gin.H{"code": 404, "message": "account not found"})
-		return
+		return nil, false
 	}
 
-	providerCode := account.ProviderCode
-
 	// 读取原始请求体
 	rawBody, err := io.ReadAll(c.Request.Body)
 	if err != nil {
 		c.JSON(200, gin.H{"code": 400, "message": "failed to read request body"})
-		return
+		return nil, false
 	}
 
 	// 重置 Body 以便后续解析表单数据（因为 Body 只能读取一次）
@@ -66,11 +93,12 @@ func (ctrl CallbackController) Handle(c *gin.Context, helper interfaces.HelperIn
 
 	// 构造回调请求
 	req := &sender.CallbackRequest{
-		ProviderCode: providerCode,
-		RawBody:      rawBody,
-		Headers:      make(map[string]string),
-		QueryParams:  make(map[string]string),
-		FormData:     formData,
+		ProviderCode:    account.ProviderCode,
+		ProviderAccount: account,
+		RawBody:         rawBody,
+		Headers:         make(map[string]string),
+		QueryParams:     make(map[string]string),
+		FormData:        formData,
 	}
 
 	// 收集请求头
@@ -87,16 +115,16 @@ func (ctrl CallbackController) Handle(c *gin.Context, helper interfaces.HelperIn
 		}
 	}
 
-	// 处理回调
-	callbackService := service.NewCallbackService()
-	resp := callbackService.HandleCallback(c.Request.Context(), providerCode, req)
+	return req, true
+}
 
+// writeCallbackResponse 返回供应商期望的响应格式
+func writeCallbackResponse(c *gin.Context, resp sender.CallbackResponse) {
 	// 如果状态码为 0，自动设置为 500
 	statusCode := resp.StatusCode
 	if statusCode == 0 {
 		statusCode = 500
 	}
 
-	// 返回供应商期望的响应格式
 	c.String(statusCode, resp.Body)
 }
@@ -1,6 +1,10 @@
 package controller
 
 import (
+	"errors"
+	"strconv"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/dto"
 	"cnb.cool/mliev/push/message-push/app/service"
 	"cnb.cool/mliev/push/message-push/internal/interfaces"
@@ -24,9 +28,13 @@ func (ctrl MessageController) Send(c *gin.Context, helper interfaces.HelperInter
 	appID, _ := c.Get("app_id")
 	req.AppID = appID.(string)
 
+	if !bindIdempotencyKey(c, &req.ClientMsgID) {
+		return
+	}
+
 	resp, err := messageService.Send(c.Request.Context(), &req)
 	if err != nil {
-		FailWithMessage(c, err.Error())
+		failWithSendError(c, err)
 		return
 	}
 
@@ -46,15 +54,46 @@ func (ctrl MessageController) BatchSend(c *gin.Context, helper interfaces.Helper
 	appID, _ := c.Get("app_id")
 	req.AppID = appID.(string)
 
+	if !bindIdempotencyKey(c, &req.ClientMsgID) {
+		return
+	}
+
 	resp, err := messageService.BatchSend(c.Request.Context(), &req)
 	if err != nil {
-		FailWithMessage(c, err.Error())
+		failWithSendError(c, err)
 		return
 	}
 
 	SuccessWithData(c, resp)
 }
 
+// bindIdempotencyKey 合并 Idempotency-Key 请求头与请求体中的 client_msg_id
+// 两者同时存在且不一致时返回错误响应并返回 false
+func bindIdempotencyKey(c *gin.Context, clientMsgID *string) bool {
+	header := c.GetHeader("Idempotency-Key")
+	if header == "" {
+		return true
+	}
+	if *clientMsgID != "" && *clientMsgID != header {
+		FailWithMessage(c, "Idempotency-Key header does not match client_msg_id")
+		return false
+	}
+	*clientMsgID = header
+	return true
+}
+
+// failWithSendError 发送失败响应，幂等键冲突和频率限制使用独立错误码
+func failWithSendError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrIdempotencyKeyConflict), errors.Is(err, service.ErrIdempotencyKeyInProgress):
+		ErrorResponse(c, constants.CodeIdempotencyConflict, err.Error())
+	case errors.Is(err, service.ErrFrequencyLimitExceeded):
+		ErrorResponse(c, constants.CodeFrequencyLimited, err.Error())
+	default:
+		FailWithMessage(c, err.Error())
+	}
+}
+
 // QueryTask 查询任务状态
 func (ctrl MessageController) QueryTask(c *gin.Context, helper interfaces.HelperInterface) {
 	messageService := service.NewMessageService()
@@ -72,3 +111,33 @@ func (ctrl MessageController) QueryTask(c *gin.Context, helper interfaces.Helper
 
 	SuccessWithData(c, task)
 }
+
+// QueryBatch 查询批次进度
+func (ctrl MessageController) QueryBatch(c *gin.Context, helper interfaces.HelperInterface) {
+	messageService := service.NewMessageService()
+	batchID := c.Param("batch_id")
+	if batchID == "" {
+		FailWithMessage(c, "batch_id is required")
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	appID, _ := c.Get("app_id")
+
+	resp, err := messageService.QueryBatch(c.Request.Context(), appID.(string), batchID, c.Query("status"), page, pageSize)
+	if err != nil {
+		FailWithMessage(c, err.Error())
+		return
+	}
+
+	SuccessWithData(c, resp)
+}
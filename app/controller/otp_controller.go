@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"errors"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/service"
+	"cnb.cool/mliev/push/message-push/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+// OTPController 验证码控制器
+type OTPController struct {
+}
+
+// Send 发送验证码
+func (ctrl OTPController) Send(c *gin.Context, helper interfaces.HelperInterface) {
+	var req dto.OTPSendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		FailWithMessage(c, "invalid request: "+err.Error())
+		return
+	}
+
+	// 从上下文获取认证信息（已由中间件验证）
+	appID, _ := c.Get("app_id")
+	req.AppID = appID.(string)
+
+	resp, err := service.NewOTPService().Send(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrOTPCooldown) {
+			ErrorResponse(c, constants.CodeOTPCooldown, err.Error())
+			return
+		}
+		failWithSendError(c, err)
+		return
+	}
+
+	SuccessWithData(c, resp)
+}
+
+// Verify 校验验证码
+func (ctrl OTPController) Verify(c *gin.Context, helper interfaces.HelperInterface) {
+	var req dto.OTPVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		FailWithMessage(c, "invalid request: "+err.Error())
+		return
+	}
+
+	// 从上下文获取认证信息（已由中间件验证）
+	appID, _ := c.Get("app_id")
+	req.AppID = appID.(string)
+
+	if err := service.NewOTPService().Verify(c.Request.Context(), &req); err != nil {
+		switch {
+		case errors.Is(err, service.ErrOTPInvalid):
+			ErrorResponse(c, constants.CodeOTPInvalid, err.Error())
+		case errors.Is(err, service.ErrOTPExpired):
+			ErrorResponse(c, constants.CodeOTPExpired, err.Error())
+		case errors.Is(err, service.ErrOTPAttemptsExceeded):
+			ErrorResponse(c, constants.CodeOTPAttemptsExceeded, err.Error())
+		default:
+			FailWithMessage(c, err.Error())
+		}
+		return
+	}
+
+	SuccessWithData(c, &dto.OTPVerifyResponse{Verified: true})
+}
@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"gorm.io/gorm"
+)
+
+// ChannelBindingEventDAO 通道绑定事件数据访问对象
+type ChannelBindingEventDAO struct {
+	db *gorm.DB
+}
+
+// NewChannelBindingEventDAO 创建 ChannelBindingEventDAO
+func NewChannelBindingEventDAO() *ChannelBindingEventDAO {
+	return &ChannelBindingEventDAO{
+		db: helper.GetHelper().GetDatabase(),
+	}
+}
+
+// Create 创建事件记录
+func (dao *ChannelBindingEventDAO) Create(event *model.ChannelBindingEvent) error {
+	return dao.db.Create(event).Error
+}
+
+// ListByChannelID 获取通道的事件记录（分页）
+// bindingID 为 0 时返回通道下所有绑定的事件
+func (dao *ChannelBindingEventDAO) ListByChannelID(channelID uint, bindingID uint, page, pageSize int) ([]*model.ChannelBindingEvent, int64, error) {
+	var events []*model.ChannelBindingEvent
+	var total int64
+
+	query := dao.db.Model(&model.ChannelBindingEvent{}).Where("channel_id = ?", channelID)
+	if bindingID > 0 {
+		query = query.Where("binding_id = ?", bindingID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).
+		Order("created_at DESC").
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
@@ -78,3 +78,12 @@ func (dao *ChannelSignatureMappingDAO) GetByChannelIDAndSignatureName(channelID
 	}
 	return mapping.ProviderSignature, nil
 }
+
+// GetByProviderIDAndSignatureCode 根据供应商账号ID和供应商签名代码获取签名映射（用于将上行回复的签名关联到通道）
+func (dao *ChannelSignatureMappingDAO) GetByProviderIDAndSignatureCode(providerID uint, signatureCode string) ([]model.ChannelSignatureMapping, error) {
+	var mappings []model.ChannelSignatureMapping
+	err := dao.db.Joins("JOIN provider_signatures ON provider_signatures.id = channel_signature_mappings.provider_signature_id").
+		Where("channel_signature_mappings.provider_id = ? AND provider_signatures.signature_code = ?", providerID, signatureCode).
+		Find(&mappings).Error
+	return mappings, err
+}
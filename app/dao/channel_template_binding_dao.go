@@ -73,6 +73,17 @@ func (d *ChannelTemplateBindingDAO) GetActiveByChannelID(channelID uint) ([]*mod
 	return bindings, nil
 }
 
+// GetChannelIDsByExtendCode 查询指定服务商账号下使用该上行扩展码的通道ID
+func (d *ChannelTemplateBindingDAO) GetChannelIDsByExtendCode(providerID uint, extendCode string) ([]uint, error) {
+	var channelIDs []uint
+	db := helper.GetHelper().GetDatabase()
+	err := db.Model(&model.ChannelTemplateBinding{}).
+		Where("provider_id = ? AND extend_code = ?", providerID, extendCode).
+		Distinct().
+		Pluck("channel_id", &channelIDs).Error
+	return channelIDs, err
+}
+
 // Update 更新配置
 func (d *ChannelTemplateBindingDAO) Update(id uint, updates map[string]interface{}) error {
 	db := helper.GetHelper().GetDatabase()
@@ -103,6 +114,18 @@ func (d *ChannelTemplateBindingDAO) UpdateIsActive(id uint, isActive int8) error
 	return db.Model(&model.ChannelTemplateBinding{}).Where("id = ?", id).Update("is_active", isActive).Error
 }
 
+// DeactivateIfActive 将激活中的配置置为未激活（条件更新，多实例下仅一个调用方返回 true）
+func (d *ChannelTemplateBindingDAO) DeactivateIfActive(id uint) (bool, error) {
+	db := helper.GetHelper().GetDatabase()
+	result := db.Model(&model.ChannelTemplateBinding{}).
+		Where("id = ? AND is_active = ?", id, 1).
+		Update("is_active", 0)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // Delete 删除配置
 func (d *ChannelTemplateBindingDAO) Delete(id uint) error {
 	db := helper.GetHelper().GetDatabase()
@@ -0,0 +1,66 @@
+package dao
+
+import (
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"gorm.io/gorm"
+)
+
+// FrequencyAllowlistDAO 频率限制白名单数据访问对象
+type FrequencyAllowlistDAO struct {
+	db *gorm.DB
+}
+
+// NewFrequencyAllowlistDAO 创建 FrequencyAllowlistDAO
+func NewFrequencyAllowlistDAO() *FrequencyAllowlistDAO {
+	return &FrequencyAllowlistDAO{
+		db: helper.GetHelper().GetDatabase(),
+	}
+}
+
+// Create 添加白名单
+func (dao *FrequencyAllowlistDAO) Create(entry *model.FrequencyAllowlist) error {
+	return dao.db.Create(entry).Error
+}
+
+// DeleteByIDAndAppID 删除属于指定应用的白名单
+func (dao *FrequencyAllowlistDAO) DeleteByIDAndAppID(id uint, appID string) (int64, error) {
+	result := dao.db.Where("id = ? AND app_id = ?", id, appID).Delete(&model.FrequencyAllowlist{})
+	return result.RowsAffected, result.Error
+}
+
+// ExistsByAppIDAndReceiver 接收者是否已在应用白名单中
+func (dao *FrequencyAllowlistDAO) ExistsByAppIDAndReceiver(appID, receiver string) (bool, error) {
+	var count int64
+	err := dao.db.Model(&model.FrequencyAllowlist{}).
+		Where("app_id = ? AND receiver = ?", appID, receiver).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListByAppID 分页获取应用白名单，receiver 不为空时模糊匹配
+func (dao *FrequencyAllowlistDAO) ListByAppID(appID, receiver string, page, pageSize int) ([]*model.FrequencyAllowlist, int64, error) {
+	var entries []*model.FrequencyAllowlist
+	var total int64
+
+	query := dao.db.Model(&model.FrequencyAllowlist{}).Where("app_id = ?", appID)
+	if receiver != "" {
+		query = query.Where("receiver LIKE ?", "%"+receiver+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&entries).Error
+	return entries, total, err
+}
+
+// GetAllowedReceivers 返回 receivers 中位于应用白名单的接收者
+func (dao *FrequencyAllowlistDAO) GetAllowedReceivers(appID string, receivers []string) ([]string, error) {
+	var allowed []string
+	err := dao.db.Model(&model.FrequencyAllowlist{}).
+		Where("app_id = ? AND receiver IN ?", appID, receivers).
+		Pluck("receiver", &allowed).Error
+	return allowed, err
+}
@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InboundMessageDAO 上行消息数据访问对象
+type InboundMessageDAO struct {
+	db *gorm.DB
+}
+
+// NewInboundMessageDAO 创建 InboundMessageDAO
+func NewInboundMessageDAO() *InboundMessageDAO {
+	return &InboundMessageDAO{
+		db: helper.GetHelper().GetDatabase(),
+	}
+}
+
+// CreateIfAbsent 保存上行消息，同一服务商账号下序列号已存在（服务商重复推送）时不保存并返回 false
+func (dao *InboundMessageDAO) CreateIfAbsent(msg *model.InboundMessage) (bool, error) {
+	result := dao.db.Clauses(clause.OnConflict{DoNothing: true}).Create(msg)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// List 获取上行消息列表（分页），phone 模糊匹配，其余条件精确匹配
+func (dao *InboundMessageDAO) List(page, pageSize int, filters map[string]interface{}) ([]*model.InboundMessage, int64, error) {
+	var messages []*model.InboundMessage
+	var total int64
+
+	query := dao.db.Model(&model.InboundMessage{})
+	if phone, ok := filters["phone"]; ok {
+		query = query.Where("phone LIKE ?", "%"+phone.(string)+"%")
+	}
+	if appID, ok := filters["app_id"]; ok {
+		query = query.Where("app_id = ?", appID)
+	}
+	if taskID, ok := filters["task_id"]; ok {
+		query = query.Where("task_id = ?", taskID)
+	}
+	if providerAccountID, ok := filters["provider_account_id"]; ok {
+		query = query.Where("provider_account_id = ?", providerAccountID)
+	}
+	if unsubscribe, ok := filters["unsubscribe"]; ok {
+		query = query.Where("unsubscribe = ?", unsubscribe)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&messages).Error
+	return messages, total, err
+}
@@ -52,6 +52,51 @@ func (d *PushBatchTaskDAO) IncrementFailed(batchID string) error {
 		UpdateColumn("failed_count", gorm.Expr("failed_count + ?", 1)).Error
 }
 
+// UpdateCounts 更新批次计数
+func (d *PushBatchTaskDAO) UpdateCounts(batchID string, successCount, failedCount, pendingCount int) error {
+	return d.db.Model(&model.PushBatchTask{}).
+		Where("batch_id = ?", batchID).
+		Updates(map[string]interface{}{
+			"success_count": successCount,
+			"failed_count":  failedCount,
+			"pending_count": pendingCount,
+		}).Error
+}
+
+// AdjustCounts 原子地调整批次计数（增量可为负数）
+func (d *PushBatchTaskDAO) AdjustCounts(batchID string, successDelta, failedDelta, pendingDelta int) error {
+	return d.db.Model(&model.PushBatchTask{}).
+		Where("batch_id = ?", batchID).
+		UpdateColumns(map[string]interface{}{
+			"success_count": gorm.Expr("success_count + ?", successDelta),
+			"failed_count":  gorm.Expr("failed_count + ?", failedDelta),
+			"pending_count": gorm.Expr("pending_count + ?", pendingDelta),
+		}).Error
+}
+
+// CompleteIfDone 批次处于处理中且没有未结束的任务时结束批次，返回是否更新成功（多实例下保证只结束一次）
+// 全部失败标记为 failedStatus，否则为 completedStatus
+func (d *PushBatchTaskDAO) CompleteIfDone(batchID, processingStatus, completedStatus, failedStatus string) (bool, error) {
+	result := d.db.Model(&model.PushBatchTask{}).
+		Where("batch_id = ? AND status = ? AND pending_count <= 0", batchID, processingStatus).
+		Update("status", gorm.Expr("CASE WHEN success_count = 0 AND failed_count > 0 THEN ? ELSE ? END", failedStatus, completedStatus))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateStatusIf 仅当批次处于 fromStatus 时更新状态，返回是否更新成功（多实例下保证状态切换只发生一次）
+func (d *PushBatchTaskDAO) UpdateStatusIf(batchID, fromStatus, toStatus string) (bool, error) {
+	result := d.db.Model(&model.PushBatchTask{}).
+		Where("batch_id = ? AND status = ?", batchID, fromStatus).
+		Update("status", toStatus)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // List 获取批量任务列表
 func (d *PushBatchTaskDAO) List(page, pageSize int, filters map[string]interface{}) ([]*model.PushBatchTask, int64, error) {
 	var batches []*model.PushBatchTask
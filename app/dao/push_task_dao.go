@@ -3,6 +3,7 @@ package dao
 import (
 	"time"
 
+	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/model"
 	"cnb.cool/mliev/push/message-push/internal/helper"
 	"gorm.io/gorm"
@@ -50,6 +51,19 @@ func (d *PushTaskDAO) Update(task *model.PushTask) error {
 	return d.db.Save(task).Error
 }
 
+// ClaimForSend 认领任务进行发送：仅当任务为 pending，或为 processing 且在 staleBefore 之前更新（处理方已异常退出）时
+// 将状态更新为 processing，返回是否认领成功（多个消费者处理同一任务时只有一个能认领）
+func (d *PushTaskDAO) ClaimForSend(taskID string, staleBefore time.Time) (bool, error) {
+	result := d.db.Model(&model.PushTask{}).
+		Where("task_id = ?", taskID).
+		Where("status = ? OR (status = ? AND updated_at < ?)", constants.TaskStatusPending, constants.TaskStatusProcessing, staleBefore).
+		Update("status", constants.TaskStatusProcessing)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // UpdateStatus 更新任务状态
 func (d *PushTaskDAO) UpdateStatus(taskID, status string) error {
 	return d.db.Model(&model.PushTask{}).
@@ -102,12 +116,68 @@ func (d *PushTaskDAO) IncrementRetryCount(taskID string) error {
 		UpdateColumn("retry_count", gorm.Expr("retry_count + 1")).Error
 }
 
-// GetTimeoutSentTasks 获取超时的 sent 状态短信任务
+// ClearNextRetryAt 清除下次重试时间
+func (d *PushTaskDAO) ClearNextRetryAt(taskID string) error {
+	return d.db.Model(&model.PushTask{}).
+		Where("task_id = ?", taskID).
+		UpdateColumn("next_retry_at", nil).Error
+}
+
+// GetTimeoutSentTasks 获取超时的 sent 状态短信任务（已标记回调超时的任务除外）
 func (d *PushTaskDAO) GetTimeoutSentTasks(timeout time.Duration, limit int) ([]*model.PushTask, error) {
 	var tasks []*model.PushTask
 	cutoff := time.Now().Add(-timeout)
 	err := d.db.Where("status = ? AND message_type = ? AND updated_at < ?",
 		"sent", "sms", cutoff).
+		Where("callback_status IS NULL OR callback_status <> ?", constants.CallbackStatusTimeout).
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// MarkCallbackTimeout 仅当任务仍为 sent 状态且未标记回调超时时标记为超时，返回是否更新成功（保证超时只处理一次）
+func (d *PushTaskDAO) MarkCallbackTimeout(taskID string, callbackTime time.Time) (bool, error) {
+	result := d.db.Model(&model.PushTask{}).
+		Where("task_id = ? AND status = ?", taskID, constants.TaskStatusSent).
+		Where("callback_status IS NULL OR callback_status <> ?", constants.CallbackStatusTimeout).
+		Updates(map[string]interface{}{
+			"callback_status": constants.CallbackStatusTimeout,
+			"callback_time":   callbackTime,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetUnconfirmedSentTasks 获取已发送但未收到最终回执的任务（按ID递增，用于状态对账轮询）
+// 仅返回创建时间在 [createdAfter, createdBefore) 范围内且 ID 大于 afterID 的任务
+func (d *PushTaskDAO) GetUnconfirmedSentTasks(afterID uint, createdAfter, createdBefore time.Time, limit int) ([]*model.PushTask, error) {
+	var tasks []*model.PushTask
+	err := d.db.Where("status = ? AND id > ? AND created_at >= ? AND created_at < ?",
+		constants.TaskStatusSent, afterID, createdAfter, createdBefore).
+		Where("callback_status IS NULL OR callback_status IN ?",
+			[]string{"", "pending", constants.CallbackStatusTimeout}).
+		Order("id ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetRecentByReceiversAndAccount 获取近期通过指定服务商账号成功提交给这些接收者的任务（按创建时间倒序，用于关联上行回复）
+func (d *PushTaskDAO) GetRecentByReceiversAndAccount(receivers []string, providerAccountID uint, since time.Time, limit int) ([]*model.PushTask, error) {
+	var tasks []*model.PushTask
+	err := d.db.Where("receiver IN ? AND created_at >= ?", receivers, since).
+		Where("task_id IN (?)", d.db.Model(&model.PushLog{}).
+			Select("task_id").
+			Where("provider_account_id = ? AND status = ? AND created_at >= ?", providerAccountID, "success", since)).
+		Order("created_at DESC").
 		Limit(limit).
 		Find(&tasks).Error
 	if err != nil {
@@ -116,6 +186,28 @@ func (d *PushTaskDAO) GetTimeoutSentTasks(timeout time.Duration, limit int) ([]*
 	return tasks, nil
 }
 
+// CountByBatchIDGroupByStatus 按状态统计批次下的任务数量
+func (d *PushTaskDAO) CountByBatchIDGroupByStatus(batchID string) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := d.db.Model(&model.PushTask{}).
+		Select("status, COUNT(*) AS count").
+		Where("batch_id = ?", batchID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
 // List 获取任务列表（分页）
 func (d *PushTaskDAO) List(page, pageSize int, filters map[string]interface{}) ([]*model.PushTask, int64, error) {
 	var tasks []*model.PushTask
@@ -140,6 +232,9 @@ func (d *PushTaskDAO) List(page, pageSize int, filters map[string]interface{}) (
 	if batchID, ok := filters["batch_id"]; ok {
 		query = query.Where("batch_id = ?", batchID)
 	}
+	if idempotencyKey, ok := filters["idempotency_key"]; ok {
+		query = query.Where("idempotency_key = ?", idempotencyKey)
+	}
 	if startDate, ok := filters["start_date"]; ok {
 		query = query.Where("DATE(created_at) >= ?", startDate)
 	}
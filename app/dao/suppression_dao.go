@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SuppressionDAO 屏蔽名单数据访问对象
+type SuppressionDAO struct {
+	db *gorm.DB
+}
+
+// NewSuppressionDAO 创建 SuppressionDAO
+func NewSuppressionDAO() *SuppressionDAO {
+	return &SuppressionDAO{
+		db: helper.GetHelper().GetDatabase(),
+	}
+}
+
+// Create 添加屏蔽记录
+func (dao *SuppressionDAO) Create(entry *model.Suppression) error {
+	return dao.db.Create(entry).Error
+}
+
+// Update 更新屏蔽记录
+func (dao *SuppressionDAO) Update(entry *model.Suppression) error {
+	return dao.db.Save(entry).Error
+}
+
+// Delete 删除屏蔽记录
+func (dao *SuppressionDAO) Delete(id uint) (int64, error) {
+	result := dao.db.Delete(&model.Suppression{}, id)
+	return result.RowsAffected, result.Error
+}
+
+// GetByID 根据ID获取屏蔽记录
+func (dao *SuppressionDAO) GetByID(id uint) (*model.Suppression, error) {
+	var entry model.Suppression
+	if err := dao.db.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Exists 同一接收者在同一范围内是否已有屏蔽记录
+func (dao *SuppressionDAO) Exists(receiver, scope, appID string, channelID uint) (bool, error) {
+	var count int64
+	err := dao.db.Model(&model.Suppression{}).
+		Where("receiver = ? AND scope = ? AND app_id = ? AND channel_id = ?", receiver, scope, appID, channelID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// List 获取屏蔽名单列表（分页），receiver 模糊匹配，其余条件精确匹配
+func (dao *SuppressionDAO) List(page, pageSize int, filters map[string]interface{}) ([]*model.Suppression, int64, error) {
+	var entries []*model.Suppression
+	var total int64
+
+	query := dao.db.Model(&model.Suppression{})
+	if receiver, ok := filters["receiver"]; ok {
+		query = query.Where("receiver LIKE ?", "%"+receiver.(string)+"%")
+	}
+	if suppressionType, ok := filters["type"]; ok {
+		query = query.Where("type = ?", suppressionType)
+	}
+	if scope, ok := filters["scope"]; ok {
+		query = query.Where("scope = ?", scope)
+	}
+	if appID, ok := filters["app_id"]; ok {
+		query = query.Where("app_id = ?", appID)
+	}
+	if channelID, ok := filters["channel_id"]; ok {
+		query = query.Where("channel_id = ?", channelID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&entries).Error
+	return entries, total, err
+}
+
+// Upsert 批量写入屏蔽记录，同一接收者和范围已存在时覆盖类型、原因、来源和过期时间
+func (dao *SuppressionDAO) Upsert(entries []*model.Suppression) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return dao.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "receiver"}, {Name: "scope"}, {Name: "app_id"}, {Name: "channel_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type", "reason", "source", "expires_at", "updated_at"}),
+	}).CreateInBatches(entries, 500).Error
+}
+
+// GetActiveByReceivers 获取对指定应用和通道生效的屏蔽记录（全局、应用或通道范围，且未过期）
+func (dao *SuppressionDAO) GetActiveByReceivers(appID string, channelID uint, receivers []string) ([]*model.Suppression, error) {
+	var entries []*model.Suppression
+	err := dao.db.
+		Where("receiver IN ?", receivers).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Where(dao.db.Where("scope = ?", model.SuppressionScopeGlobal).
+			Or("scope = ? AND app_id = ?", model.SuppressionScopeApp, appID).
+			Or("scope = ? AND channel_id = ?", model.SuppressionScopeChannel, channelID)).
+		Find(&entries).Error
+	return entries, err
+}
@@ -28,6 +28,11 @@ func (dao *WebhookConfigDAO) Update(config *model.WebhookConfig) error {
 	return dao.db.Save(config).Error
 }
 
+// UpdateStatus 更新 Webhook 配置状态
+func (dao *WebhookConfigDAO) UpdateStatus(id uint, status int) error {
+	return dao.db.Model(&model.WebhookConfig{}).Where("id = ?", id).Update("status", status).Error
+}
+
 // Delete 删除 Webhook 配置
 func (dao *WebhookConfigDAO) Delete(id uint) error {
 	return dao.db.Delete(&model.WebhookConfig{}, id).Error
@@ -53,16 +58,30 @@ func (dao *WebhookConfigDAO) GetByAppID(appID string) (*model.WebhookConfig, err
 	return &config, nil
 }
 
-// GetEnabledByAppID 根据应用 ID 获取已启用的 Webhook 配置
-func (dao *WebhookConfigDAO) GetEnabledByAppID(appID string) (*model.WebhookConfig, error) {
+// GetByIDAndAppID 根据 ID 获取指定应用的 Webhook 配置
+func (dao *WebhookConfigDAO) GetByIDAndAppID(id uint, appID string) (*model.WebhookConfig, error) {
 	var config model.WebhookConfig
-	err := dao.db.Where("app_id = ? AND status = 1", appID).First(&config).Error
+	err := dao.db.Where("id = ? AND app_id = ?", id, appID).First(&config).Error
 	if err != nil {
 		return nil, err
 	}
 	return &config, nil
 }
 
+// ListByAppID 获取应用的所有 Webhook 配置
+func (dao *WebhookConfigDAO) ListByAppID(appID string) ([]*model.WebhookConfig, error) {
+	var configs []*model.WebhookConfig
+	err := dao.db.Where("app_id = ?", appID).Order("id ASC").Find(&configs).Error
+	return configs, err
+}
+
+// ListEnabledByAppID 获取应用所有已启用的 Webhook 配置
+func (dao *WebhookConfigDAO) ListEnabledByAppID(appID string) ([]*model.WebhookConfig, error) {
+	var configs []*model.WebhookConfig
+	err := dao.db.Where("app_id = ? AND status = 1", appID).Order("id ASC").Find(&configs).Error
+	return configs, err
+}
+
 // List 获取所有 Webhook 配置
 func (dao *WebhookConfigDAO) List() ([]*model.WebhookConfig, error) {
 	var configs []*model.WebhookConfig
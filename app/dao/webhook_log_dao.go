@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"time"
+
 	"cnb.cool/mliev/push/message-push/app/model"
 	"cnb.cool/mliev/push/message-push/internal/helper"
 	"gorm.io/gorm"
@@ -23,6 +25,73 @@ func (dao *WebhookLogDAO) Create(log *model.WebhookLog) error {
 	return dao.db.Create(log).Error
 }
 
+// BatchCreate 批量创建Webhook日志
+func (dao *WebhookLogDAO) BatchCreate(logs []*model.WebhookLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return dao.db.CreateInBatches(logs, 500).Error
+}
+
+// Update 更新Webhook日志
+func (dao *WebhookLogDAO) Update(log *model.WebhookLog) error {
+	return dao.db.Save(log).Error
+}
+
+// GetByID 根据ID获取Webhook日志
+func (dao *WebhookLogDAO) GetByID(id uint) (*model.WebhookLog, error) {
+	var log model.WebhookLog
+	if err := dao.db.First(&log, id).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// ClaimDue 认领到期的待投递记录
+// 通过条件更新将 next_retry_at 推迟一个租约时长，多实例下同一记录只会被一个投递器认领；
+// 投递器崩溃时租约到期后记录会被重新认领
+func (dao *WebhookLogDAO) ClaimDue(now time.Time, lease time.Duration, limit int) ([]*model.WebhookLog, error) {
+	var logs []*model.WebhookLog
+	err := dao.db.Where("status = ? AND next_retry_at <= ?", model.WebhookLogStatusPending, now).
+		Order("next_retry_at ASC").
+		Limit(limit).
+		Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	leaseUntil := now.Add(lease)
+	claimed := make([]*model.WebhookLog, 0, len(logs))
+	for _, log := range logs {
+		result := dao.db.Model(&model.WebhookLog{}).
+			Where("id = ? AND status = ? AND next_retry_at = ?", log.ID, model.WebhookLogStatusPending, log.NextRetryAt).
+			Update("next_retry_at", leaseUntil)
+		if result.Error != nil {
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 1 {
+			log.NextRetryAt = &leaseUntil
+			claimed = append(claimed, log)
+		}
+	}
+
+	return claimed, nil
+}
+
+// GetFailedByTimeRange 获取时间范围内投递失败的记录
+func (dao *WebhookLogDAO) GetFailedByTimeRange(startTime, endTime time.Time, appID string, configID uint, limit int) ([]*model.WebhookLog, error) {
+	var logs []*model.WebhookLog
+	query := dao.db.Where("status = ? AND created_at >= ? AND created_at <= ?", model.WebhookLogStatusFailed, startTime, endTime)
+	if appID != "" {
+		query = query.Where("app_id = ?", appID)
+	}
+	if configID > 0 {
+		query = query.Where("webhook_config_id = ?", configID)
+	}
+	err := query.Order("id ASC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
 // GetByTaskID 根据任务ID获取Webhook日志
 func (dao *WebhookLogDAO) GetByTaskID(taskID string) ([]*model.WebhookLog, error) {
 	var logs []*model.WebhookLog
@@ -47,6 +116,35 @@ func (dao *WebhookLogDAO) GetByWebhookConfigID(configID uint) ([]*model.WebhookL
 	return logs, nil
 }
 
+// ListByWebhookConfigID 获取指定Webhook配置的投递记录（分页）
+func (dao *WebhookLogDAO) ListByWebhookConfigID(configID uint, event, status string, page, pageSize int) ([]*model.WebhookLog, int64, error) {
+	var logs []*model.WebhookLog
+	var total int64
+
+	query := dao.db.Model(&model.WebhookLog{}).Where("webhook_config_id = ?", configID)
+	if event != "" {
+		query = query.Where("event = ?", event)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).
+		Order("id DESC").
+		Find(&logs).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
 // List 获取Webhook日志列表（分页）
 func (dao *WebhookLogDAO) List(appID string, status string, page, pageSize int) ([]*model.WebhookLog, int64, error) {
 	var logs []*model.WebhookLog
@@ -16,26 +16,36 @@ type ParamMappingItem struct {
 	Value       string           `json:"value"`        // 固定值（type=fixed时使用）
 }
 
+// FrequencyLimitItem 接收者频率限制规则
+type FrequencyLimitItem struct {
+	Window int `json:"window" binding:"required,min=1"` // 时间窗口（秒）
+	Limit  int `json:"limit" binding:"required,min=1"`  // 窗口内同一接收者最多发送条数
+}
+
 // CreateApplicationRequest 创建应用请求
 type CreateApplicationRequest struct {
-	Name        string `json:"name" binding:"required,min=2,max=50"`
-	Description string `json:"description" binding:"max=200"`
-	Status      int    `json:"status" binding:"omitempty,oneof=1 2"`  // 1:启用 2:禁用
-	DailyQuota  *int   `json:"daily_quota" binding:"omitempty,min=0"` // 使用指针，nil使用默认值，0表示不限制
-	RateLimit   *int   `json:"rate_limit" binding:"omitempty,min=0"`  // 使用指针，nil使用默认值，0表示不限制
-	IPWhitelist string `json:"ip_whitelist"`                          // IP白名单，换行分隔，支持IP和CIDR子网格式
-	WebhookURL  string `json:"webhook_url" binding:"omitempty,url"`
+	Name            string               `json:"name" binding:"required,min=2,max=50"`
+	Description     string               `json:"description" binding:"max=200"`
+	Status          int                  `json:"status" binding:"omitempty,oneof=1 2"`  // 1:启用 2:禁用
+	DailyQuota      *int                 `json:"daily_quota" binding:"omitempty,min=0"` // 使用指针，nil使用默认值，0表示不限制
+	RateLimit       *int                 `json:"rate_limit" binding:"omitempty,min=0"`  // 使用指针，nil使用默认值，0表示不限制
+	IPWhitelist     string               `json:"ip_whitelist"`                          // IP白名单，换行分隔，支持IP和CIDR子网格式
+	WebhookURL      string               `json:"webhook_url" binding:"omitempty,url"`
+	Sandbox         bool                 `json:"sandbox"`                                   // 沙箱模式：所有消息由模拟服务商处理
+	FrequencyLimits []FrequencyLimitItem `json:"frequency_limits" binding:"omitempty,dive"` // 接收者频率限制，如 60秒1条、3600秒5条
 }
 
 // UpdateApplicationRequest 更新应用请求
 type UpdateApplicationRequest struct {
-	Name        string `json:"name" binding:"omitempty,min=2,max=50"`
-	Description string `json:"description" binding:"omitempty,max=200"`
-	Status      int    `json:"status" binding:"omitempty,oneof=1 2"`
-	DailyQuota  *int   `json:"daily_quota" binding:"omitempty,min=0"` // 使用指针，nil表示不更新，0表示不限制
-	RateLimit   *int   `json:"rate_limit" binding:"omitempty,min=0"`  // 使用指针，nil表示不更新，0表示不限制
-	IPWhitelist string `json:"ip_whitelist"`                          // IP白名单，换行分隔，支持IP和CIDR子网格式
-	WebhookURL  string `json:"webhook_url" binding:"omitempty"`
+	Name            string               `json:"name" binding:"omitempty,min=2,max=50"`
+	Description     string               `json:"description" binding:"omitempty,max=200"`
+	Status          int                  `json:"status" binding:"omitempty,oneof=1 2"`
+	DailyQuota      *int                 `json:"daily_quota" binding:"omitempty,min=0"` // 使用指针，nil表示不更新，0表示不限制
+	RateLimit       *int                 `json:"rate_limit" binding:"omitempty,min=0"`  // 使用指针，nil表示不更新，0表示不限制
+	IPWhitelist     string               `json:"ip_whitelist"`                          // IP白名单，换行分隔，支持IP和CIDR子网格式
+	WebhookURL      string               `json:"webhook_url" binding:"omitempty"`
+	Sandbox         *bool                `json:"sandbox"`                                   // 使用指针，nil表示不更新
+	FrequencyLimits []FrequencyLimitItem `json:"frequency_limits" binding:"omitempty,dive"` // nil表示不更新，空数组表示清除限制
 }
 
 // ApplicationListRequest 应用列表请求
@@ -48,18 +58,20 @@ type ApplicationListRequest struct {
 
 // ApplicationResponse 应用响应
 type ApplicationResponse struct {
-	ID          uint   `json:"id"`
-	AppName     string `json:"app_name"`
-	Description string `json:"description"`
-	AppID       string `json:"app_id"`
-	AppSecret   string `json:"app_secret,omitempty"` // 仅创建时返回明文
-	Status      int    `json:"status"`
-	DailyQuota  int    `json:"daily_quota"`
-	RateLimit   int    `json:"rate_limit"`
-	IPWhitelist string `json:"ip_whitelist"`
-	WebhookURL  string `json:"webhook_url"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID              uint                 `json:"id"`
+	AppName         string               `json:"app_name"`
+	Description     string               `json:"description"`
+	AppID           string               `json:"app_id"`
+	AppSecret       string               `json:"app_secret,omitempty"` // 仅创建时返回明文
+	Status          int                  `json:"status"`
+	DailyQuota      int                  `json:"daily_quota"`
+	RateLimit       int                  `json:"rate_limit"`
+	IPWhitelist     string               `json:"ip_whitelist"`
+	WebhookURL      string               `json:"webhook_url"`
+	Sandbox         bool                 `json:"sandbox"`
+	FrequencyLimits []FrequencyLimitItem `json:"frequency_limits"`
+	CreatedAt       string               `json:"created_at"`
+	UpdatedAt       string               `json:"updated_at"`
 }
 
 // ApplicationListResponse 应用列表响应
@@ -85,6 +97,7 @@ type RegenerateSecretResponse struct {
 type CreateProviderAccountRequest struct {
 	Name         string                 `json:"name" binding:"required,min=2,max=50"`
 	ProviderCode string                 `json:"provider_code" binding:"required"` // 服务商代码，如：aliyun_sms
+	ProviderType string                 `json:"provider_type"`                    // 消息类型，仅支持多种消息类型的服务商（如 mock）需要指定
 	Description  string                 `json:"description" binding:"max=200"`
 	Config       map[string]interface{} `json:"config" binding:"required"`
 	Status       int                    `json:"status" binding:"omitempty,oneof=1 2"`
@@ -118,6 +131,7 @@ type ProviderAccountResponse struct {
 	Config       map[string]interface{} `json:"config"`
 	Status       int                    `json:"status"`
 	CallbackURL  string                 `json:"callback_url,omitempty"` // 回调地址（仅支持回调的服务商有值）
+	InboundURL   string                 `json:"inbound_url,omitempty"`  // 上行消息推送地址（仅支持上行消息的服务商有值）
 	CreatedAt    string                 `json:"created_at"`
 	UpdatedAt    string                 `json:"updated_at"`
 }
@@ -135,12 +149,14 @@ type AvailableProviderResponse struct {
 	Code         string                `json:"code"`
 	Name         string                `json:"name"`
 	Type         string                `json:"type"`
+	MessageTypes []string              `json:"message_types,omitempty"` // 支持多种消息类型时的全部类型
 	Description  string                `json:"description"`
 	ConfigFields []ConfigFieldResponse `json:"config_fields"`
 	// 能力声明
 	SupportsSend      bool `json:"supports_send"`
 	SupportsBatchSend bool `json:"supports_batch_send"`
 	SupportsCallback  bool `json:"supports_callback"`
+	SupportsInbound   bool `json:"supports_inbound"`
 	// 扩展信息
 	Website    string   `json:"website"`
 	Icon       string   `json:"icon"`
@@ -169,16 +185,18 @@ type ConfigFieldResponse struct {
 
 // CreateChannelRequest 创建通道请求
 type CreateChannelRequest struct {
-	Name              string `json:"name" binding:"required,min=2,max=50"`
-	Type              string `json:"type" binding:"required,oneof=sms email wechat_work dingtalk webhook push"`
-	MessageTemplateID uint   `json:"message_template_id" binding:"required"`
-	Status            int    `json:"status" binding:"omitempty,oneof=1 2"`
+	Name              string               `json:"name" binding:"required,min=2,max=50"`
+	Type              string               `json:"type" binding:"required,oneof=sms email wechat_work dingtalk webhook push"`
+	MessageTemplateID uint                 `json:"message_template_id" binding:"required"`
+	Status            int                  `json:"status" binding:"omitempty,oneof=1 2"`
+	FrequencyLimits   []FrequencyLimitItem `json:"frequency_limits" binding:"omitempty,dive"` // 接收者频率限制，与应用的限制同时生效
 }
 
 // UpdateChannelRequest 更新通道请求
 type UpdateChannelRequest struct {
-	Name   string `json:"name" binding:"omitempty,min=2,max=50"`
-	Status int    `json:"status" binding:"omitempty,oneof=1 2"`
+	Name            string               `json:"name" binding:"omitempty,min=2,max=50"`
+	Status          int                  `json:"status" binding:"omitempty,oneof=1 2"`
+	FrequencyLimits []FrequencyLimitItem `json:"frequency_limits" binding:"omitempty,dive"` // nil表示不更新，空数组表示清除限制
 }
 
 // ChannelListRequest 通道列表请求
@@ -197,6 +215,7 @@ type ChannelResponse struct {
 	MessageTemplateID uint                      `json:"message_template_id"`
 	TemplateName      string                    `json:"template_name"`
 	Status            int                       `json:"status"`
+	FrequencyLimits   []FrequencyLimitItem      `json:"frequency_limits"`
 	CreatedAt         string                    `json:"created_at"`
 	UpdatedAt         string                    `json:"updated_at"`
 	Bindings          []*ChannelBindingResponse `json:"bindings,omitempty"`
@@ -225,9 +244,45 @@ type ChannelBindingResponse struct {
 	IsActive             int8               `json:"is_active"`
 	AutoDisableOnFail    bool               `json:"auto_disable_on_fail"`
 	AutoDisableThreshold int                `json:"auto_disable_threshold"`
+	ExtendCode           string             `json:"extend_code"`
+	CircuitState         string             `json:"circuit_state"` // 熔断器状态：closed/open/half_open
 	CreatedAt            string             `json:"created_at"`
 }
 
+// CircuitBreakerResponse 熔断器状态响应
+type CircuitBreakerResponse struct {
+	BindingID           uint    `json:"binding_id"`
+	State               string  `json:"state"`
+	Requests            uint32  `json:"requests"`
+	Failures            uint32  `json:"failures"`
+	ConsecutiveFailures uint32  `json:"consecutive_failures"`
+	OpenedAt            *string `json:"opened_at"`
+	RetryAt             *string `json:"retry_at"` // 熔断打开时，下一次半开探测时间
+	LastFailureAt       *string `json:"last_failure_at"`
+}
+
+// ChannelBindingEventResponse 通道绑定事件响应
+type ChannelBindingEventResponse struct {
+	ID                  uint   `json:"id"`
+	ChannelID           uint   `json:"channel_id"`
+	BindingID           uint   `json:"binding_id"`
+	ProviderID          uint   `json:"provider_id"`
+	EventType           string `json:"event_type"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	ErrorCode           string `json:"error_code"`
+	ErrorMessage        string `json:"error_message"`
+	Operator            string `json:"operator"`
+	CreatedAt           string `json:"created_at"`
+}
+
+// ChannelBindingEventListResponse 通道绑定事件列表响应
+type ChannelBindingEventListResponse struct {
+	Total int64                          `json:"total"`
+	Page  int                            `json:"page"`
+	Size  int                            `json:"size"`
+	Items []*ChannelBindingEventResponse `json:"items"`
+}
+
 // CreateChannelBindingRequest 创建通道绑定配置请求
 type CreateChannelBindingRequest struct {
 	ProviderTemplateID   uint               `json:"provider_template_id" binding:"required"`
@@ -239,6 +294,7 @@ type CreateChannelBindingRequest struct {
 	IsActive             int8               `json:"is_active" binding:"omitempty,oneof=0 1"`
 	AutoDisableOnFail    bool               `json:"auto_disable_on_fail"`
 	AutoDisableThreshold int                `json:"auto_disable_threshold" binding:"omitempty,min=1,max=100"`
+	ExtendCode           string             `json:"extend_code" binding:"omitempty,max=20,numeric"` // 上行扩展码（短信），用于关联用户回复
 }
 
 // UpdateChannelBindingRequest 更新通道绑定配置请求
@@ -250,6 +306,7 @@ type UpdateChannelBindingRequest struct {
 	IsActive             int8               `json:"is_active" binding:"omitempty,oneof=0 1"`
 	AutoDisableOnFail    bool               `json:"auto_disable_on_fail"`
 	AutoDisableThreshold int                `json:"auto_disable_threshold" binding:"omitempty,min=1,max=100"`
+	ExtendCode           *string            `json:"extend_code" binding:"omitempty,max=20,len=0|numeric"` // nil表示不更新，空字符串表示清除
 }
 
 // AvailableProviderTemplateResponse 可用供应商模板响应（用于通道绑定）
@@ -277,20 +334,22 @@ type StatisticsRequest struct {
 
 // DailyStatistics 每日统计
 type DailyStatistics struct {
-	Date         string `json:"date"`
-	TotalCount   int64  `json:"total_count"`
-	SuccessCount int64  `json:"success_count"`
-	FailureCount int64  `json:"failure_count"`
-	SuccessRate  string `json:"success_rate"`
+	Date           string `json:"date"`
+	TotalCount     int64  `json:"total_count"`
+	SuccessCount   int64  `json:"success_count"`
+	FailureCount   int64  `json:"failure_count"`
+	SuccessRate    string `json:"success_rate"`
+	ThrottledCount int64  `json:"throttled_count"` // 频率限制拒绝数
 }
 
 // StatisticsResponse 统计响应
 type StatisticsResponse struct {
 	Summary struct {
-		TotalCount   int64  `json:"total_count"`
-		SuccessCount int64  `json:"success_count"`
-		FailureCount int64  `json:"failure_count"`
-		SuccessRate  string `json:"success_rate"`
+		TotalCount     int64  `json:"total_count"`
+		SuccessCount   int64  `json:"success_count"`
+		FailureCount   int64  `json:"failure_count"`
+		SuccessRate    string `json:"success_rate"`
+		ThrottledCount int64  `json:"throttled_count"` // 频率限制拒绝数（按应用统计，不区分通道）
 	} `json:"summary"`
 	Daily []*DailyStatistics `json:"daily"`
 }
@@ -320,17 +379,19 @@ type TestProviderResponse struct {
 
 // DashboardResponse 仪表盘响应
 type DashboardResponse struct {
-	TotalApplications  int64  `json:"total_applications"`
-	ActiveApplications int64  `json:"active_applications"`
-	TotalChannels      int64  `json:"total_channels"`
-	ActiveChannels     int64  `json:"active_channels"`
-	TotalProviders     int64  `json:"total_providers"`
-	ActiveProviders    int64  `json:"active_providers"`
-	TodayPushCount     int64  `json:"today_push_count"`
-	TodaySuccessCount  int64  `json:"today_success_count"`
-	TodayFailedCount   int64  `json:"today_failed_count"`
-	TodaySuccessRate   string `json:"today_success_rate"`
-	TotalPushCount     int64  `json:"total_push_count"`
+	TotalApplications   int64  `json:"total_applications"`
+	ActiveApplications  int64  `json:"active_applications"`
+	TotalChannels       int64  `json:"total_channels"`
+	ActiveChannels      int64  `json:"active_channels"`
+	TotalProviders      int64  `json:"total_providers"`
+	ActiveProviders     int64  `json:"active_providers"`
+	TodayPushCount      int64  `json:"today_push_count"`
+	TodaySuccessCount   int64  `json:"today_success_count"`
+	TodayFailedCount    int64  `json:"today_failed_count"`
+	TodaySuccessRate    string `json:"today_success_rate"`
+	TodayThrottledCount int64  `json:"today_throttled_count"` // 今日频率限制拒绝数
+	TotalPushCount      int64  `json:"total_push_count"`
+	DeadLetterCount     int64  `json:"dead_letter_count"` // 死信队列积压数量
 }
 
 // TopApplicationResponse 热门应用
@@ -358,3 +419,25 @@ type QuotaUsageResponse struct {
 	Remaining       int     `json:"remaining"`
 	UsagePercentage float64 `json:"usage_percentage"`
 }
+
+// CreateFrequencyAllowlistRequest 添加频率限制白名单请求
+type CreateFrequencyAllowlistRequest struct {
+	Receiver string `json:"receiver" binding:"required,max=100"`
+	Remark   string `json:"remark" binding:"max=200"`
+}
+
+// FrequencyAllowlistResponse 频率限制白名单响应
+type FrequencyAllowlistResponse struct {
+	ID        uint   `json:"id"`
+	Receiver  string `json:"receiver"`
+	Remark    string `json:"remark"`
+	CreatedAt string `json:"created_at"`
+}
+
+// FrequencyAllowlistListResponse 频率限制白名单列表响应
+type FrequencyAllowlistListResponse struct {
+	Total int64                         `json:"total"`
+	Page  int                           `json:"page"`
+	Size  int                           `json:"size"`
+	Items []*FrequencyAllowlistResponse `json:"items"`
+}
@@ -74,6 +74,7 @@ type WebhookLogItem struct {
 	Status          string `json:"status"`
 	ErrorMessage    string `json:"error_message"`
 	RetryCount      int    `json:"retry_count"`
+	NextRetryAt     string `json:"next_retry_at,omitempty"` // 待投递时的下次投递时间
 	CreatedAt       string `json:"created_at"`
 }
 
@@ -4,15 +4,16 @@ import "time"
 
 // PushTaskListRequest 推送任务列表请求参数
 type PushTaskListRequest struct {
-	Page        int    `form:"page" binding:"required,min=1"`
-	PageSize    int    `form:"page_size" binding:"required,min=1,max=100"`
-	TaskID      string `form:"task_id"`
-	AppID       string `form:"app_id"`
-	Status      string `form:"status"`
-	MessageType string `form:"message_type"`
-	StartDate   string `form:"start_date"` // YYYY-MM-DD
-	EndDate     string `form:"end_date"`   // YYYY-MM-DD
-	BatchID     string `form:"batch_id"`   // 按批次ID查询
+	Page           int    `form:"page" binding:"required,min=1"`
+	PageSize       int    `form:"page_size" binding:"required,min=1,max=100"`
+	TaskID         string `form:"task_id"`
+	AppID          string `form:"app_id"`
+	Status         string `form:"status"`
+	MessageType    string `form:"message_type"`
+	StartDate      string `form:"start_date"`      // YYYY-MM-DD
+	EndDate        string `form:"end_date"`        // YYYY-MM-DD
+	BatchID        string `form:"batch_id"`        // 按批次ID查询
+	IdempotencyKey string `form:"idempotency_key"` // 按幂等键查询
 }
 
 // PushTaskListResponse 推送任务列表响应
@@ -28,6 +29,7 @@ type PushTaskItem struct {
 	ID             uint       `json:"id"`
 	TaskID         string     `json:"task_id"`
 	AppID          string     `json:"app_id"`
+	BatchID        string     `json:"batch_id"`
 	ChannelID      uint       `json:"channel_id"`
 	ProviderMsgID  string     `json:"provider_msg_id"`
 	MessageType    string     `json:"message_type"`
@@ -37,12 +39,14 @@ type PushTaskItem struct {
 	TemplateCode   string     `json:"template_code"`
 	TemplateParams string     `json:"template_params"`
 	Signature      string     `json:"signature"`
+	IdempotencyKey string     `json:"idempotency_key"`
 	Status         string     `json:"status"`
 	CallbackStatus string     `json:"callback_status"`
 	CallbackTime   *time.Time `json:"callback_time"`
 	RetryCount     int        `json:"retry_count"`
 	MaxRetry       int        `json:"max_retry"`
 	ScheduledAt    *time.Time `json:"scheduled_at"`
+	NextRetryAt    *time.Time `json:"next_retry_at"`
 	CreatedAt      string     `json:"created_at"`
 	UpdatedAt      string     `json:"updated_at"`
 	// 关联数据
@@ -0,0 +1,70 @@
+package dto
+
+// DeadLetterListRequest 死信消息列表请求参数
+type DeadLetterListRequest struct {
+	Cursor   string `form:"cursor"` // 上一页返回的 next_cursor，为空表示从最新开始
+	PageSize int    `form:"page_size"`
+}
+
+// DeadLetterListResponse 死信消息列表响应
+type DeadLetterListResponse struct {
+	Total      int64             `json:"total"`
+	Size       int               `json:"size"`
+	NextCursor string            `json:"next_cursor"` // 为空表示没有更多
+	Items      []*DeadLetterItem `json:"items"`
+}
+
+// DeadLetterItem 死信消息项
+type DeadLetterItem struct {
+	ID                string                 `json:"id"`
+	TaskID            string                 `json:"task_id"`
+	LastError         string                 `json:"last_error"`
+	OriginalMessageID string                 `json:"original_message_id"`
+	Consumer          string                 `json:"consumer"`
+	DeadAt            string                 `json:"dead_at"`
+	Data              map[string]interface{} `json:"data"`
+	Task              *PushTaskItem          `json:"task,omitempty"` // 任务已删除时为空
+}
+
+// DeadLetterReplayRequest 重放单条死信消息请求
+type DeadLetterReplayRequest struct {
+	TargetChannelID uint `json:"target_channel_id"` // 为0时使用原通道
+}
+
+// DeadLetterBatchReplayRequest 批量重放死信消息请求
+// 指定 IDs 时仅重放这些消息，否则按过滤条件从最早的消息开始重放
+type DeadLetterBatchReplayRequest struct {
+	IDs             []string `json:"ids"`
+	AppID           string   `json:"app_id"`
+	ChannelID       uint     `json:"channel_id"`
+	StartTime       string   `json:"start_time"` // RFC3339
+	EndTime         string   `json:"end_time"`   // RFC3339
+	Limit           int      `json:"limit"`      // 最多重放数量，默认100，最大1000
+	TargetChannelID uint     `json:"target_channel_id"`
+}
+
+// DeadLetterReplayResponse 重放结果
+type DeadLetterReplayResponse struct {
+	Replayed int                       `json:"replayed"`
+	Failed   int                       `json:"failed"`
+	Errors   []*DeadLetterReplayFailed `json:"errors,omitempty"`
+}
+
+// DeadLetterReplayFailed 重放失败项
+type DeadLetterReplayFailed struct {
+	ID     string `json:"id"`
+	TaskID string `json:"task_id"`
+	Error  string `json:"error"`
+}
+
+// DeadLetterTrimRequest 裁剪死信队列请求
+// Before 与 MaxLen 至少指定一个
+type DeadLetterTrimRequest struct {
+	Before string `json:"before"`  // RFC3339，删除早于该时间的消息
+	MaxLen *int64 `json:"max_len"` // 仅保留最新的 max_len 条
+}
+
+// DeadLetterTrimResponse 裁剪/清空结果
+type DeadLetterTrimResponse struct {
+	Deleted int64 `json:"deleted"`
+}
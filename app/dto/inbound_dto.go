@@ -0,0 +1,38 @@
+package dto
+
+// InboundMessageListRequest 上行消息列表请求参数
+type InboundMessageListRequest struct {
+	Page              int    `form:"page"`
+	PageSize          int    `form:"page_size"`
+	Phone             string `form:"phone"` // 模糊匹配
+	AppID             string `form:"app_id"`
+	TaskID            string `form:"task_id"`
+	ProviderAccountID uint   `form:"provider_account_id"`
+	Unsubscribe       *bool  `form:"unsubscribe"`
+}
+
+// InboundMessageResponse 上行消息响应
+type InboundMessageResponse struct {
+	ID                uint   `json:"id"`
+	AppID             string `json:"app_id"`
+	TaskID            string `json:"task_id"`
+	ProviderAccountID uint   `json:"provider_account_id"`
+	ProviderCode      string `json:"provider_code"`
+	Phone             string `json:"phone"`
+	Content           string `json:"content"`
+	SignName          string `json:"sign_name"`
+	ExtendCode        string `json:"extend_code"`
+	DestCode          string `json:"dest_code"`
+	SequenceID        string `json:"sequence_id"`
+	Unsubscribe       bool   `json:"unsubscribe"`
+	ReceivedAt        string `json:"received_at"`
+	CreatedAt         string `json:"created_at"`
+}
+
+// InboundMessageListResponse 上行消息列表响应
+type InboundMessageListResponse struct {
+	Total int64                     `json:"total"`
+	Page  int                       `json:"page"`
+	Size  int                       `json:"size"`
+	Items []*InboundMessageResponse `json:"items"`
+}
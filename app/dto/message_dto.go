@@ -10,6 +10,7 @@ type SendRequest struct {
 	TemplateParams map[string]string `json:"template_params"`
 	SignatureName  string            `json:"signature_name"` // 用户自定义签名名称
 	ScheduledAt    *time.Time        `json:"scheduled_at"`
+	ClientMsgID    string            `json:"client_msg_id"` // 幂等键，也可通过 Idempotency-Key 请求头传递
 }
 
 // BatchSendRequest 批量发送请求
@@ -20,6 +21,7 @@ type BatchSendRequest struct {
 	TemplateParams map[string]string `json:"template_params"`              // 模板参数（所有接收者共用）
 	SignatureName  string            `json:"signature_name"`               // 用户自定义签名名称
 	ScheduledAt    *time.Time        `json:"scheduled_at"`
+	ClientMsgID    string            `json:"client_msg_id"` // 幂等键，也可通过 Idempotency-Key 请求头传递
 }
 
 // SendResponse 发送响应
@@ -31,9 +33,45 @@ type SendResponse struct {
 
 // BatchSendResponse 批量发送响应
 type BatchSendResponse struct {
-	BatchID      string    `json:"batch_id"`
-	TotalCount   int       `json:"total_count"`
-	SuccessCount int       `json:"success_count"`
-	FailedCount  int       `json:"failed_count"`
-	CreatedAt    time.Time `json:"created_at"`
+	BatchID             string    `json:"batch_id"`
+	TotalCount          int       `json:"total_count"`
+	SuccessCount        int       `json:"success_count"`
+	FailedCount         int       `json:"failed_count"`
+	CreatedAt           time.Time `json:"created_at"`
+	ThrottledReceivers  []string  `json:"throttled_receivers,omitempty"`  // 超出频率限制未受理的接收者
+	SuppressedReceivers []string  `json:"suppressed_receivers,omitempty"` // 命中屏蔽名单未发送的接收者（任务状态为 suppressed）
+}
+
+// BatchQueryResponse 批次查询响应
+type BatchQueryResponse struct {
+	BatchID      string           `json:"batch_id"`
+	Status       string           `json:"status"`
+	TotalCount   int              `json:"total_count"`
+	SuccessCount int              `json:"success_count"`
+	FailedCount  int              `json:"failed_count"`
+	PendingCount int              `json:"pending_count"`
+	StatusCounts map[string]int64 `json:"status_counts"` // 按任务状态统计
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+	Tasks        *BatchTaskPage   `json:"tasks"`
+}
+
+// BatchTaskPage 批次任务分页列表
+type BatchTaskPage struct {
+	Total int64            `json:"total"`
+	Page  int              `json:"page"`
+	Size  int              `json:"size"`
+	Items []*BatchTaskItem `json:"items"`
+}
+
+// BatchTaskItem 批次任务项
+type BatchTaskItem struct {
+	TaskID         string     `json:"task_id"`
+	Receiver       string     `json:"receiver"`
+	Status         string     `json:"status"`
+	CallbackStatus string     `json:"callback_status"`
+	CallbackTime   *time.Time `json:"callback_time"`
+	RetryCount     int        `json:"retry_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
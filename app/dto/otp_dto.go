@@ -0,0 +1,35 @@
+package dto
+
+// OTPSendRequest 发送验证码请求
+// 验证码由服务端生成，以 otp.code_param（默认 code）为参数名合并到模板参数中
+type OTPSendRequest struct {
+	AppID             string            `json:"app_id"`
+	ChannelID         uint              `json:"channel_id" binding:"required"`
+	Receiver          string            `json:"receiver" binding:"required,max=100"`
+	Scene             string            `json:"scene" binding:"omitempty,max=32"` // 业务场景（如 login、register），不同场景的验证码互不影响，默认 default
+	TemplateParams    map[string]string `json:"template_params"`                  // 除验证码外的其他模板参数
+	SignatureName     string            `json:"signature_name"`                   // 用户自定义签名名称
+	FallbackChannelID uint              `json:"fallback_channel_id"`              // 备用通道，超时未收到送达回执时通过该通道发送新验证码（原验证码作废）
+}
+
+// OTPSendResponse 发送验证码响应
+type OTPSendResponse struct {
+	TaskID      string `json:"task_id"`
+	Status      string `json:"status"`                // 任务状态（接收者命中屏蔽名单时为 suppressed，不会发送）
+	ExpiresIn   int    `json:"expires_in"`            // 验证码有效期（秒）
+	ResendAfter int    `json:"resend_after"`          // 距离可再次发送的秒数
+	FallbackIn  int    `json:"fallback_in,omitempty"` // 超过该秒数未送达将通过备用通道重发（指定备用通道时有值）
+}
+
+// OTPVerifyRequest 校验验证码请求
+type OTPVerifyRequest struct {
+	AppID    string `json:"app_id"`
+	Receiver string `json:"receiver" binding:"required,max=100"`
+	Code     string `json:"code" binding:"required,max=32"`
+	Scene    string `json:"scene" binding:"omitempty,max=32"`
+}
+
+// OTPVerifyResponse 校验验证码响应
+type OTPVerifyResponse struct {
+	Verified bool `json:"verified"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// SchedulerLeaderResponse 调度器 leader 信息
+type SchedulerLeaderResponse struct {
+	LeaderInstanceID  string `json:"leader_instance_id"` // 为空表示当前无 leader
+	TTLSeconds        int64  `json:"ttl_seconds"`        // leader 租约剩余秒数
+	CurrentInstanceID string `json:"current_instance_id"`
+	IsCurrentLeader   bool   `json:"is_current_leader"`
+}
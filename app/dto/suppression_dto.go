@@ -0,0 +1,70 @@
+package dto
+
+import "time"
+
+// SuppressionListRequest 屏蔽名单列表请求参数
+type SuppressionListRequest struct {
+	Page      int    `form:"page"`
+	PageSize  int    `form:"page_size"`
+	Receiver  string `form:"receiver"` // 模糊匹配
+	Type      string `form:"type"`
+	Scope     string `form:"scope"`
+	AppID     string `form:"app_id"`
+	ChannelID uint   `form:"channel_id"`
+}
+
+// CreateSuppressionRequest 添加屏蔽记录请求
+type CreateSuppressionRequest struct {
+	Receiver  string     `json:"receiver" binding:"required,max=100"`
+	Type      string     `json:"type" binding:"required"`  // unsubscribe, complaint, invalid, manual
+	Scope     string     `json:"scope" binding:"required"` // global, app, channel
+	AppID     string     `json:"app_id"`                   // scope=app 时必填
+	ChannelID uint       `json:"channel_id"`               // scope=channel 时必填
+	Reason    string     `json:"reason" binding:"omitempty,max=255"`
+	ExpiresAt *time.Time `json:"expires_at"` // 为空表示永久有效
+}
+
+// UpdateSuppressionRequest 更新屏蔽记录请求（接收者和范围不可修改）
+type UpdateSuppressionRequest struct {
+	Type      *string    `json:"type"`
+	Reason    *string    `json:"reason" binding:"omitempty,max=255"`
+	ExpiresAt *time.Time `json:"expires_at"` // 为空表示永久有效
+}
+
+// SuppressionResponse 屏蔽记录响应
+type SuppressionResponse struct {
+	ID        uint   `json:"id"`
+	Receiver  string `json:"receiver"`
+	Type      string `json:"type"`
+	Scope     string `json:"scope"`
+	AppID     string `json:"app_id"`
+	ChannelID uint   `json:"channel_id"`
+	Reason    string `json:"reason"`
+	Source    string `json:"source"`
+	ExpiresAt string `json:"expires_at"` // 为空表示永久有效
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// SuppressionListResponse 屏蔽名单列表响应
+type SuppressionListResponse struct {
+	Total int64                  `json:"total"`
+	Page  int                    `json:"page"`
+	Size  int                    `json:"size"`
+	Items []*SuppressionResponse `json:"items"`
+}
+
+// SuppressionImportResponse 屏蔽名单导入结果
+type SuppressionImportResponse struct {
+	Total    int                       `json:"total"`    // 数据行数（不含表头）
+	Imported int                       `json:"imported"` // 写入数（已存在的记录被覆盖）
+	Failed   int                       `json:"failed"`
+	Errors   []*SuppressionImportError `json:"errors,omitempty"` // 最多返回前100条错误
+}
+
+// SuppressionImportError 导入失败的行
+type SuppressionImportError struct {
+	Line     int    `json:"line"` // CSV 行号（表头为第1行）
+	Receiver string `json:"receiver"`
+	Error    string `json:"error"`
+}
@@ -0,0 +1,90 @@
+package dto
+
+// CreateWebhookConfigRequest 创建Webhook配置请求
+type CreateWebhookConfigRequest struct {
+	Name          string   `json:"name" binding:"omitempty,max=100"`
+	WebhookURL    string   `json:"webhook_url" binding:"required,url,max=500"`
+	Secret        string   `json:"secret" binding:"omitempty,max=64"` // 为空时自动生成
+	Events        []string `json:"events" binding:"required,min=1"`
+	Status        *int     `json:"status" binding:"omitempty,oneof=0 1"`              // nil 默认启用
+	RetryCount    *int     `json:"retry_count" binding:"omitempty,min=0,max=10"`      // nil 默认 3
+	RetryInterval *int     `json:"retry_interval" binding:"omitempty,min=1,max=3600"` // 重试基础间隔，按指数退避递增，nil 默认 1 秒
+	Timeout       *int     `json:"timeout" binding:"omitempty,min=1,max=30"`          // nil 默认 5 秒
+	Description   string   `json:"description" binding:"omitempty,max=200"`
+}
+
+// UpdateWebhookConfigRequest 更新Webhook配置请求（字段为空表示不更新）
+type UpdateWebhookConfigRequest struct {
+	Name          *string  `json:"name" binding:"omitempty,max=100"`
+	WebhookURL    string   `json:"webhook_url" binding:"omitempty,url,max=500"`
+	Secret        string   `json:"secret" binding:"omitempty,max=64"`
+	Events        []string `json:"events" binding:"omitempty,min=1"`
+	Status        *int     `json:"status" binding:"omitempty,oneof=0 1"`
+	RetryCount    *int     `json:"retry_count" binding:"omitempty,min=0,max=10"`
+	RetryInterval *int     `json:"retry_interval" binding:"omitempty,min=1,max=3600"`
+	Timeout       *int     `json:"timeout" binding:"omitempty,min=1,max=30"`
+	Description   *string  `json:"description" binding:"omitempty,max=200"`
+}
+
+// WebhookConfigResponse Webhook配置响应
+type WebhookConfigResponse struct {
+	ID            uint     `json:"id"`
+	AppID         string   `json:"app_id"`
+	Name          string   `json:"name"`
+	WebhookURL    string   `json:"webhook_url"`
+	Secret        string   `json:"secret"`
+	Events        []string `json:"events"`
+	Status        int      `json:"status"`
+	RetryCount    int      `json:"retry_count"`
+	RetryInterval int      `json:"retry_interval"`
+	Timeout       int      `json:"timeout"`
+	Description   string   `json:"description"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
+}
+
+// WebhookConfigListResponse Webhook配置列表响应
+type WebhookConfigListResponse struct {
+	Items  []*WebhookConfigResponse `json:"items"`
+	Events []string                 `json:"events"` // 可订阅的事件列表
+}
+
+// WebhookTestResponse Webhook测试事件响应
+type WebhookTestResponse struct {
+	Success        bool   `json:"success"`
+	ResponseStatus int    `json:"response_status"`
+	ResponseData   string `json:"response_data"`
+	ErrorMessage   string `json:"error_message"`
+	LogID          uint   `json:"log_id"`
+}
+
+// WebhookDeliveryListResponse Webhook投递记录列表响应
+type WebhookDeliveryListResponse struct {
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Size  int               `json:"size"`
+	Items []*WebhookLogItem `json:"items"`
+}
+
+// WebhookRedeliverRequest 批量重新投递请求
+// 重新投递时间范围内投递失败的记录，每条记录以相同事件内容新建一次投递
+type WebhookRedeliverRequest struct {
+	StartTime       string `json:"start_time" binding:"required"` // RFC3339
+	EndTime         string `json:"end_time" binding:"required"`   // RFC3339
+	AppID           string `json:"app_id"`
+	WebhookConfigID uint   `json:"webhook_config_id"`
+	Limit           int    `json:"limit"` // 最多重新投递数量，默认100，最大1000
+}
+
+// WebhookRedeliverResponse 批量重新投递结果
+type WebhookRedeliverResponse struct {
+	Redelivered int                       `json:"redelivered"`
+	Failed      int                       `json:"failed"`
+	Errors      []*WebhookRedeliverFailed `json:"errors,omitempty"`
+}
+
+// WebhookRedeliverFailed 重新投递失败项
+type WebhookRedeliverFailed struct {
+	LogID uint   `json:"log_id"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,22 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+)
+
+// ResolveInstanceID 获取当前实例标识
+// 优先使用配置 worker.instance_id（Kubernetes 中可通过 Downward API 注入 Pod 名），其次使用主机名
+func ResolveInstanceID() string {
+	if instanceID := internalHelper.GetHelper().GetEnv().GetString("worker.instance_id", ""); instanceID != "" {
+		return instanceID
+	}
+
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+
+	return fmt.Sprintf("pid-%d", os.Getpid())
+}
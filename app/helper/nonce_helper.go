@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/redis/go-redis/v9"
+)
+
+// nonceKeyPrefix 已使用随机数 key 前缀，完整 key 为 push:nonce:{app_id}:{nonce}
+const nonceKeyPrefix = "push:nonce:"
+
+// localNonces 进程内随机数记录（cache.driver=memory 或 Redis 不可用时使用）
+var localNonces = &nonceMemoryStore{items: make(map[string]time.Time)}
+
+// NonceHelper 请求随机数助手
+// 记录签名时间窗口内已使用的 (app_id, nonce)，拒绝重复请求，防止签名请求被重放
+type NonceHelper struct {
+	redis *redis.Client
+	ttl   time.Duration
+}
+
+// NewNonceHelper 创建随机数助手
+func NewNonceHelper() *NonceHelper {
+	h := internalHelper.GetHelper()
+
+	var redisClient *redis.Client
+	if h.GetConfig().GetString("cache.driver", "redis") == "redis" {
+		redisClient = h.GetRedis()
+	}
+
+	return &NonceHelper{
+		redis: redisClient,
+		// 时间戳允许前后偏移，随机数需保留整个可接受区间
+		ttl: 2 * SignatureTimeWindow * time.Second,
+	}
+}
+
+// CheckAndRemember 校验随机数未被使用并记录，返回 false 表示重复请求
+func (h *NonceHelper) CheckAndRemember(ctx context.Context, appID, nonce string) bool {
+	key := nonceKeyPrefix + appID + ":" + nonce
+
+	if h.redis != nil {
+		ok, err := h.redis.SetNX(ctx, key, 1, h.ttl).Result()
+		if err == nil {
+			return ok
+		}
+		// Redis 异常时退化为进程内校验，至少拦截打到同一实例的重放
+		internalHelper.GetHelper().GetLogger().Warn(fmt.Sprintf("failed to remember nonce in redis, fallback to memory: %v", err))
+	}
+
+	return localNonces.add(key, h.ttl)
+}
+
+// nonceMemoryStore 进程内随机数存储
+type nonceMemoryStore struct {
+	mu        sync.Mutex
+	items     map[string]time.Time // key -> 过期时间
+	nextSweep time.Time
+}
+
+// add 记录随机数，已存在且未过期时返回 false
+func (s *nonceMemoryStore) add(key string, ttl time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	// 定期清理过期记录，避免内存持续增长
+	if now.After(s.nextSweep) {
+		for k, expireAt := range s.items {
+			if now.After(expireAt) {
+				delete(s.items, k)
+			}
+		}
+		s.nextSweep = now.Add(time.Minute)
+	}
+
+	if expireAt, exists := s.items[key]; exists && now.Before(expireAt) {
+		return false
+	}
+
+	s.items[key] = now.Add(ttl)
+	return true
+}
@@ -10,6 +10,9 @@ import (
 	"time"
 )
 
+// SignatureTimeWindow 签名时间戳允许的偏移（秒）
+const SignatureTimeWindow = 300
+
 // SignatureHelper 签名助手
 type SignatureHelper struct {
 }
@@ -32,7 +35,7 @@ func (h *SignatureHelper) GenerateSignature(secret, method, path string, params
 func (h *SignatureHelper) VerifySignature(secret, method, path string, params map[string]interface{}, timestamp int64, nonce, signature string) bool {
 	// 检查时间戳（防重放攻击，允许 ±5 分钟）
 	now := time.Now().Unix()
-	if abs(now-timestamp) > 300 {
+	if abs(now-timestamp) > SignatureTimeWindow {
 		return false
 	}
 
@@ -17,6 +17,7 @@ import (
 func AuthMiddleware() gin.HandlerFunc {
 	appDao := dao.NewApplicationDAO()
 	signatureHelper := helper.NewSignatureHelper()
+	nonceHelper := helper.NewNonceHelper()
 
 	return func(c *gin.Context) {
 		// 从Header获取AppID和签名信息
@@ -93,6 +94,13 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 校验随机数（同一应用在时间窗口内不可重复使用，防止签名请求被重放）
+		if !nonceHelper.CheckAndRemember(c.Request.Context(), app.AppID, nonce) {
+			controller.FailWithCode(c, constants.CodeNonceReused)
+			c.Abort()
+			return
+		}
+
 		// 将应用信息存入上下文
 		c.Set("app_id", app.AppID)
 		c.Set("app_db_id", app.ID)
@@ -13,12 +13,18 @@ type Channel struct {
 	Type              string           `gorm:"type:varchar(20);not null;index:idx_type;comment:类型：sms, email, wechat_work, dingtalk" json:"type"`
 	MessageTemplateID uint             `gorm:"type:bigint unsigned;index:idx_message_template;comment:绑定的系统模板ID" json:"message_template_id"`
 	Status            int8             `gorm:"type:tinyint;default:1;index:idx_status;comment:状态：1=启用 0=禁用" json:"status"`
+	FrequencyLimits   string           `gorm:"type:text;comment:接收者频率限制，JSON数组格式 [{window,limit}]，空表示不限制" json:"frequency_limits"`
 	CreatedAt         time.Time        `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt         time.Time        `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
 	DeletedAt         gorm.DeletedAt   `gorm:"index" json:"deleted_at"`
 	MessageTemplate   *MessageTemplate `gorm:"foreignKey:MessageTemplateID;references:ID" json:"message_template,omitempty"`
 }
 
+// GetFrequencyLimits 获取接收者频率限制规则
+func (c *Channel) GetFrequencyLimits() ([]FrequencyLimit, error) {
+	return ParseFrequencyLimits(c.FrequencyLimits)
+}
+
 // TableName 指定表名
 func (Channel) TableName() string {
 	return "channels"
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+// 通道绑定事件类型
+const (
+	BindingEventAutoDisabled = "auto_disabled" // 连续失败达到阈值，系统自动停用
+	BindingEventReEnabled    = "re_enabled"    // 管理员手动重新启用
+)
+
+// ChannelBindingEvent 通道绑定事件记录表（自动停用、重新启用等）
+type ChannelBindingEvent struct {
+	ID                  uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ChannelID           uint      `gorm:"type:bigint unsigned;not null;index:idx_channel;comment:通道ID" json:"channel_id"`
+	BindingID           uint      `gorm:"type:bigint unsigned;not null;index:idx_binding;comment:通道模板绑定ID" json:"binding_id"`
+	ProviderID          uint      `gorm:"type:bigint unsigned;not null;comment:供应商账号ID" json:"provider_id"`
+	EventType           string    `gorm:"type:varchar(32);not null;comment:事件类型: auto_disabled/re_enabled" json:"event_type"`
+	ConsecutiveFailures int       `gorm:"type:int;default:0;comment:触发时的连续失败次数" json:"consecutive_failures"`
+	ErrorCode           string    `gorm:"type:varchar(64);comment:最后一次错误码" json:"error_code"`
+	ErrorMessage        string    `gorm:"type:text;comment:最后一次错误信息" json:"error_message"`
+	Operator            string    `gorm:"type:varchar(64);comment:操作人（系统自动为 system）" json:"operator"`
+	CreatedAt           time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index:idx_created_at" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ChannelBindingEvent) TableName() string {
+	return "channel_binding_events"
+}
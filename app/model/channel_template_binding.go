@@ -36,6 +36,7 @@ type ChannelTemplateBinding struct {
 	IsActive             int8              `gorm:"type:tinyint;default:1;comment:是否激活：1=是 0=否" json:"is_active"`
 	AutoDisableOnFail    bool              `gorm:"type:tinyint;default:0;comment:失败时自动禁用" json:"auto_disable_on_fail"`
 	AutoDisableThreshold int               `gorm:"type:int;default:5;comment:自动禁用阈值（连续失败次数）" json:"auto_disable_threshold"`
+	ExtendCode           string            `gorm:"type:varchar(20);not null;default:'';comment:上行扩展码（短信，用于关联用户回复）" json:"extend_code"`
 	CreatedAt            time.Time         `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt            time.Time         `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
 	DeletedAt            gorm.DeletedAt    `gorm:"index" json:"deleted_at"`
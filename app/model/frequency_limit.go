@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FrequencyLimit 接收者频率限制规则
+type FrequencyLimit struct {
+	Window int `json:"window"` // 时间窗口（秒）
+	Limit  int `json:"limit"`  // 窗口内同一接收者最多发送条数
+}
+
+// ParseFrequencyLimits 解析频率限制规则（JSON数组），空字符串表示不限制
+func ParseFrequencyLimits(raw string) ([]FrequencyLimit, error) {
+	var limits []FrequencyLimit
+	if raw == "" {
+		return limits, nil
+	}
+	err := json.Unmarshal([]byte(raw), &limits)
+	return limits, err
+}
+
+// FormatFrequencyLimits 序列化频率限制规则，没有规则时返回空字符串
+func FormatFrequencyLimits(limits []FrequencyLimit) string {
+	if len(limits) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(limits)
+	return string(data)
+}
+
+// FrequencyAllowlist 频率限制白名单表
+// 白名单中的接收者不受应用及其通道的频率限制
+type FrequencyAllowlist struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AppID     string    `gorm:"type:varchar(32);not null;uniqueIndex:uk_app_receiver;comment:应用ID" json:"app_id"`
+	Receiver  string    `gorm:"type:varchar(100);not null;uniqueIndex:uk_app_receiver;comment:接收者（手机号/邮箱/UserID等）" json:"receiver"`
+	Remark    string    `gorm:"type:varchar(200);comment:备注" json:"remark"`
+	CreatedAt time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName 指定表名
+func (FrequencyAllowlist) TableName() string {
+	return "frequency_allowlists"
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+// InboundMessage 上行消息表（用户回复的短信）
+// 按扩展码、签名和手机号关联到最近发送给该号码的任务，未关联到任务时 AppID 和 TaskID 为空
+// 服务商重复推送的同一条上行消息按 (服务商账号, 序列号) 去重，无序列号时 SequenceID 为 NULL 不参与去重
+type InboundMessage struct {
+	ID                uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AppID             string    `gorm:"type:varchar(32);not null;default:'';index:idx_app_id_created;comment:应用ID（关联到任务时有值）" json:"app_id"`
+	TaskID            string    `gorm:"type:varchar(36);not null;default:'';index:idx_task_id;comment:关联的原始任务ID" json:"task_id"`
+	ProviderAccountID uint      `gorm:"type:bigint unsigned;not null;index:idx_provider_account;uniqueIndex:uk_provider_sequence;comment:服务商账号ID" json:"provider_account_id"`
+	ProviderCode      string    `gorm:"type:varchar(50);not null;comment:服务商代码" json:"provider_code"`
+	Phone             string    `gorm:"type:varchar(32);not null;index:idx_phone;comment:发送者手机号" json:"phone"`
+	Content           string    `gorm:"type:text;comment:回复内容" json:"content"`
+	SignName          string    `gorm:"type:varchar(100);comment:短信签名" json:"sign_name"`
+	ExtendCode        string    `gorm:"type:varchar(20);comment:上行扩展码" json:"extend_code"`
+	DestCode          string    `gorm:"type:varchar(50);comment:上行目的号码" json:"dest_code"`
+	SequenceID        *string   `gorm:"type:varchar(64);uniqueIndex:uk_provider_sequence;comment:服务商上行消息序列号" json:"sequence_id"`
+	Unsubscribe       bool      `gorm:"type:tinyint;default:0;comment:是否为退订回复" json:"unsubscribe"`
+	RawData           string    `gorm:"type:text;comment:原始推送数据" json:"raw_data"`
+	ReceivedAt        time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;comment:接收时间" json:"received_at"`
+	CreatedAt         time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP;index:idx_app_id_created" json:"created_at"`
+}
+
+// TableName 指定表名
+func (InboundMessage) TableName() string {
+	return "inbound_messages"
+}
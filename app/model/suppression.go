@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+)
+
+// 屏蔽类型
+const (
+	SuppressionTypeUnsubscribe = "unsubscribe" // 用户退订
+	SuppressionTypeComplaint   = "complaint"   // 用户投诉
+	SuppressionTypeInvalid     = "invalid"     // 无效接收者（空号、硬退信等）
+	SuppressionTypeManual      = "manual"      // 人工屏蔽
+)
+
+// 屏蔽范围
+const (
+	SuppressionScopeGlobal  = "global"  // 全局，所有应用和通道
+	SuppressionScopeApp     = "app"     // 指定应用
+	SuppressionScopeChannel = "channel" // 指定通道
+)
+
+// 屏蔽记录来源
+const (
+	SuppressionSourceManual  = "manual"  // 管理后台添加
+	SuppressionSourceImport  = "import"  // CSV 批量导入
+	SuppressionSourceInbound = "inbound" // 用户回复退订关键词自动添加
+)
+
+// Suppression 屏蔽名单表
+// 命中屏蔽名单的接收者不会发送消息，任务以 suppressed 状态结束
+type Suppression struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Receiver  string     `gorm:"type:varchar(100);not null;uniqueIndex:uk_receiver_scope;comment:接收者（手机号/邮箱/UserID等）" json:"receiver"`
+	Type      string     `gorm:"type:varchar(20);not null;comment:屏蔽类型：unsubscribe, complaint, invalid, manual" json:"type"`
+	Scope     string     `gorm:"type:varchar(20);not null;uniqueIndex:uk_receiver_scope;comment:屏蔽范围：global, app, channel" json:"scope"`
+	AppID     string     `gorm:"type:varchar(32);not null;default:'';uniqueIndex:uk_receiver_scope;comment:应用ID（scope=app时有效）" json:"app_id"`
+	ChannelID uint       `gorm:"type:bigint unsigned;not null;default:0;uniqueIndex:uk_receiver_scope;comment:通道ID（scope=channel时有效）" json:"channel_id"`
+	Reason    string     `gorm:"type:varchar(255);comment:屏蔽原因" json:"reason"`
+	Source    string     `gorm:"type:varchar(20);not null;default:'manual';comment:来源：manual, import, inbound" json:"source"`
+	ExpiresAt *time.Time `gorm:"type:timestamp;null;index:idx_expires_at;comment:过期时间，为空表示永久有效" json:"expires_at"`
+	CreatedAt time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Suppression) TableName() string {
+	return "suppressions"
+}
+
+// IsValidSuppressionType 检查屏蔽类型是否有效
+func IsValidSuppressionType(t string) bool {
+	switch t {
+	case SuppressionTypeUnsubscribe, SuppressionTypeComplaint, SuppressionTypeInvalid, SuppressionTypeManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidSuppressionScope 检查屏蔽范围是否有效
+func IsValidSuppressionScope(scope string) bool {
+	switch scope {
+	case SuppressionScopeGlobal, SuppressionScopeApp, SuppressionScopeChannel:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,22 +1,28 @@
 package model
 
 import (
+	"strings"
 	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
 )
 
 // WebhookConfig 应用的 Webhook 配置
+// 一个应用可配置多个回调地址，每个地址独立订阅事件、签名密钥、超时和重试策略
 type WebhookConfig struct {
-	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
-	AppID       string    `gorm:"type:varchar(32);not null;uniqueIndex:uk_app_id;comment:应用ID" json:"app_id"`
-	WebhookURL  string    `gorm:"type:varchar(500);not null;comment:回调地址" json:"webhook_url"`
-	Secret      string    `gorm:"type:varchar(64);comment:签名密钥" json:"secret"`
-	Events      string    `gorm:"type:varchar(200);default:'success,failed';comment:订阅事件：success,failed,delivered" json:"events"`
-	Status      int       `gorm:"type:tinyint;default:1;comment:状态：0-禁用 1-启用" json:"status"`
-	RetryCount  int       `gorm:"type:int;default:3;comment:最大重试次数" json:"retry_count"`
-	Timeout     int       `gorm:"type:int;default:5;comment:超时时间（秒）" json:"timeout"`
-	Description string    `gorm:"type:varchar(200);comment:描述" json:"description"`
-	CreatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AppID         string    `gorm:"type:varchar(32);not null;index:idx_app_id;comment:应用ID" json:"app_id"`
+	Name          string    `gorm:"type:varchar(100);comment:名称" json:"name"`
+	WebhookURL    string    `gorm:"type:varchar(500);not null;comment:回调地址" json:"webhook_url"`
+	Secret        string    `gorm:"type:varchar(64);comment:签名密钥" json:"secret"`
+	Events        string    `gorm:"type:varchar(200);default:'task.delivered,task.failed';comment:订阅事件，逗号分隔" json:"events"`
+	Status        int       `gorm:"type:tinyint;default:1;comment:状态：0-禁用 1-启用" json:"status"`
+	RetryCount    int       `gorm:"type:int;default:3;comment:最大重试次数" json:"retry_count"`
+	RetryInterval int       `gorm:"type:int;default:1;comment:重试基础间隔（秒），按指数退避递增" json:"retry_interval"`
+	Timeout       int       `gorm:"type:int;default:5;comment:超时时间（秒）" json:"timeout"`
+	Description   string    `gorm:"type:varchar(200);comment:描述" json:"description"`
+	CreatedAt     time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"type:timestamp;default:CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -30,34 +36,17 @@ func (w *WebhookConfig) IsEnabled() bool {
 }
 
 // ShouldNotify 是否应该通知某个事件
+// 订阅列表中的旧版事件名（delivered, failed, rejected）按事件目录中的对应事件匹配
 func (w *WebhookConfig) ShouldNotify(event string) bool {
-	if !w.IsEnabled() {
-		return false
-	}
-
-	// 检查事件是否在订阅列表中
-	// events 格式：success,failed,delivered
-	events := w.Events
-	if events == "" {
+	if !w.IsEnabled() || w.Events == "" {
 		return false
 	}
 
-	// 简单的字符串包含检查
-	return containsEvent(events, event)
-}
-
-// containsEvent 检查事件列表是否包含指定事件
-func containsEvent(events, event string) bool {
-	// 简单实现，可以优化为解析后的 map
-	for i := 0; i < len(events); {
-		j := i
-		for j < len(events) && events[j] != ',' {
-			j++
-		}
-		if events[i:j] == event {
+	// events 格式：task.delivered,task.failed
+	for _, subscribed := range strings.Split(w.Events, ",") {
+		if constants.NormalizeWebhookEvent(strings.TrimSpace(subscribed)) == event {
 			return true
 		}
-		i = j + 1
 	}
 	return false
 }
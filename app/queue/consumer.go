@@ -85,16 +85,109 @@ func (c *Consumer) Ack(ctx context.Context, messageID string) error {
 }
 
 // MoveToDeadLetter 移入死信队列
-func (c *Consumer) MoveToDeadLetter(ctx context.Context, message *Message) error {
+// reason 为进入死信队列的原因（最后一次错误），与原消息 ID、消费者一并记录便于排查
+func (c *Consumer) MoveToDeadLetter(ctx context.Context, message *Message, reason string) error {
+	values := make(map[string]interface{}, len(message.Data)+4)
+	for k, v := range message.Data {
+		values[k] = v
+	}
+	values[DeadLetterFieldError] = reason
+	values[DeadLetterFieldOriginalID] = message.ID
+	values[DeadLetterFieldConsumer] = c.consumerName
+	values[DeadLetterFieldDeadAt] = time.Now().Unix()
+
 	// 推送到死信队列
 	_, err := c.redis.XAdd(ctx, &redis.XAddArgs{
-		Stream: "push:stream:dead_letter",
-		Values: message.Data,
+		Stream: DeadLetterStream,
+		Values: values,
 	}).Result()
 
 	return err
 }
 
+// AutoClaim 认领其他消费者长时间未确认的消息（XAUTOCLAIM）
+// start 为本轮扫描起点，返回下一轮扫描起点（"0-0" 表示已扫描完一轮）
+func (c *Consumer) AutoClaim(ctx context.Context, minIdle time.Duration, start string, count int64) ([]*Message, string, error) {
+	msgs, next, err := c.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   c.streamName,
+		Group:    c.consumerGroup,
+		Consumer: c.consumerName,
+		MinIdle:  minIdle,
+		Start:    start,
+		Count:    count,
+	}).Result()
+
+	if err != nil {
+		if err == redis.Nil {
+			return nil, "0-0", nil
+		}
+		return nil, "0-0", err
+	}
+
+	messages := make([]*Message, 0, len(msgs))
+	for _, msg := range msgs {
+		message := &Message{
+			ID:   msg.ID,
+			Data: msg.Values,
+		}
+
+		if taskID, ok := msg.Values["task_id"].(string); ok {
+			message.TaskID = taskID
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, next, nil
+}
+
+// DeliveryCount 获取消息的投递次数
+func (c *Consumer) DeliveryCount(ctx context.Context, messageID string) (int64, error) {
+	pending, err := c.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: c.streamName,
+		Group:  c.consumerGroup,
+		Start:  messageID,
+		End:    messageID,
+		Count:  1,
+	}).Result()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	return pending[0].RetryCount, nil
+}
+
+// CleanupConsumers 删除长时间无活动且没有待确认消息的消费者
+// keep 返回 true 的消费者不会被删除（如当前实例自身的消费者）
+func (c *Consumer) CleanupConsumers(ctx context.Context, minIdle time.Duration, keep func(name string) bool) ([]string, error) {
+	consumers, err := c.redis.XInfoConsumers(ctx, c.streamName, c.consumerGroup).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, consumer := range consumers {
+		if consumer.Pending > 0 || consumer.Idle < minIdle {
+			continue
+		}
+		if keep != nil && keep(consumer.Name) {
+			continue
+		}
+
+		if err := c.redis.XGroupDelConsumer(ctx, c.streamName, c.consumerGroup, consumer.Name).Err(); err != nil {
+			return removed, err
+		}
+		removed = append(removed, consumer.Name)
+	}
+
+	return removed, nil
+}
+
 // GetPendingMessages 获取待处理的消息（超时未确认）
 func (c *Consumer) GetPendingMessages(ctx context.Context) ([]string, error) {
 	// 获取pending消息列表
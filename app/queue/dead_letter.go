@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DeadLetterStream 死信队列 Stream 名称
+const DeadLetterStream = "push:stream:dead_letter"
+
+// ErrDeadLetterNotFound 死信消息不存在
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// 死信消息附加字段
+const (
+	DeadLetterFieldError      = "dl_error"       // 最后一次错误
+	DeadLetterFieldOriginalID = "dl_original_id" // 原消息 ID
+	DeadLetterFieldConsumer   = "dl_consumer"    // 移入死信时的消费者
+	DeadLetterFieldDeadAt     = "dl_dead_at"     // 移入死信时间（Unix 秒）
+)
+
+// DeadLetter 死信消息
+type DeadLetter struct {
+	ID         string
+	TaskID     string
+	Error      string
+	OriginalID string
+	Consumer   string
+	DeadAt     time.Time
+	Data       map[string]interface{}
+}
+
+// DeadLetterQueue 死信队列管理
+type DeadLetterQueue struct {
+	redis      *redis.Client
+	streamName string
+}
+
+// NewDeadLetterQueue 创建死信队列管理
+func NewDeadLetterQueue(redisClient *redis.Client) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		redis:      redisClient,
+		streamName: DeadLetterStream,
+	}
+}
+
+// Len 获取死信队列长度
+func (q *DeadLetterQueue) Len(ctx context.Context) (int64, error) {
+	return q.redis.XLen(ctx, q.streamName).Result()
+}
+
+// List 按时间倒序分页获取死信消息
+// before 为上一页最后一条消息 ID（为空表示从最新开始），返回的 next 为下一页游标（为空表示没有更多）
+func (q *DeadLetterQueue) List(ctx context.Context, before string, count int64) ([]*DeadLetter, string, error) {
+	end := "+"
+	if before != "" {
+		end = "(" + before
+	}
+
+	msgs, err := q.redis.XRevRangeN(ctx, q.streamName, end, "-", count).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	letters := make([]*DeadLetter, 0, len(msgs))
+	for _, msg := range msgs {
+		letters = append(letters, newDeadLetter(msg))
+	}
+
+	next := ""
+	if int64(len(msgs)) == count {
+		next = msgs[len(msgs)-1].ID
+	}
+
+	return letters, next, nil
+}
+
+// Range 按时间范围正序获取死信消息（用于批量重放）
+// start/end 为零值时表示不限，after 为上一批最后一条消息 ID（为空表示从头开始）
+func (q *DeadLetterQueue) Range(ctx context.Context, start, end time.Time, after string, count int64) ([]*DeadLetter, error) {
+	startID, endID := "-", "+"
+	if after != "" {
+		startID = "(" + after
+	} else if !start.IsZero() {
+		startID = fmt.Sprintf("%d-0", start.UnixMilli())
+	}
+	if !end.IsZero() {
+		endID = strconv.FormatInt(end.UnixMilli(), 10) // 不带序号的结束 ID 包含该毫秒内全部消息
+	}
+
+	msgs, err := q.redis.XRangeN(ctx, q.streamName, startID, endID, count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]*DeadLetter, 0, len(msgs))
+	for _, msg := range msgs {
+		letters = append(letters, newDeadLetter(msg))
+	}
+	return letters, nil
+}
+
+// Get 获取单条死信消息
+func (q *DeadLetterQueue) Get(ctx context.Context, id string) (*DeadLetter, error) {
+	msgs, err := q.redis.XRangeN(ctx, q.streamName, id, id, 1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msgs) == 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	return newDeadLetter(msgs[0]), nil
+}
+
+// Delete 删除死信消息
+func (q *DeadLetterQueue) Delete(ctx context.Context, ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return q.redis.XDel(ctx, q.streamName, ids...).Result()
+}
+
+// TrimBefore 删除早于指定时间的死信消息，返回删除数量
+func (q *DeadLetterQueue) TrimBefore(ctx context.Context, before time.Time) (int64, error) {
+	return q.redis.XTrimMinID(ctx, q.streamName, fmt.Sprintf("%d-0", before.UnixMilli())).Result()
+}
+
+// TrimMaxLen 仅保留最新的 maxLen 条死信消息，返回删除数量
+func (q *DeadLetterQueue) TrimMaxLen(ctx context.Context, maxLen int64) (int64, error) {
+	return q.redis.XTrimMaxLen(ctx, q.streamName, maxLen).Result()
+}
+
+// Purge 清空死信队列，返回删除数量
+func (q *DeadLetterQueue) Purge(ctx context.Context) (int64, error) {
+	length, err := q.Len(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := q.redis.Del(ctx, q.streamName).Err(); err != nil {
+		return 0, err
+	}
+
+	return length, nil
+}
+
+// newDeadLetter 转换 Stream 消息为死信消息
+func newDeadLetter(msg redis.XMessage) *DeadLetter {
+	letter := &DeadLetter{
+		ID:   msg.ID,
+		Data: msg.Values,
+	}
+
+	if v, ok := msg.Values["task_id"].(string); ok {
+		letter.TaskID = v
+	}
+	if v, ok := msg.Values[DeadLetterFieldError].(string); ok {
+		letter.Error = v
+	}
+	if v, ok := msg.Values[DeadLetterFieldOriginalID].(string); ok {
+		letter.OriginalID = v
+	}
+	if v, ok := msg.Values[DeadLetterFieldConsumer].(string); ok {
+		letter.Consumer = v
+	}
+	if v, ok := msg.Values[DeadLetterFieldDeadAt].(string); ok {
+		if ts, err := strconv.ParseInt(v, 10, 64); err == nil {
+			letter.DeadAt = time.Unix(ts, 0)
+		}
+	}
+
+	// 旧版本死信消息没有记录时间，使用消息 ID 中的毫秒时间戳
+	if letter.DeadAt.IsZero() {
+		ms, _, _ := strings.Cut(msg.ID, "-")
+		if ts, err := strconv.ParseInt(ms, 10, 64); err == nil {
+			letter.DeadAt = time.UnixMilli(ts)
+		}
+	}
+
+	return letter
+}
@@ -0,0 +1,116 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 重试队列 key
+const (
+	RetryQueueKey           = "push:retry:tasks"
+	RetryQueueProcessingKey = "push:retry:processing"
+)
+
+// 定时任务队列 key
+const (
+	ScheduledQueueKey           = "push:scheduled:tasks"
+	ScheduledQueueProcessingKey = "push:scheduled:processing"
+)
+
+// claimScript 原子领取到期成员：从待处理集合移入处理中集合（score 为租约到期时间）
+var claimScript = redis.NewScript(`
+	local pending = KEYS[1]
+	local processing = KEYS[2]
+	local now = tonumber(ARGV[1])
+	local limit = tonumber(ARGV[2])
+	local leaseUntil = tonumber(ARGV[3])
+
+	local members = redis.call('ZRANGEBYSCORE', pending, '-inf', now, 'LIMIT', 0, limit)
+	for _, member in ipairs(members) do
+		redis.call('ZREM', pending, member)
+		redis.call('ZADD', processing, leaseUntil, member)
+	end
+	return members
+`)
+
+// recoverScript 将租约过期的成员放回待处理集合（领取方崩溃后恢复）
+var recoverScript = redis.NewScript(`
+	local pending = KEYS[1]
+	local processing = KEYS[2]
+	local now = tonumber(ARGV[1])
+
+	local members = redis.call('ZRANGEBYSCORE', processing, '-inf', now)
+	for _, member in ipairs(members) do
+		redis.call('ZREM', processing, member)
+		redis.call('ZADD', pending, now, member)
+	end
+	return #members
+`)
+
+// DelayQueue 基于 Redis Sorted Set 的延迟队列
+// 成员在到期后由扫描器领取，领取期间带租约，处理方崩溃后租约过期会被重新放回队列
+type DelayQueue struct {
+	redis         *redis.Client
+	pendingKey    string
+	processingKey string
+}
+
+// NewDelayQueue 创建延迟队列
+func NewDelayQueue(redisClient *redis.Client, pendingKey, processingKey string) *DelayQueue {
+	return &DelayQueue{
+		redis:         redisClient,
+		pendingKey:    pendingKey,
+		processingKey: processingKey,
+	}
+}
+
+// NewRetryQueue 创建任务重试延迟队列
+func NewRetryQueue(redisClient *redis.Client) *DelayQueue {
+	return NewDelayQueue(redisClient, RetryQueueKey, RetryQueueProcessingKey)
+}
+
+// NewScheduledQueue 创建定时任务延迟队列
+func NewScheduledQueue(redisClient *redis.Client) *DelayQueue {
+	return NewDelayQueue(redisClient, ScheduledQueueKey, ScheduledQueueProcessingKey)
+}
+
+// Add 添加成员，到期时间为 at（重复添加会覆盖到期时间）
+func (q *DelayQueue) Add(ctx context.Context, member string, at time.Time) error {
+	return q.redis.ZAdd(ctx, q.pendingKey, redis.Z{
+		Score:  float64(at.Unix()),
+		Member: member,
+	}).Err()
+}
+
+// Claim 领取到期成员，lease 为处理租约时长
+func (q *DelayQueue) Claim(ctx context.Context, limit int, lease time.Duration) ([]string, error) {
+	now := time.Now()
+	return claimScript.Run(ctx, q.redis, []string{q.pendingKey, q.processingKey},
+		now.Unix(), limit, now.Add(lease).Unix()).StringSlice()
+}
+
+// Ack 确认成员处理完成
+func (q *DelayQueue) Ack(ctx context.Context, member string) error {
+	return q.redis.ZRem(ctx, q.processingKey, member).Err()
+}
+
+// Recover 恢复租约过期的成员，返回恢复数量
+func (q *DelayQueue) Recover(ctx context.Context) (int64, error) {
+	return recoverScript.Run(ctx, q.redis, []string{q.pendingKey, q.processingKey}, time.Now().Unix()).Int64()
+}
+
+// Remove 移除成员（待处理与处理中）
+func (q *DelayQueue) Remove(ctx context.Context, member string) error {
+	pipe := q.redis.Pipeline()
+	pipe.ZRem(ctx, q.pendingKey, member)
+	pipe.ZRem(ctx, q.processingKey, member)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Len 获取待处理成员数量
+func (q *DelayQueue) Len(ctx context.Context) (int64, error) {
+	return q.redis.ZCard(ctx, q.pendingKey).Result()
+}
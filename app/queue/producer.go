@@ -55,7 +55,7 @@ func (p *Producer) pushScheduled(ctx context.Context, task *model.PushTask) erro
 	// 推送到Sorted Set，score为定时发送时间
 	score := float64(task.ScheduledAt.Unix())
 
-	return p.redis.ZAdd(ctx, "push:scheduled:tasks", redis.Z{
+	return p.redis.ZAdd(ctx, ScheduledQueueKey, redis.Z{
 		Score:  score,
 		Member: task.TaskID,
 	}).Err()
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 )
 
@@ -12,11 +13,13 @@ type ProviderMeta struct {
 	Type         string        `json:"type"`          // 消息类型：sms, email, wechat_work, dingtalk, webhook, push
 	Description  string        `json:"description"`   // 服务商描述
 	ConfigFields []ConfigField `json:"config_fields"` // 配置参数定义
+	MessageTypes []string      `json:"message_types"` // 支持的全部消息类型（为空时仅支持 Type，如模拟服务商支持所有类型）
 
 	// 能力声明
 	SupportsSend        bool `json:"supports_send"`         // 是否支持单条发送
 	SupportsBatchSend   bool `json:"supports_batch_send"`   // 是否支持批量发送
 	SupportsCallback    bool `json:"supports_callback"`     // 是否支持回调
+	SupportsInbound     bool `json:"supports_inbound"`      // 是否支持上行消息（用户回复，阿里云、腾讯云短信）
 	SupportsStatusQuery bool `json:"supports_status_query"` // 是否支持单条状态查询（阿里云、腾讯云）
 	SupportsStatusPull  bool `json:"supports_status_pull"`  // 是否支持批量状态拉取（掌榕网）
 
@@ -32,6 +35,14 @@ type ProviderMeta struct {
 	Deprecated bool     `json:"deprecated"`  // 是否已弃用
 }
 
+// SupportsMessageType 是否支持指定消息类型
+func (m *ProviderMeta) SupportsMessageType(msgType string) bool {
+	if len(m.MessageTypes) == 0 {
+		return m.Type == msgType
+	}
+	return slices.Contains(m.MessageTypes, msgType)
+}
+
 // ProviderRegistry 服务商注册表
 type ProviderRegistry struct {
 	mu        sync.RWMutex
@@ -110,7 +121,7 @@ func (r *ProviderRegistry) GetByType(msgType string) []*ProviderMeta {
 
 	result := make([]*ProviderMeta, 0)
 	for _, meta := range r.providers {
-		if meta.Type == msgType {
+		if meta.SupportsMessageType(msgType) {
 			result = append(result, meta)
 		}
 	}
@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+	"github.com/redis/go-redis/v9"
+)
+
+// LeaderKey 调度器 leader 租约 key
+const LeaderKey = "push:scheduler:leader"
+
+// renewScript 仅当租约仍由当前实例持有时续期
+var renewScript = redis.NewScript(`
+	if redis.call('GET', KEYS[1]) == ARGV[1] then
+		return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	end
+	return 0
+`)
+
+// releaseScript 仅当租约仍由当前实例持有时释放
+var releaseScript = redis.NewScript(`
+	if redis.call('GET', KEYS[1]) == ARGV[1] then
+		return redis.call('DEL', KEYS[1])
+	end
+	return 0
+`)
+
+// LeaderInfo leader 信息
+type LeaderInfo struct {
+	InstanceID string        // 持有租约的实例，为空表示当前无 leader
+	TTL        time.Duration // 租约剩余时长
+}
+
+// GetLeaderInfo 获取当前 leader 信息
+func GetLeaderInfo(ctx context.Context, redisClient *redis.Client) (*LeaderInfo, error) {
+	pipe := redisClient.Pipeline()
+	getCmd := pipe.Get(ctx, LeaderKey)
+	ttlCmd := pipe.PTTL(ctx, LeaderKey)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	info := &LeaderInfo{}
+	if instanceID, err := getCmd.Result(); err == nil {
+		info.InstanceID = instanceID
+		if ttl, err := ttlCmd.Result(); err == nil && ttl > 0 {
+			info.TTL = ttl
+		}
+	}
+
+	return info, nil
+}
+
+// LeaderElector 基于 Redis 租约的 leader 选举
+// 多实例部署时只有 leader 执行全局性的定时任务（如短信超时扫描、配额同步），租约到期未续期时由其他实例接管
+type LeaderElector struct {
+	logger     gsr.Logger
+	redis      *redis.Client
+	instanceID string
+	ttl        time.Duration // 租约时长
+	interval   time.Duration // 竞选/续期间隔
+	isLeader   atomic.Bool
+	stopCh     chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewLeaderElector 创建 leader 选举器
+func NewLeaderElector(instanceID string) *LeaderElector {
+	h := helper.GetHelper()
+	ttl := time.Duration(h.GetEnv().GetInt("scheduler.leader_ttl", 30)) * time.Second
+	return &LeaderElector{
+		logger:     h.GetLogger(),
+		redis:      h.GetRedis(),
+		instanceID: instanceID,
+		ttl:        ttl,
+		interval:   ttl / 3, // 租约时长内至少续期两次，避免网络抖动导致失去 leader
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start 启动选举
+// 启动时先同步竞选一次，保证随后启动的组件能拿到准确的 leader 状态
+func (e *LeaderElector) Start(ctx context.Context) error {
+	e.elect(ctx)
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.elect(ctx)
+			case <-e.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	e.logger.Info(fmt.Sprintf("leader elector started instance=%s ttl=%v", e.instanceID, e.ttl))
+	return nil
+}
+
+// Stop 停止选举并主动释放租约，使其他实例尽快接管
+func (e *LeaderElector) Stop() {
+	close(e.stopCh)
+	e.wg.Wait()
+
+	if e.isLeader.Load() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := releaseScript.Run(ctx, e.redis, []string{LeaderKey}, e.instanceID).Err(); err != nil {
+			e.logger.Warn(fmt.Sprintf("failed to release leader lease: %v", err))
+		}
+		e.isLeader.Store(false)
+	}
+
+	e.logger.Info("leader elector stopped")
+}
+
+// IsLeader 当前实例是否为 leader
+func (e *LeaderElector) IsLeader() bool {
+	return e.isLeader.Load()
+}
+
+// InstanceID 当前实例标识
+func (e *LeaderElector) InstanceID() string {
+	return e.instanceID
+}
+
+// elect 竞选或续期
+func (e *LeaderElector) elect(ctx context.Context) {
+	if e.isLeader.Load() {
+		renewed, err := renewScript.Run(ctx, e.redis, []string{LeaderKey}, e.instanceID, e.ttl.Milliseconds()).Int64()
+		if err != nil {
+			// Redis 异常时无法确认租约仍然有效，放弃 leader 身份，避免与其他实例同时执行
+			e.logger.Error(fmt.Sprintf("failed to renew leader lease: %v", err))
+			e.setLeader(false)
+			return
+		}
+		e.setLeader(renewed == 1)
+		return
+	}
+
+	acquired, err := e.redis.SetNX(ctx, LeaderKey, e.instanceID, e.ttl).Result()
+	if err != nil {
+		e.logger.Error(fmt.Sprintf("failed to acquire leader lease: %v", err))
+		return
+	}
+	e.setLeader(acquired)
+}
+
+// setLeader 更新 leader 状态并记录变化
+func (e *LeaderElector) setLeader(leader bool) {
+	if e.isLeader.Swap(leader) == leader {
+		return
+	}
+
+	if leader {
+		e.logger.Info(fmt.Sprintf("became scheduler leader instance=%s", e.instanceID))
+	} else {
+		e.logger.Warn(fmt.Sprintf("lost scheduler leadership instance=%s", e.instanceID))
+	}
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/sender"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// CallbackHandleFunc 回调处理函数（CallbackService.HandleCallback）
+type CallbackHandleFunc func(ctx context.Context, providerCode string, req *sender.CallbackRequest) sender.CallbackResponse
+
+// MockCallbackDispatcher 模拟回调投递器
+// 将模拟服务商登记的到期回调交给回调服务处理；回调按条原子领取，多实例可同时运行
+type MockCallbackDispatcher struct {
+	logger   gsr.Logger
+	handle   CallbackHandleFunc
+	interval time.Duration // 扫描间隔
+	limit    int           // 单次处理数量
+	stopCh   chan struct{}
+}
+
+// NewMockCallbackDispatcher 创建模拟回调投递器
+func NewMockCallbackDispatcher(handle CallbackHandleFunc) *MockCallbackDispatcher {
+	return &MockCallbackDispatcher{
+		logger:   helper.GetHelper().GetLogger(),
+		handle:   handle,
+		interval: time.Second, // 每秒扫描一次
+		limit:    500,         // 每次最多处理500条
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start 启动投递器
+func (d *MockCallbackDispatcher) Start(ctx context.Context) error {
+	d.logger.Info("mock callback dispatcher started")
+
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.dispatch(ctx)
+			case <-d.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop 停止投递器
+func (d *MockCallbackDispatcher) Stop() {
+	close(d.stopCh)
+	d.logger.Info("mock callback dispatcher stopped")
+}
+
+// dispatch 投递到期的模拟回调
+func (d *MockCallbackDispatcher) dispatch(ctx context.Context) {
+	payloads, err := sender.PopDueMockCallbacks(ctx, d.limit)
+	if err != nil {
+		d.logger.Error(fmt.Sprintf("failed to pop mock callbacks: %v", err))
+		return
+	}
+
+	for _, payload := range payloads {
+		d.handle(ctx, constants.ProviderMock, &sender.CallbackRequest{
+			ProviderCode: constants.ProviderMock,
+			RawBody:      payload,
+		})
+	}
+}
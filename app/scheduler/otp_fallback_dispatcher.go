@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// OTPFallbackFunc 验证码备用通道处理函数（OTPService.ProcessDueFallbacks）
+type OTPFallbackFunc func(ctx context.Context, limit int)
+
+// OTPFallbackDispatcher 验证码备用通道调度器
+// 定期处理到期的验证码，首次发送未送达时由验证码服务通过备用通道重发；检查项按条原子领取，多实例可同时运行
+type OTPFallbackDispatcher struct {
+	logger   gsr.Logger
+	process  OTPFallbackFunc
+	interval time.Duration // 扫描间隔
+	limit    int           // 单次处理数量
+	stopCh   chan struct{}
+}
+
+// NewOTPFallbackDispatcher 创建验证码备用通道调度器
+func NewOTPFallbackDispatcher(process OTPFallbackFunc) *OTPFallbackDispatcher {
+	return &OTPFallbackDispatcher{
+		logger:   helper.GetHelper().GetLogger(),
+		process:  process,
+		interval: time.Second, // 每秒扫描一次
+		limit:    100,         // 每次最多处理100条
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start 启动调度器
+func (d *OTPFallbackDispatcher) Start(ctx context.Context) error {
+	d.logger.Info("otp fallback dispatcher started")
+
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.process(ctx, d.limit)
+			case <-d.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop 停止调度器
+func (d *OTPFallbackDispatcher) Stop() {
+	close(d.stopCh)
+	d.logger.Info("otp fallback dispatcher stopped")
+}
@@ -16,23 +16,26 @@ import (
 )
 
 // QuotaSyncer 配额同步器
+// 多实例下仅由 leader 执行
 type QuotaSyncer struct {
 	logger   gsr.Logger
 	redis    *redis.Client
 	db       *gorm.DB
 	appDao   *dao.ApplicationDAO
+	leader   *LeaderElector
 	interval time.Duration
 	stopCh   chan struct{}
 }
 
 // NewQuotaSyncer 创建同步器
-func NewQuotaSyncer() *QuotaSyncer {
+func NewQuotaSyncer(leader *LeaderElector) *QuotaSyncer {
 	h := helper.GetHelper()
 	return &QuotaSyncer{
 		logger:   h.GetLogger(),
 		redis:    h.GetRedis(),
 		db:       h.GetDatabase(),
 		appDao:   dao.NewApplicationDAO(),
+		leader:   leader,
 		interval: 1 * time.Hour, // 每小时同步一次
 		stopCh:   make(chan struct{}),
 	}
@@ -72,6 +75,10 @@ func (s *QuotaSyncer) Stop() {
 
 // sync 同步配额数据
 func (s *QuotaSyncer) sync(ctx context.Context) {
+	if !s.leader.IsLeader() {
+		return
+	}
+
 	s.logger.Info("starting quota sync...")
 	if !helper.GetHelper().GetConfig().GetBool("app.installed", false) {
 		helper.GetHelper().GetLogger().Warn("数据库未安装，不执行")
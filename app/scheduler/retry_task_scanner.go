@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/queue"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// RetryTaskScanner 重试任务扫描器
+// 从 Redis 重试延迟队列中领取到期任务并重新推送到消息队列，多实例下通过原子领取避免重复推送
+type RetryTaskScanner struct {
+	logger     gsr.Logger
+	retryQueue *queue.DelayQueue
+	producer   *queue.Producer
+	taskDao    *dao.PushTaskDAO
+	interval   time.Duration // 扫描间隔
+	lease      time.Duration // 领取租约时长
+	limit      int           // 单次处理数量
+	stopCh     chan struct{}
+}
+
+// NewRetryTaskScanner 创建重试任务扫描器
+func NewRetryTaskScanner() *RetryTaskScanner {
+	h := helper.GetHelper()
+	return &RetryTaskScanner{
+		logger:     h.GetLogger(),
+		retryQueue: queue.NewRetryQueue(h.GetRedis()),
+		producer:   queue.NewProducer(h.GetRedis()),
+		taskDao:    dao.NewPushTaskDAO(),
+		interval:   1 * time.Second,  // 每秒扫描一次，保证短延迟重试的时效
+		lease:      60 * time.Second, // 领取后60秒未确认视为处理方异常，重新放回队列
+		limit:      100,              // 每次最多处理100个
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start 启动扫描器
+func (s *RetryTaskScanner) Start(ctx context.Context) error {
+	s.logger.Info("retry task scanner started")
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.scan(ctx)
+			case <-s.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop 停止扫描器
+func (s *RetryTaskScanner) Stop() {
+	close(s.stopCh)
+	s.logger.Info("retry task scanner stopped")
+}
+
+// scan 扫描到期的重试任务
+func (s *RetryTaskScanner) scan(ctx context.Context) {
+	// 恢复租约过期（领取后未确认）的任务
+	if recovered, err := s.retryQueue.Recover(ctx); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to recover retry tasks: %v", err))
+	} else if recovered > 0 {
+		s.logger.Warn(fmt.Sprintf("recovered %d retry tasks with expired lease", recovered))
+	}
+
+	taskIDs, err := s.retryQueue.Claim(ctx, s.limit, s.lease)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to claim retry tasks: %v", err))
+		return
+	}
+
+	if len(taskIDs) == 0 {
+		return
+	}
+
+	s.logger.Info(fmt.Sprintf("claimed %d retry tasks to process", len(taskIDs)))
+
+	for _, taskID := range taskIDs {
+		task, err := s.taskDao.GetByTaskID(taskID)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to get retry task id=%s: %v", taskID, err))
+			s.retryQueue.Ack(ctx, taskID)
+			continue
+		}
+
+		// 任务已结束（如管理员手动处理或接收者已被屏蔽），无需重试
+		if task.Status == constants.TaskStatusSuccess || task.Status == constants.TaskStatusFailed || task.Status == constants.TaskStatusSuppressed {
+			s.retryQueue.Ack(ctx, taskID)
+			continue
+		}
+
+		// 推送前清除下次重试时间（推送后 worker 可能立即更新任务）
+		if err := s.taskDao.ClearNextRetryAt(taskID); err != nil {
+			s.logger.Warn(fmt.Sprintf("failed to clear next retry time task_id=%s: %v", taskID, err))
+		}
+
+		// 推送失败不确认，租约过期后重新领取
+		if err := s.producer.Push(ctx, task); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to push retry task id=%s to queue: %v", taskID, err))
+			continue
+		}
+
+		s.retryQueue.Ack(ctx, taskID)
+
+		s.logger.Info(fmt.Sprintf("retry task pushed to queue: task_id=%s retry_count=%d", taskID, task.RetryCount))
+	}
+}
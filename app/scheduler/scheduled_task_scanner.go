@@ -9,29 +9,33 @@ import (
 	"cnb.cool/mliev/push/message-push/app/queue"
 	"cnb.cool/mliev/push/message-push/internal/helper"
 	"github.com/muleiwu/gsr"
-	"github.com/redis/go-redis/v9"
 )
 
 // ScheduledTaskScanner 定时任务扫描器
+// 到期任务通过延迟队列原子领取，多实例同时运行也不会重复推送
 type ScheduledTaskScanner struct {
-	logger   gsr.Logger
-	redis    *redis.Client
-	producer *queue.Producer
-	taskDao  *dao.PushTaskDAO
-	interval time.Duration
-	stopCh   chan struct{}
+	logger         gsr.Logger
+	scheduledQueue *queue.DelayQueue
+	producer       *queue.Producer
+	taskDao        *dao.PushTaskDAO
+	interval       time.Duration
+	lease          time.Duration // 领取租约时长
+	limit          int           // 单次处理数量
+	stopCh         chan struct{}
 }
 
 // NewScheduledTaskScanner 创建扫描器
 func NewScheduledTaskScanner() *ScheduledTaskScanner {
 	h := helper.GetHelper()
 	return &ScheduledTaskScanner{
-		logger:   h.GetLogger(),
-		redis:    h.GetRedis(),
-		producer: queue.NewProducer(h.GetRedis()),
-		taskDao:  dao.NewPushTaskDAO(),
-		interval: 10 * time.Second, // 每10秒扫描一次
-		stopCh:   make(chan struct{}),
+		logger:         h.GetLogger(),
+		scheduledQueue: queue.NewScheduledQueue(h.GetRedis()),
+		producer:       queue.NewProducer(h.GetRedis()),
+		taskDao:        dao.NewPushTaskDAO(),
+		interval:       10 * time.Second, // 每10秒扫描一次
+		lease:          60 * time.Second, // 领取后60秒未确认视为处理方异常，重新放回队列
+		limit:          100,              // 每次最多处理100个
+		stopCh:         make(chan struct{}),
 	}
 }
 
@@ -66,50 +70,44 @@ func (s *ScheduledTaskScanner) Stop() {
 
 // scan 扫描到期任务
 func (s *ScheduledTaskScanner) scan(ctx context.Context) {
-	now := time.Now().Unix()
-	sortedSetKey := "push:scheduled:tasks"
-
-	// 获取到期的任务（score <= now）
-	results, err := s.redis.ZRangeByScoreWithScores(ctx, sortedSetKey, &redis.ZRangeBy{
-		Min:   "-inf",
-		Max:   fmt.Sprintf("%d", now),
-		Count: 100, // 每次最多处理100个
-	}).Result()
+	// 恢复租约过期（领取后未确认）的任务
+	if recovered, err := s.scheduledQueue.Recover(ctx); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to recover scheduled tasks: %v", err))
+	} else if recovered > 0 {
+		s.logger.Warn(fmt.Sprintf("recovered %d scheduled tasks with expired lease", recovered))
+	}
 
+	// 原子领取到期的任务，多实例下每个任务只会被一个实例领取
+	taskIDs, err := s.scheduledQueue.Claim(ctx, s.limit, s.lease)
 	if err != nil {
-		if err != redis.Nil {
-			s.logger.Error(fmt.Sprintf("failed to scan scheduled tasks: %v", err))
-		}
+		s.logger.Error(fmt.Sprintf("failed to claim scheduled tasks: %v", err))
 		return
 	}
 
-	if len(results) == 0 {
+	if len(taskIDs) == 0 {
 		return
 	}
 
-	s.logger.Info(fmt.Sprintf("found %d scheduled tasks to process", len(results)))
+	s.logger.Info(fmt.Sprintf("found %d scheduled tasks to process", len(taskIDs)))
 
 	// 处理每个到期任务
-	for _, result := range results {
-		taskID := result.Member.(string)
-
+	for _, taskID := range taskIDs {
 		// 从数据库获取任务
 		task, err := s.taskDao.GetByTaskID(taskID)
 		if err != nil {
 			s.logger.Error(fmt.Sprintf("failed to get task id=%s: %v", taskID, err))
 			// 删除无效的任务ID
-			s.redis.ZRem(ctx, sortedSetKey, taskID)
+			s.scheduledQueue.Ack(ctx, taskID)
 			continue
 		}
 
-		// 推送到队列
+		// 推送到队列，失败不确认，租约过期后重新领取
 		if err := s.producer.Push(ctx, task); err != nil {
 			s.logger.Error(fmt.Sprintf("failed to push task id=%s to queue: %v", taskID, err))
 			continue
 		}
 
-		// 从sorted set中删除
-		s.redis.ZRem(ctx, sortedSetKey, taskID)
+		s.scheduledQueue.Ack(ctx, taskID)
 
 		s.logger.Info(fmt.Sprintf("scheduled task pushed to queue: task_id=%s", taskID))
 	}
@@ -7,15 +7,21 @@ import (
 
 	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/model"
 	"cnb.cool/mliev/push/message-push/internal/helper"
 	"github.com/muleiwu/gsr"
 )
 
+// TaskExpiredHandler 任务等待回执超时的处理函数（用于发出 task.expired 事件）
+type TaskExpiredHandler func(ctx context.Context, task *model.PushTask) error
+
 // SMSTimeoutScanner 短信超时扫描器
-// 用于处理长时间处于 sent 状态未收到回调的短信任务
+// 用于处理长时间处于 sent 状态未收到回调的短信任务，多实例下仅由 leader 执行
 type SMSTimeoutScanner struct {
 	logger   gsr.Logger
 	taskDao  *dao.PushTaskDAO
+	leader   *LeaderElector
+	onExpire TaskExpiredHandler
 	interval time.Duration // 扫描间隔
 	timeout  time.Duration // 超时阈值
 	limit    int           // 单次处理数量
@@ -23,11 +29,13 @@ type SMSTimeoutScanner struct {
 }
 
 // NewSMSTimeoutScanner 创建短信超时扫描器
-func NewSMSTimeoutScanner() *SMSTimeoutScanner {
+func NewSMSTimeoutScanner(leader *LeaderElector, onExpire TaskExpiredHandler) *SMSTimeoutScanner {
 	h := helper.GetHelper()
 	return &SMSTimeoutScanner{
 		logger:   h.GetLogger(),
 		taskDao:  dao.NewPushTaskDAO(),
+		leader:   leader,
+		onExpire: onExpire,
 		interval: 10 * time.Second, // 每10秒扫描一次
 		timeout:  60 * time.Second, // 60秒未收到回调视为超时
 		limit:    100,              // 每次最多处理100个
@@ -66,6 +74,10 @@ func (s *SMSTimeoutScanner) Stop() {
 
 // scan 扫描超时任务
 func (s *SMSTimeoutScanner) scan(ctx context.Context) {
+	if !s.leader.IsLeader() {
+		return
+	}
+
 	// 获取超时的 sent 状态任务
 	tasks, err := s.taskDao.GetTimeoutSentTasks(s.timeout, s.limit)
 	if err != nil {
@@ -81,16 +93,25 @@ func (s *SMSTimeoutScanner) scan(ctx context.Context) {
 
 	// 处理每个超时任务：保持 sent 状态，仅更新回调状态为超时
 	for _, task := range tasks {
-		task.CallbackStatus = constants.CallbackStatusTimeout
-
 		now := time.Now()
-		task.CallbackTime = &now
-
-		if err := s.taskDao.Update(task); err != nil {
+		// 条件更新：期间已收到回执或已被标记超时时跳过，避免重复推送过期事件
+		updated, err := s.taskDao.MarkCallbackTimeout(task.TaskID, now)
+		if err != nil {
 			s.logger.Error(fmt.Sprintf("failed to update timeout task task_id=%s: %v", task.TaskID, err))
 			continue
 		}
+		if !updated {
+			continue
+		}
+		task.CallbackStatus = constants.CallbackStatusTimeout
+		task.CallbackTime = &now
 
 		s.logger.Info(fmt.Sprintf("timeout task callback_status marked as timeout: task_id=%s", task.TaskID))
+
+		if s.onExpire != nil {
+			if err := s.onExpire(ctx, task); err != nil {
+				s.logger.Error(fmt.Sprintf("failed to handle expired task task_id=%s: %v", task.TaskID, err))
+			}
+		}
 	}
 }
@@ -0,0 +1,245 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/app/sender"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// StatusResultHandler 状态结果处理函数（与回调走同一处理流程），返回实际处理的结果数量
+type StatusResultHandler func(ctx context.Context, providerCode string, results []*sender.StatusQueryResult) int
+
+// StatusReconciler 状态对账器
+// 对已发送但未收到回执的任务主动查询（StatusQuerier）或批量拉取（StatusPuller）状态，多实例下仅由 leader 执行
+type StatusReconciler struct {
+	logger        gsr.Logger
+	taskDao       *dao.PushTaskDAO
+	logDao        *dao.PushLogDAO
+	accountDao    *dao.ProviderAccountDAO
+	senderFactory *sender.Factory
+	leader        *LeaderElector
+	handler       StatusResultHandler
+	interval      time.Duration // 对账间隔
+	minAge        time.Duration // 发送后超过该时长仍无回执才主动查询
+	maxAge        time.Duration // 超过该时长的任务不再查询
+	limit         int           // 单次查询任务数量
+	queryGap      time.Duration // 同一服务商账号两次查询的最小间隔（限流）
+	lastTaskID    uint          // 轮询游标，保证所有待对账任务都能被轮到
+	nextQueryAt   map[uint]time.Time
+	stopCh        chan struct{}
+}
+
+// NewStatusReconciler 创建状态对账器
+func NewStatusReconciler(leader *LeaderElector, handler StatusResultHandler) *StatusReconciler {
+	h := helper.GetHelper()
+	env := h.GetEnv()
+
+	qps := env.GetInt("reconcile.query_qps", 5)
+	if qps <= 0 {
+		qps = 5
+	}
+
+	return &StatusReconciler{
+		logger:        h.GetLogger(),
+		taskDao:       dao.NewPushTaskDAO(),
+		logDao:        dao.NewPushLogDAO(),
+		accountDao:    dao.NewProviderAccountDAO(),
+		senderFactory: sender.NewFactory(),
+		leader:        leader,
+		handler:       handler,
+		interval:      time.Duration(env.GetInt("reconcile.interval", 60)) * time.Second,
+		minAge:        time.Duration(env.GetInt("reconcile.min_age", 120)) * time.Second,
+		maxAge:        time.Duration(env.GetInt("reconcile.max_age", 172800)) * time.Second,
+		limit:         env.GetInt("reconcile.batch_size", 100),
+		queryGap:      time.Second / time.Duration(qps),
+		nextQueryAt:   make(map[uint]time.Time),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Start 启动对账器
+func (s *StatusReconciler) Start(ctx context.Context) error {
+	s.logger.Info("status reconciler started")
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.reconcile(ctx)
+			case <-s.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop 停止对账器
+func (s *StatusReconciler) Stop() {
+	close(s.stopCh)
+	s.logger.Info("status reconciler stopped")
+}
+
+// reconcile 执行一次对账
+func (s *StatusReconciler) reconcile(ctx context.Context) {
+	if !s.leader.IsLeader() {
+		return
+	}
+
+	s.pull(ctx)
+	s.query(ctx)
+}
+
+// pull 对仅支持批量拉取的服务商，逐个账号拉取待处理状态
+func (s *StatusReconciler) pull(ctx context.Context) {
+	for _, puller := range s.senderFactory.GetAllStatusPullers() {
+		providerCode := puller.GetProviderCode()
+
+		accounts, err := s.accountDao.GetByProviderCode(providerCode)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("failed to get provider accounts provider=%s: %v", providerCode, err))
+			continue
+		}
+
+		for _, account := range accounts {
+			if !s.wait(ctx, account.ID) {
+				return
+			}
+
+			resp, err := s.pullAccount(ctx, puller, account)
+			if err != nil {
+				s.logger.Error(fmt.Sprintf("failed to pull status provider=%s account_id=%d: %v", providerCode, account.ID, err))
+				continue
+			}
+
+			if len(resp.Results) == 0 {
+				continue
+			}
+
+			processed := s.handler(ctx, providerCode, resp.Results)
+			s.logger.Info(fmt.Sprintf("pulled status provider=%s account_id=%d results=%d processed=%d",
+				providerCode, account.ID, len(resp.Results), processed))
+		}
+	}
+}
+
+// pullAccount 拉取单个账号的状态
+func (s *StatusReconciler) pullAccount(ctx context.Context, puller sender.StatusPuller, account *model.ProviderAccount) (*sender.StatusQueryResponse, error) {
+	pullCtx, cancel := context.WithTimeout(ctx, sender.DefaultTimeout*time.Second)
+	defer cancel()
+
+	return puller.PullStatus(pullCtx, &sender.StatusPullRequest{ProviderAccount: account})
+}
+
+// query 对支持单条查询的服务商，逐个查询未收到回执的任务
+func (s *StatusReconciler) query(ctx context.Context) {
+	now := time.Now()
+	tasks, err := s.taskDao.GetUnconfirmedSentTasks(s.lastTaskID, now.Add(-s.maxAge), now.Add(-s.minAge), s.limit)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to get unconfirmed sent tasks: %v", err))
+		return
+	}
+
+	// 不足一批说明已轮询到末尾，下次从头开始
+	if len(tasks) < s.limit {
+		s.lastTaskID = 0
+	} else {
+		s.lastTaskID = tasks[len(tasks)-1].ID
+	}
+
+	if len(tasks) == 0 {
+		return
+	}
+
+	accounts := make(map[uint]*model.ProviderAccount)
+	processed := 0
+
+	for _, task := range tasks {
+		logs, err := s.logDao.GetByTaskID(task.TaskID)
+		if err != nil || len(logs) == 0 {
+			continue
+		}
+		pushLog := logs[0] // 最新的发送日志
+		if pushLog.ProviderMsgID == "" {
+			continue
+		}
+
+		account, ok := accounts[pushLog.ProviderAccountID]
+		if !ok {
+			account, err = s.accountDao.GetByID(pushLog.ProviderAccountID)
+			if err != nil {
+				s.logger.Warn(fmt.Sprintf("provider account not found id=%d task_id=%s", pushLog.ProviderAccountID, task.TaskID))
+			}
+			accounts[pushLog.ProviderAccountID] = account
+		}
+		if account == nil {
+			continue
+		}
+
+		// 不支持单条查询的服务商（如仅支持拉取）跳过
+		querier, err := s.senderFactory.GetStatusQuerier(account.ProviderCode)
+		if err != nil {
+			continue
+		}
+
+		if !s.wait(ctx, account.ID) {
+			return
+		}
+
+		resp, err := s.queryTask(ctx, querier, account, task, pushLog)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("failed to query status task_id=%s provider=%s: %v", task.TaskID, account.ProviderCode, err))
+			continue
+		}
+
+		processed += s.handler(ctx, account.ProviderCode, resp.Results)
+	}
+
+	if processed > 0 {
+		s.logger.Info(fmt.Sprintf("status reconciled by query checked=%d processed=%d", len(tasks), processed))
+	}
+}
+
+// queryTask 查询单个任务的状态
+func (s *StatusReconciler) queryTask(ctx context.Context, querier sender.StatusQuerier, account *model.ProviderAccount, task *model.PushTask, pushLog *model.PushLog) (*sender.StatusQueryResponse, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, sender.DefaultTimeout*time.Second)
+	defer cancel()
+
+	return querier.QueryStatus(queryCtx, &sender.StatusQueryRequest{
+		ProviderAccount: account,
+		ProviderMsgID:   pushLog.ProviderMsgID,
+		PhoneNumber:     task.Receiver,
+		SendDate:        pushLog.CreatedAt,
+	})
+}
+
+// wait 按服务商账号限流，返回 false 表示对账器已停止
+func (s *StatusReconciler) wait(ctx context.Context, accountID uint) bool {
+	if delay := time.Until(s.nextQueryAt[accountID]); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-s.stopCh:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	s.nextQueryAt[accountID] = time.Now().Add(s.queryGap)
+	return true
+}
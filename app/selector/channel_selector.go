@@ -2,18 +2,24 @@ package selector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"cnb.cool/mliev/push/message-push/app/circuit"
 	"cnb.cool/mliev/push/message-push/app/dao"
 	"cnb.cool/mliev/push/message-push/app/model"
 	"cnb.cool/mliev/push/message-push/internal/helper"
 	"github.com/muleiwu/gsr"
+	"github.com/redis/go-redis/v9"
 )
 
+// ErrAllBreakersOpen 通道下所有可用节点均处于熔断（或半开探测名额已满），稍后可恢复
+var ErrAllBreakersOpen = errors.New("all providers are circuit broken")
+
 // 缓存 key 前缀
 const (
 	cacheKeyPrefix        = "channel_selector:"
@@ -45,17 +51,39 @@ type ChannelSelector struct {
 	cache                     gsr.Cacher    // 使用统一缓存接口
 	cacheTTL                  time.Duration // 缓存过期时间
 	weightMu                  sync.Mutex    // 保护权重修改的并发安全
+	redis                     *redis.Client
+	breakerConfig             BreakerConfig // 熔断器配置
+}
+
+// BreakerConfig 熔断器配置（每个通道模板绑定一个熔断器，状态通过 Redis 跨实例共享）
+type BreakerConfig struct {
+	Enabled          bool
+	Threshold        float64       // 失败率阈值
+	MinRequests      uint32        // 最小请求数
+	HalfOpenRequests uint32        // 半开状态探测请求数
+	Interval         time.Duration // 统计窗口
+	Timeout          time.Duration // 熔断持续时间
 }
 
 // NewChannelSelector 创建通道选择器
 func NewChannelSelector() *ChannelSelector {
 	h := helper.GetHelper()
+	env := h.GetEnv()
 	return &ChannelSelector{
 		logger:                    h.GetLogger(),
 		channelTemplateBindingDao: dao.NewChannelTemplateBindingDAO(),
 		providerAccountDAO:        dao.NewProviderAccountDAO(),
 		cache:                     h.GetCache(),
 		cacheTTL:                  30 * time.Second, // 默认30秒
+		redis:                     h.GetRedis(),
+		breakerConfig: BreakerConfig{
+			Enabled:          env.GetBool("circuit_breaker.enabled", true),
+			Threshold:        env.GetFloat64("circuit_breaker.threshold", 0.5),
+			MinRequests:      uint32(env.GetInt("circuit_breaker.min_requests", 10)),
+			HalfOpenRequests: uint32(env.GetInt("circuit_breaker.half_open_requests", 3)),
+			Interval:         time.Duration(env.GetInt("circuit_breaker.interval", 60)) * time.Second,
+			Timeout:          time.Duration(env.GetInt("circuit_breaker.timeout", 60)) * time.Second,
+		},
 	}
 }
 
@@ -91,12 +119,37 @@ func (s *ChannelSelector) SelectWithExcludes(ctx context.Context, channelID uint
 		s.logger.Info(fmt.Sprintf("filtered excluded providers, remaining nodes=%d, excluded=%v", len(nodes), excludeProviderIDs))
 	}
 
+	// 过滤熔断中的节点
+	nodes = s.filterOpenBreakers(ctx, nodes)
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w for channel_id=%d", ErrAllBreakersOpen, channelID)
+	}
+
 	// 获取上次使用的供应商 ID（5 分钟内同一接收者切换供应商）
 	lastProviderID := s.getLastProviderID(ctx, appID, channelID, receiver)
 
 	// 使用平滑加权轮询选择（权重状态持久化到 Redis）
-	selected := s.smoothWeightedRoundRobin(ctx, channelID, nodes, lastProviderID)
+	// 选中节点需通过熔断器放行（半开状态仅放行有限的探测请求），否则剔除后重新选择
+	var selected *ChannelNode
+	breakerRejected := false
+	for len(nodes) > 0 {
+		candidate := s.smoothWeightedRoundRobin(ctx, channelID, nodes, lastProviderID)
+		if candidate == nil {
+			break
+		}
+		if err := s.allowBreaker(ctx, candidate); err != nil {
+			s.logger.Info(fmt.Sprintf("skip node binding_id=%d: %v", candidate.ChannelTemplateBinding.ID, err))
+			nodes = removeNode(nodes, candidate)
+			breakerRejected = true
+			continue
+		}
+		selected = candidate
+		break
+	}
 	if selected == nil {
+		if breakerRejected && len(nodes) == 0 {
+			return nil, fmt.Errorf("%w for channel_id=%d", ErrAllBreakersOpen, channelID)
+		}
 		return nil, fmt.Errorf("failed to select channel")
 	}
 
@@ -405,14 +458,108 @@ func (s *ChannelSelector) ResetWeightsByChannelID(channelID uint) {
 	s.logger.Info(fmt.Sprintf("weight states reset for channel_id=%d, cleared %d bindings", channelID, len(bindings)))
 }
 
-// ReportSuccess 报告成功
-func (s *ChannelSelector) ReportSuccess(providerAccountID uint) {
-	// TODO: record success to circuit breaker and auto-enable if disabled
+// ReportSuccess 报告成功（计入节点熔断器）
+func (s *ChannelSelector) ReportSuccess(node *ChannelNode) {
+	s.reportBreaker(node, true)
+}
+
+// ReportFailure 报告失败（计入节点熔断器）
+func (s *ChannelSelector) ReportFailure(node *ChannelNode) {
+	s.reportBreaker(node, false)
+}
+
+// BreakerTimeout 熔断持续时间（熔断后经过该时长进入半开探测）
+func (s *ChannelSelector) BreakerTimeout() time.Duration {
+	return s.breakerConfig.Timeout
+}
+
+// GetBreaker 获取通道模板绑定对应的熔断器
+func (s *ChannelSelector) GetBreaker(bindingID uint) *circuit.RedisBreaker {
+	return circuit.NewRedisBreaker(s.redis, fmt.Sprintf("binding:%d", bindingID)).
+		WithThreshold(s.breakerConfig.Threshold).
+		WithMinRequests(s.breakerConfig.MinRequests).
+		WithMaxRequests(s.breakerConfig.HalfOpenRequests).
+		WithInterval(s.breakerConfig.Interval).
+		WithTimeout(s.breakerConfig.Timeout).
+		WithOnStateChange(func(name string, from circuit.State, to circuit.State) {
+			s.logger.Warn(fmt.Sprintf("circuit breaker %s state changed: %s -> %s", name, from, to))
+		})
+}
+
+// GetBreakerSnapshot 获取通道模板绑定的熔断器状态
+func (s *ChannelSelector) GetBreakerSnapshot(ctx context.Context, bindingID uint) (*circuit.Snapshot, error) {
+	return s.GetBreaker(bindingID).Snapshot(ctx)
+}
+
+// ResetBreaker 手动重置通道模板绑定的熔断器
+func (s *ChannelSelector) ResetBreaker(ctx context.Context, bindingID uint) error {
+	if err := s.GetBreaker(bindingID).ResetContext(ctx); err != nil {
+		return err
+	}
+	s.logger.Info(fmt.Sprintf("circuit breaker reset for binding_id=%d", bindingID))
+	return nil
+}
+
+// filterOpenBreakers 过滤熔断器处于打开状态的节点
+func (s *ChannelSelector) filterOpenBreakers(ctx context.Context, nodes []*ChannelNode) []*ChannelNode {
+	if !s.breakerConfig.Enabled {
+		return nodes
+	}
+
+	var available []*ChannelNode
+	for _, node := range nodes {
+		snapshot, err := s.GetBreakerSnapshot(ctx, node.ChannelTemplateBinding.ID)
+		if err != nil {
+			// Redis 异常时不阻断发送
+			s.logger.Warn(fmt.Sprintf("failed to get circuit breaker state binding_id=%d: %v", node.ChannelTemplateBinding.ID, err))
+			available = append(available, node)
+			continue
+		}
+		if snapshot.State == circuit.StateOpen {
+			continue
+		}
+		available = append(available, node)
+	}
+	return available
+}
+
+// allowBreaker 节点熔断器放行检查
+func (s *ChannelSelector) allowBreaker(ctx context.Context, node *ChannelNode) error {
+	if !s.breakerConfig.Enabled || node.ChannelTemplateBinding == nil {
+		return nil
+	}
+
+	err := s.GetBreaker(node.ChannelTemplateBinding.ID).Allow(ctx)
+	if errors.Is(err, circuit.ErrOpenState) || errors.Is(err, circuit.ErrTooManyRequests) {
+		return err
+	}
+	if err != nil {
+		// Redis 异常时不阻断发送
+		s.logger.Warn(fmt.Sprintf("circuit breaker allow check failed binding_id=%d: %v", node.ChannelTemplateBinding.ID, err))
+	}
+	return nil
 }
 
-// ReportFailure 报告失败
-func (s *ChannelSelector) ReportFailure(providerAccountID uint) {
-	// TODO: record failure to circuit breaker and auto-disable based on threshold
+// reportBreaker 上报节点发送结果
+func (s *ChannelSelector) reportBreaker(node *ChannelNode, success bool) {
+	if !s.breakerConfig.Enabled || node == nil || node.ChannelTemplateBinding == nil {
+		return
+	}
+
+	if err := s.GetBreaker(node.ChannelTemplateBinding.ID).Report(context.Background(), success); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to report circuit breaker binding_id=%d: %v", node.ChannelTemplateBinding.ID, err))
+	}
+}
+
+// removeNode 从节点列表中移除指定节点
+func removeNode(nodes []*ChannelNode, target *ChannelNode) []*ChannelNode {
+	var remaining []*ChannelNode
+	for _, node := range nodes {
+		if node != target {
+			remaining = append(remaining, node)
+		}
+	}
+	return remaining
 }
 
 // ClearCache 清除所有缓存
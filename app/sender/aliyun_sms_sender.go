@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"cnb.cool/mliev/push/message-push/app/constants"
@@ -11,6 +12,7 @@ import (
 
 	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
 	dysmsapi "github.com/alibabacloud-go/dysmsapi-20170525/v3/client"
+	util "github.com/alibabacloud-go/tea-utils/v2/service"
 	"github.com/alibabacloud-go/tea/tea"
 )
 
@@ -21,7 +23,7 @@ func init() {
 		Name:        "阿里云短信",
 		Type:        constants.MessageTypeSMS,
 		Description: "阿里云短信服务，支持国内短信和国际短信发送。注意：短信签名需在「签名管理」中单独配置",
-		ConfigFields: []registry.ConfigField{
+		ConfigFields: append([]registry.ConfigField{
 			{
 				Key:            "access_key_id",
 				Label:          "AccessKeyID",
@@ -44,11 +46,13 @@ func init() {
 				ValidationRule: "min:16,max:64",
 				HelpLink:       "https://help.aliyun.com/document_detail/53045.html",
 			},
-		},
+			endpointConfigField(AliyunSMSDefaultEndpoint),
+		}, httpClientConfigFields()...),
 		// 能力声明
 		SupportsSend:        true,
 		SupportsBatchSend:   true,
 		SupportsCallback:    true,
+		SupportsInbound:     true,
 		SupportsStatusQuery: true,
 		// 扩展信息
 		Website:    "https://www.aliyun.com/product/sms",
@@ -93,7 +97,7 @@ func (s *AliyunSMSSender) Send(ctx context.Context, req *SendRequest) (*SendResp
 	}
 
 	// 2. 初始化客户端
-	client, err := s.createClient(accessKeyID, accessKeySecret)
+	client, runtime, err := s.createClient(ctx, config, accessKeyID, accessKeySecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create aliyun sms client: %w", err)
 	}
@@ -137,6 +141,11 @@ func (s *AliyunSMSSender) Send(ctx context.Context, req *SendRequest) (*SendResp
 		sendRequest.TemplateParam = tea.String(templateParamStr)
 	}
 
+	// 上行扩展码（用户回复时原样带回，用于关联原始任务）
+	if extendCode := bindingExtendCode(req.ChannelTemplateBinding); extendCode != "" {
+		sendRequest.SmsUpExtendCode = tea.String(extendCode)
+	}
+
 	// 4. 序列化请求数据用于日志
 	requestData, _ := json.Marshal(map[string]interface{}{
 		"phone_numbers":   req.Task.Receiver,
@@ -146,7 +155,7 @@ func (s *AliyunSMSSender) Send(ctx context.Context, req *SendRequest) (*SendResp
 	})
 
 	// 5. 发送
-	response, err := client.SendSms(sendRequest)
+	response, err := client.SendSmsWithOptions(sendRequest, runtime)
 	if err != nil {
 		return &SendResponse{
 			Success:      false,
@@ -192,14 +201,45 @@ func (s *AliyunSMSSender) Send(ctx context.Context, req *SendRequest) (*SendResp
 	}, nil
 }
 
-// createClient 创建阿里云短信客户端
-func (s *AliyunSMSSender) createClient(accessKeyID, accessKeySecret string) (*dysmsapi.Client, error) {
-	config := &openapi.Config{
+// createClient 创建阿里云短信客户端及请求运行参数
+// SDK 不支持 context，读取超时按 ctx 截止时间收紧
+func (s *AliyunSMSSender) createClient(ctx context.Context, config map[string]interface{}, accessKeyID, accessKeySecret string) (*dysmsapi.Client, *util.RuntimeOptions, error) {
+	opts, err := ParseHTTPClientOptions(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scheme, host := splitEndpoint(configEndpoint(config, AliyunSMSDefaultEndpoint))
+	clientConfig := &openapi.Config{
 		AccessKeyId:     tea.String(accessKeyID),
 		AccessKeySecret: tea.String(accessKeySecret),
-		Endpoint:        tea.String("dysmsapi.aliyuncs.com"),
+		Endpoint:        tea.String(host),
+		Protocol:        tea.String(scheme),
+		MaxIdleConns:    tea.Int(opts.MaxIdleConns),
+	}
+	if opts.ProxyURL != "" {
+		if strings.HasPrefix(opts.ProxyURL, "socks5://") {
+			clientConfig.Socks5Proxy = tea.String(opts.ProxyURL)
+		} else {
+			clientConfig.HttpProxy = tea.String(opts.ProxyURL)
+			clientConfig.HttpsProxy = tea.String(opts.ProxyURL)
+		}
+	}
+
+	client, err := dysmsapi.NewClient(clientConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runtime := &util.RuntimeOptions{
+		ConnectTimeout: tea.Int(int(opts.ConnectTimeout.Milliseconds())),
+		ReadTimeout:    tea.Int(int(opts.TimeoutFor(ctx).Milliseconds())),
+		IgnoreSSL:      tea.Bool(opts.InsecureSkipVerify),
 	}
-	return dysmsapi.NewClient(config)
+	if opts.CACert != "" {
+		runtime.Ca = tea.String(opts.CACert)
+	}
+	return client, runtime, nil
 }
 
 // ==================== BatchSender 接口实现 ====================
@@ -229,7 +269,7 @@ func (s *AliyunSMSSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 	}
 
 	// 2. 初始化客户端
-	client, err := s.createClient(accessKeyID, accessKeySecret)
+	client, runtime, err := s.createClient(ctx, config, accessKeyID, accessKeySecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create aliyun sms client: %w", err)
 	}
@@ -289,6 +329,16 @@ func (s *AliyunSMSSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 		TemplateParamJson: tea.String(string(templateParamsJSON)),
 	}
 
+	// 上行扩展码（每个号码对应一个扩展码）
+	if extendCode := bindingExtendCode(req.ChannelTemplateBinding); extendCode != "" {
+		extendCodes := make([]string, len(req.Tasks))
+		for i := range extendCodes {
+			extendCodes[i] = extendCode
+		}
+		extendCodesJSON, _ := json.Marshal(extendCodes)
+		batchRequest.SmsUpExtendCodeJson = tea.String(string(extendCodesJSON))
+	}
+
 	// 4. 序列化请求数据用于日志
 	requestData, _ := json.Marshal(map[string]interface{}{
 		"phone_numbers":   phoneNumbers,
@@ -298,7 +348,7 @@ func (s *AliyunSMSSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 	})
 
 	// 5. 发送
-	response, err := client.SendBatchSms(batchRequest)
+	response, err := client.SendBatchSmsWithOptions(batchRequest, runtime)
 	if err != nil {
 		// 如果批量发送失败，返回所有任务都失败的结果
 		results := make([]*SendResponse, len(req.Tasks))
@@ -395,7 +445,7 @@ func (s *AliyunSMSSender) QueryStatus(ctx context.Context, req *StatusQueryReque
 	}
 
 	// 2. 初始化客户端
-	client, err := s.createClient(accessKeyID, accessKeySecret)
+	client, runtime, err := s.createClient(ctx, config, accessKeyID, accessKeySecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create aliyun sms client: %w", err)
 	}
@@ -414,7 +464,7 @@ func (s *AliyunSMSSender) QueryStatus(ctx context.Context, req *StatusQueryReque
 	}
 
 	// 4. 发送查询请求
-	response, err := client.QuerySendDetails(queryRequest)
+	response, err := client.QuerySendDetailsWithOptions(queryRequest, runtime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query send details: %w", err)
 	}
@@ -517,3 +567,53 @@ func (s *AliyunSMSSender) HandleCallback(ctx context.Context, req *CallbackReque
 
 	return resp, results, nil
 }
+
+// ==================== InboundHandler 接口实现 ====================
+
+// SupportsInbound 是否支持上行消息
+func (s *AliyunSMSSender) SupportsInbound() bool {
+	return true
+}
+
+// HandleInbound 处理阿里云短信上行消息（SmsUp）推送
+// 阿里云上行消息格式：
+// [{"phone_number":"1381111****","send_time":"2017-01-01 00:00:00","content":"TD","sign_name":"签名","dest_code":"1234","sequence_id":1234567890}]
+// 其中 dest_code 为发送时指定的上行扩展码
+func (s *AliyunSMSSender) HandleInbound(ctx context.Context, req *CallbackRequest) (CallbackResponse, []*InboundMessage, error) {
+	// 阿里云期望返回 {"code" : 0, "msg" : "接收成功"}
+	resp := CallbackResponse{
+		StatusCode: 200,
+		Body:       `{"code" : 0, "msg" : "接收成功"}`,
+	}
+
+	var reports []struct {
+		PhoneNumber string      `json:"phone_number"`
+		SendTime    string      `json:"send_time"`
+		Content     string      `json:"content"`
+		SignName    string      `json:"sign_name"`
+		DestCode    string      `json:"dest_code"`
+		SequenceID  json.Number `json:"sequence_id"`
+	}
+
+	if err := json.Unmarshal(req.RawBody, &reports); err != nil {
+		// 即使解析失败也返回成功响应，避免服务商重复推送
+		return resp, nil, fmt.Errorf("invalid inbound data: %w", err)
+	}
+
+	messages := make([]*InboundMessage, 0, len(reports))
+	for _, report := range reports {
+		receiveTime, _ := time.ParseInLocation("2006-01-02 15:04:05", report.SendTime, time.Local)
+
+		messages = append(messages, &InboundMessage{
+			Phone:       report.PhoneNumber,
+			Content:     report.Content,
+			SignName:    report.SignName,
+			ExtendCode:  report.DestCode,
+			DestCode:    report.DestCode,
+			SequenceID:  report.SequenceID.String(),
+			ReceiveTime: receiveTime,
+		})
+	}
+
+	return resp, messages, nil
+}
@@ -0,0 +1,263 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/registry"
+)
+
+func init() {
+	// 注册钉钉群机器人服务商
+	registry.Register(&registry.ProviderMeta{
+		Code:        constants.ProviderDingTalkRobot,
+		Name:        "钉钉群机器人",
+		Type:        constants.MessageTypeDingTalk,
+		Description: "钉钉群自定义机器人，支持文本、Markdown、链接和ActionCard消息，接收者填写需要 @ 的手机号或用户ID",
+		ConfigFields: append([]registry.ConfigField{
+			{
+				Key:         "webhook_url",
+				Label:       "Webhook地址",
+				Description: "群机器人的Webhook地址（包含 access_token 参数）",
+				Type:        registry.FieldTypeURL,
+				Required:    true,
+				Example:     "https://oapi.dingtalk.com/robot/send?access_token=xxxxxxxx",
+				Placeholder: "请输入Webhook地址",
+				HelpLink:    "https://open.dingtalk.com/document/robots/custom-robot-access",
+			},
+			{
+				Key:         "secret",
+				Label:       "加签密钥",
+				Description: "机器人安全设置中的加签密钥（SEC开头）",
+				Type:        registry.FieldTypePassword,
+				Required:    false,
+				Example:     "SECxxxxxxxxxxxxxxxx",
+				Placeholder: "请输入加签密钥",
+				HelpLink:    "https://open.dingtalk.com/document/robots/customize-robot-security-settings",
+			},
+			{
+				Key:          "msg_type",
+				Label:        "消息类型",
+				Description:  "text（文本）、markdown、link（链接）或 action_card（ActionCard）；链接和卡片使用模板参数 url、pic_url、button_title",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "text",
+				DefaultValue: "text",
+			},
+		}, httpClientConfigFields()...),
+		// 能力声明
+		SupportsSend:      true,
+		SupportsBatchSend: false,
+		SupportsCallback:  false,
+		// 扩展信息
+		Website:    "https://www.dingtalk.com/",
+		Icon:       "https://www.dingtalk.com/favicon.ico",
+		DocsUrl:    "https://open.dingtalk.com/document/robots/custom-robot-access",
+		ConsoleUrl: "",
+		PricingUrl: "",
+		SortOrder:  41,
+		Tags:       []string{"企业", "即时通讯", "机器人"},
+		Regions:    []string{"中国大陆"},
+		Deprecated: false,
+	})
+}
+
+// DingTalkRobotSender 钉钉群机器人发送器
+type DingTalkRobotSender struct{}
+
+// NewDingTalkRobotSender 创建钉钉群机器人发送器
+func NewDingTalkRobotSender() *DingTalkRobotSender {
+	return &DingTalkRobotSender{}
+}
+
+// GetProviderCode 获取服务商代码
+func (s *DingTalkRobotSender) GetProviderCode() string {
+	return constants.ProviderDingTalkRobot
+}
+
+// Send 发送群机器人消息
+func (s *DingTalkRobotSender) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	config, err := req.ProviderAccount.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider config: %w", err)
+	}
+
+	webhookURL := configString(config, "webhook_url")
+	if webhookURL == "" {
+		return nil, fmt.Errorf("missing dingtalk robot config: webhook_url")
+	}
+
+	msgType := configString(config, "msg_type")
+	if msgType == "" {
+		msgType = "text"
+	}
+
+	payload, err := s.buildPayload(req, msgType)
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed, retryAfter := allowRobotSend(ctx, constants.ProviderDingTalkRobot, webhookURL); !allowed {
+		return robotRateLimitedResponse(req.Task.TaskID, retryAfter), nil
+	}
+
+	// 配置了加签密钥时在地址上追加 timestamp 和 sign
+	apiURL := webhookURL
+	if secret := configString(config, "secret"); secret != "" {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		separator := "&"
+		if !strings.Contains(apiURL, "?") {
+			separator = "?"
+		}
+		apiURL += fmt.Sprintf("%stimestamp=%s&sign=%s", separator, timestamp, url.QueryEscape(s.sign(timestamp, secret)))
+	}
+
+	body, _ := json.Marshal(payload)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client, err := httpClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var respData struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(respBody, &respData); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	if respData.ErrCode != 0 {
+		return &SendResponse{
+			Success:      false,
+			ErrorCode:    strconv.Itoa(respData.ErrCode),
+			ErrorMessage: respData.ErrMsg,
+			TaskID:       req.Task.TaskID,
+			RequestData:  string(body),
+			ResponseData: string(respBody),
+		}, nil
+	}
+
+	return &SendResponse{
+		Success:      true,
+		ProviderID:   fmt.Sprintf("dingtalk_robot_%s", req.Task.TaskID), // 群机器人不返回消息ID
+		TaskID:       req.Task.TaskID,
+		Status:       constants.TaskStatusSuccess, // 群机器人消息发送成功即完成
+		RequestData:  string(body),
+		ResponseData: string(respBody),
+	}, nil
+}
+
+// buildPayload 构造群机器人消息
+func (s *DingTalkRobotSender) buildPayload(req *SendRequest, msgType string) (map[string]interface{}, error) {
+	task := req.Task
+	mentions := parseRobotMentions(task.Receiver)
+	params := robotMessageParams(req)
+
+	content := task.Content
+	if content == "" {
+		content = task.Title
+	}
+	title := task.Title
+	if title == "" {
+		title = "通知"
+	}
+
+	// 文本和 Markdown 消息需要在正文中包含 @手机号/@用户ID 才会高亮显示
+	var mentionText strings.Builder
+	for _, mobile := range mentions.Mobiles {
+		mentionText.WriteString(" @" + mobile)
+	}
+	for _, userID := range mentions.UserIDs {
+		mentionText.WriteString(" @" + userID)
+	}
+
+	var payload map[string]interface{}
+	switch msgType {
+	case "text":
+		payload = map[string]interface{}{
+			"msgtype": "text",
+			"text": map[string]string{
+				"content": content + mentionText.String(),
+			},
+		}
+	case "markdown":
+		payload = map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"title": title,
+				"text":  content + mentionText.String(),
+			},
+		}
+	case "link":
+		if params["url"] == "" {
+			return nil, fmt.Errorf("missing template param: url")
+		}
+		payload = map[string]interface{}{
+			"msgtype": "link",
+			"link": map[string]string{
+				"title":      title,
+				"text":       content,
+				"messageUrl": params["url"],
+				"picUrl":     params["pic_url"],
+			},
+		}
+	case "action_card":
+		if params["url"] == "" {
+			return nil, fmt.Errorf("missing template param: url")
+		}
+		buttonTitle := params["button_title"]
+		if buttonTitle == "" {
+			buttonTitle = "查看详情"
+		}
+		payload = map[string]interface{}{
+			"msgtype": "actionCard",
+			"actionCard": map[string]string{
+				"title":       title,
+				"text":        content,
+				"singleTitle": buttonTitle,
+				"singleURL":   params["url"],
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported dingtalk robot msg_type: %s", msgType)
+	}
+
+	payload["at"] = map[string]interface{}{
+		"atMobiles": mentions.Mobiles,
+		"atUserIds": mentions.UserIDs,
+		"isAtAll":   mentions.All,
+	}
+
+	return payload, nil
+}
+
+// sign 计算加签：Base64(HMAC-SHA256(timestamp + "\n" + secret, secret))
+func (s *DingTalkRobotSender) sign(timestamp, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
@@ -12,7 +12,6 @@ import (
 
 	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/registry"
-	"cnb.cool/mliev/push/message-push/internal/helper"
 )
 
 func init() {
@@ -22,7 +21,7 @@ func init() {
 		Name:        "钉钉",
 		Type:        constants.MessageTypeDingTalk,
 		Description: "钉钉工作通知消息服务，支持文本和Markdown消息",
-		ConfigFields: []registry.ConfigField{
+		ConfigFields: append([]registry.ConfigField{
 			{
 				Key:         "app_key",
 				Label:       "应用AppKey",
@@ -52,7 +51,8 @@ func init() {
 				Example:     "123456789",
 				Placeholder: "请输入AgentId",
 			},
-		},
+			endpointConfigField(DingTalkDefaultEndpoint),
+		}, httpClientConfigFields()...),
 		// 能力声明
 		SupportsSend:      true,
 		SupportsBatchSend: true,
@@ -95,13 +95,7 @@ func (s *DingTalkSender) Send(ctx context.Context, req *SendRequest) (*SendRespo
 		return nil, fmt.Errorf("missing dingtalk config: app_key, app_secret or agent_id")
 	}
 
-	// 1. 获取 Access Token
-	token, err := s.getAccessToken(ctx, appKey, appSecret)
-	if err != nil {
-		return nil, err
-	}
-
-	// 2. 构造消息
+	// 1. 构造消息
 	// 支持 text, markdown
 	msgType := "text"
 	// 这里简单起见，默认text
@@ -130,15 +124,12 @@ func (s *DingTalkSender) Send(ctx context.Context, req *SendRequest) (*SendRespo
 
 	body, _ := json.Marshal(payload)
 
-	// 3. 发送请求
-	apiURL := fmt.Sprintf("https://oapi.dingtalk.com/topapi/message/corpconversation/asyncsend_v2?access_token=%s", token)
-	resp, err := http.Post(apiURL, "application/json", bytes.NewBuffer(body))
+	// 2. 发送请求
+	respBody, err := s.postMessage(ctx, config, appKey, appSecret, body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respBody, _ := io.ReadAll(resp.Body)
 	var respData struct {
 		ErrCode   int64  `json:"errcode"`
 		ErrMsg    string `json:"errmsg"`
@@ -167,43 +158,79 @@ func (s *DingTalkSender) Send(ctx context.Context, req *SendRequest) (*SendRespo
 	}, nil
 }
 
-func (s *DingTalkSender) getAccessToken(ctx context.Context, appKey, appSecret string) (string, error) {
-	redisClient := helper.GetHelper().GetRedis()
-	key := fmt.Sprintf("dingtalk:token:%s", appKey)
-
-	// 尝试从Redis获取
-	token, err := redisClient.Get(ctx, key).Result()
-	if err == nil && token != "" {
-		return token, nil
-	}
-
-	// 从API获取
-	apiURL := fmt.Sprintf("https://oapi.dingtalk.com/gettoken?appkey=%s&appsecret=%s", appKey, appSecret)
-	resp, err := http.Get(apiURL)
+// postMessage 发送工作通知请求，令牌无效时强制刷新并重试一次
+func (s *DingTalkSender) postMessage(ctx context.Context, config map[string]interface{}, appKey, appSecret string, body []byte) ([]byte, error) {
+	endpoint := configEndpoint(config, DingTalkDefaultEndpoint)
+	client, err := httpClientFor(config)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var data struct {
-		ErrCode     int    `json:"errcode"`
-		ErrMsg      string `json:"errmsg"`
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
-	}
+	key := AccessTokenKey(constants.ProviderDingTalk, endpoint, appKey, appSecret)
+	for attempt := 0; ; attempt++ {
+		token, err := s.getAccessToken(ctx, client, key, endpoint, appKey, appSecret)
+		if err != nil {
+			return nil, err
+		}
 
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", err
-	}
+		apiURL := fmt.Sprintf("%s/topapi/message/corpconversation/asyncsend_v2?access_token=%s", endpoint, token)
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
 
-	if data.ErrCode != 0 {
-		return "", fmt.Errorf("failed to get access token: %s", data.ErrMsg)
+		var respData struct {
+			ErrCode int `json:"errcode"`
+		}
+		_ = json.Unmarshal(respBody, &respData)
+		if attempt == 0 && isInvalidTokenCode(dingTalkInvalidTokenCodes, respData.ErrCode) {
+			GetTokenManager().Invalidate(ctx, key, token)
+			continue
+		}
+		return respBody, nil
 	}
+}
+
+// getAccessToken 获取 Access Token（由令牌管理器缓存）
+func (s *DingTalkSender) getAccessToken(ctx context.Context, client *http.Client, key, endpoint, appKey, appSecret string) (string, error) {
+	return GetTokenManager().Get(ctx, key, func(ctx context.Context) (string, time.Duration, error) {
+		apiURL := fmt.Sprintf("%s/gettoken?appkey=%s&appsecret=%s", endpoint, appKey, appSecret)
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+		if err != nil {
+			return "", 0, err
+		}
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return "", 0, err
+		}
+		defer resp.Body.Close()
+
+		var data struct {
+			ErrCode     int    `json:"errcode"`
+			ErrMsg      string `json:"errmsg"`
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int    `json:"expires_in"`
+		}
 
-	// 缓存到Redis
-	redisClient.Set(ctx, key, data.AccessToken, time.Duration(data.ExpiresIn-200)*time.Second)
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			return "", 0, err
+		}
+
+		if data.ErrCode != 0 {
+			return "", 0, fmt.Errorf("failed to get access token: %s", data.ErrMsg)
+		}
 
-	return data.AccessToken, nil
+		return data.AccessToken, time.Duration(data.ExpiresIn) * time.Second, nil
+	})
 }
 
 // ==================== BatchSender 接口实现 ====================
@@ -232,12 +259,6 @@ func (s *DingTalkSender) BatchSend(ctx context.Context, req *BatchSendRequest) (
 		return nil, fmt.Errorf("missing dingtalk config: app_key, app_secret or agent_id")
 	}
 
-	// 获取 Access Token
-	token, err := s.getAccessToken(ctx, appKey, appSecret)
-	if err != nil {
-		return nil, err
-	}
-
 	// 收集所有用户ID（用逗号分隔）
 	var userIDs []string
 	for _, task := range req.Tasks {
@@ -270,14 +291,11 @@ func (s *DingTalkSender) BatchSend(ctx context.Context, req *BatchSendRequest) (
 	body, _ := json.Marshal(payload)
 
 	// 发送请求
-	apiURL := fmt.Sprintf("https://oapi.dingtalk.com/topapi/message/corpconversation/asyncsend_v2?access_token=%s", token)
-	resp, err := http.Post(apiURL, "application/json", bytes.NewBuffer(body))
+	respBody, err := s.postMessage(ctx, config, appKey, appSecret, body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respBody, _ := io.ReadAll(resp.Body)
 	var respData struct {
 		ErrCode   int64  `json:"errcode"`
 		ErrMsg    string `json:"errmsg"`
@@ -0,0 +1,57 @@
+package sender
+
+import (
+	"fmt"
+	"strings"
+
+	"cnb.cool/mliev/push/message-push/app/registry"
+)
+
+// ConfigKeyEndpoint 服务商账号的 API 地址配置项
+// 留空时使用服务商默认地址，可指向沙箱环境、专有云地域或本地模拟服务
+const ConfigKeyEndpoint = "endpoint"
+
+// 各服务商默认 API 地址
+const (
+	AliyunSMSDefaultEndpoint  = "https://dysmsapi.aliyuncs.com"
+	TencentSMSDefaultEndpoint = "https://sms.tencentcloudapi.com"
+	ZrwinfoDefaultEndpoint    = "http://api.1cloudsp.com"
+	WeChatWorkDefaultEndpoint = "https://qyapi.weixin.qq.com"
+	DingTalkDefaultEndpoint   = "https://oapi.dingtalk.com"
+	FeishuDefaultEndpoint     = "https://open.feishu.cn"
+)
+
+// endpointConfigField 生成 API 地址配置项声明
+func endpointConfigField(defaultEndpoint string) registry.ConfigField {
+	return registry.ConfigField{
+		Key:         ConfigKeyEndpoint,
+		Label:       "API地址",
+		Description: fmt.Sprintf("可选，自定义 API 地址（如沙箱环境或模拟服务），留空使用默认地址 %s", defaultEndpoint),
+		Type:        registry.FieldTypeURL,
+		Required:    false,
+		Example:     defaultEndpoint,
+		Placeholder: "留空使用默认地址",
+	}
+}
+
+// configEndpoint 读取账号配置的 API 地址，未配置时返回默认地址
+// 未带协议时补全 https://，并去除末尾的斜杠
+func configEndpoint(config map[string]interface{}, defaultEndpoint string) string {
+	endpoint := strings.TrimSpace(configString(config, ConfigKeyEndpoint))
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	return strings.TrimRight(endpoint, "/")
+}
+
+// splitEndpoint 将 API 地址拆分为协议和主机（含端口与路径），供云厂商 SDK 使用
+func splitEndpoint(endpoint string) (scheme, host string) {
+	scheme, host, found := strings.Cut(endpoint, "://")
+	if !found {
+		return "https", endpoint
+	}
+	return strings.ToLower(scheme), host
+}
@@ -22,6 +22,11 @@ func NewFactory() *Factory {
 	factory.Register(NewZrwinfoSMSSender())
 	factory.Register(NewWeChatWorkSender())
 	factory.Register(NewDingTalkSender())
+	factory.Register(NewWeChatWorkRobotSender())
+	factory.Register(NewDingTalkRobotSender())
+	factory.Register(NewFeishuSender())
+	factory.Register(NewWebhookSender())
+	factory.Register(NewMockSender())
 
 	return factory
 }
@@ -89,6 +94,25 @@ func (f *Factory) GetAllCallbackHandlers() []CallbackHandler {
 	return handlers
 }
 
+// GetInboundHandler 根据服务商代码获取上行消息处理器
+func (f *Factory) GetInboundHandler(providerCode string) (InboundHandler, error) {
+	sender, exists := f.senders[providerCode]
+	if !exists {
+		return nil, fmt.Errorf("unknown provider code: %s", providerCode)
+	}
+
+	handler, ok := sender.(InboundHandler)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not implement InboundHandler", providerCode)
+	}
+
+	if !handler.SupportsInbound() {
+		return nil, fmt.Errorf("provider %s does not support inbound messages", providerCode)
+	}
+
+	return handler, nil
+}
+
 // GetStatusQuerier 根据服务商代码获取状态查询器
 func (f *Factory) GetStatusQuerier(providerCode string) (StatusQuerier, error) {
 	sender, exists := f.senders[providerCode]
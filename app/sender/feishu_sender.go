@@ -0,0 +1,555 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/registry"
+)
+
+func init() {
+	// 注册飞书服务商
+	registry.Register(&registry.ProviderMeta{
+		Code:        constants.ProviderFeishu,
+		Name:        "飞书",
+		Type:        constants.MessageTypeFeishu,
+		Description: "飞书应用消息和自定义机器人，支持文本、富文本和消息卡片",
+		ConfigFields: append([]registry.ConfigField{
+			{
+				Key:         "app_id",
+				Label:       "应用App ID",
+				Description: "飞书自建应用的App ID，用于发送应用消息（与机器人Webhook二选一）",
+				Type:        registry.FieldTypeText,
+				Required:    false,
+				Example:     "cli_a1b2c3d4e5f6g7h8",
+				Placeholder: "请输入App ID",
+				HelpLink:    "https://open.feishu.cn/document/server-docs/authentication-management/access-token/tenant_access_token_internal",
+			},
+			{
+				Key:         "app_secret",
+				Label:       "应用App Secret",
+				Description: "飞书自建应用的App Secret",
+				Type:        registry.FieldTypePassword,
+				Required:    false,
+				Example:     "xxxxxxxxxxxxxxxxxxxxxx",
+				Placeholder: "请输入App Secret",
+			},
+			{
+				Key:          "receive_id_type",
+				Label:        "接收者ID类型",
+				Description:  "应用消息的接收者ID类型：open_id, user_id, union_id, chat_id, email；为空时按接收者前缀自动识别（ou_/on_/oc_），接收者也可写作 chat_id:oc_xxx",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "open_id",
+				DefaultValue: "",
+			},
+			{
+				Key:         "webhook_url",
+				Label:       "机器人Webhook地址",
+				Description: "群自定义机器人的Webhook地址，未配置应用凭证时使用",
+				Type:        registry.FieldTypeURL,
+				Required:    false,
+				Example:     "https://open.feishu.cn/open-apis/bot/v2/hook/xxxxxxxx",
+				Placeholder: "请输入Webhook地址",
+				HelpLink:    "https://open.feishu.cn/document/client-docs/bot-v3/add-custom-bot",
+			},
+			{
+				Key:         "webhook_secret",
+				Label:       "机器人签名密钥",
+				Description: "机器人安全设置中的签名校验密钥",
+				Type:        registry.FieldTypePassword,
+				Required:    false,
+				Placeholder: "请输入签名密钥",
+			},
+			{
+				Key:          "msg_type",
+				Label:        "消息类型",
+				Description:  "text（文本）、post（富文本）或 interactive（消息卡片）；富文本和卡片的消息内容可直接使用飞书 JSON 结构",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "text",
+				DefaultValue: "text",
+			},
+			{
+				Key:         "verification_token",
+				Label:       "事件Verification Token",
+				Description: "事件订阅的Verification Token，用于校验已读回执回调",
+				Type:        registry.FieldTypePassword,
+				Required:    false,
+			},
+			{
+				Key:         "encrypt_key",
+				Label:       "事件Encrypt Key",
+				Description: "事件订阅的Encrypt Key，配置后回调内容按飞书加密方式解密",
+				Type:        registry.FieldTypePassword,
+				Required:    false,
+			},
+			endpointConfigField(FeishuDefaultEndpoint),
+		}, httpClientConfigFields()...),
+		// 能力声明
+		SupportsSend:      true,
+		SupportsBatchSend: false,
+		SupportsCallback:  true,
+		// 扩展信息
+		Website:    "https://www.feishu.cn/",
+		Icon:       "https://www.feishu.cn/favicon.ico",
+		DocsUrl:    "https://open.feishu.cn/document/server-docs/im-v1/message/create",
+		ConsoleUrl: "https://open.feishu.cn/app",
+		PricingUrl: "",
+		SortOrder:  45,
+		Tags:       []string{"企业", "即时通讯"},
+		Regions:    []string{"中国大陆", "国际"},
+		Deprecated: false,
+	})
+}
+
+const (
+	feishuAPIPrefix      = "/open-apis"
+	feishuEventURLVerify = "url_verification"
+	feishuEventRead      = "im.message.message_read_v1"
+)
+
+// FeishuSender 飞书发送器
+// 配置了 app_id/app_secret 时发送应用消息，否则通过群自定义机器人 Webhook 发送
+type FeishuSender struct{}
+
+// NewFeishuSender 创建飞书发送器
+func NewFeishuSender() *FeishuSender {
+	return &FeishuSender{}
+}
+
+// GetProviderCode 获取服务商代码
+func (s *FeishuSender) GetProviderCode() string {
+	return constants.ProviderFeishu
+}
+
+// Send 发送飞书消息
+func (s *FeishuSender) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	config, err := req.ProviderAccount.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider config: %w", err)
+	}
+
+	appID := configString(config, "app_id")
+	appSecret := configString(config, "app_secret")
+	webhookURL := configString(config, "webhook_url")
+
+	msgType := configString(config, "msg_type")
+	if msgType == "" {
+		msgType = "text"
+	}
+	content, err := s.buildContent(msgType, req.Task.Title, req.Task.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := httpClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case appID != "" && appSecret != "":
+		return s.sendAppMessage(ctx, client, req, configEndpoint(config, FeishuDefaultEndpoint)+feishuAPIPrefix, appID, appSecret, configString(config, "receive_id_type"), msgType, content)
+	case webhookURL != "":
+		return s.sendBotMessage(ctx, client, req, webhookURL, configString(config, "webhook_secret"), msgType, content)
+	default:
+		return nil, fmt.Errorf("missing feishu config: app_id and app_secret, or webhook_url")
+	}
+}
+
+// sendAppMessage 发送应用消息
+func (s *FeishuSender) sendAppMessage(ctx context.Context, client *http.Client, req *SendRequest, apiBase, appID, appSecret, receiveIDType, msgType string, content map[string]interface{}) (*SendResponse, error) {
+	receiveIDType, receiveID := s.resolveReceiver(req.Task.Receiver, receiveIDType)
+	if receiveID == "" {
+		return nil, fmt.Errorf("feishu receiver is empty")
+	}
+
+	// 应用消息的 content 为 JSON 字符串
+	contentJSON, _ := json.Marshal(content)
+	body, _ := json.Marshal(map[string]string{
+		"receive_id": receiveID,
+		"msg_type":   msgType,
+		"content":    string(contentJSON),
+	})
+
+	apiURL := fmt.Sprintf("%s/im/v1/messages?receive_id_type=%s", apiBase, receiveIDType)
+	tokenKey := AccessTokenKey(constants.ProviderFeishu, apiBase, appID, appSecret)
+
+	var respData struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+		Data struct {
+			MessageID string `json:"message_id"`
+		} `json:"data"`
+	}
+	var respBody []byte
+	for attempt := 0; ; attempt++ {
+		token, err := s.getTenantAccessToken(ctx, client, tokenKey, apiBase, appID, appSecret)
+		if err != nil {
+			return nil, err
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+
+		respBody, err = s.do(client, httpReq, &respData)
+		if err != nil {
+			return nil, err
+		}
+
+		// 令牌无效时强制刷新并重试一次
+		if attempt == 0 && isInvalidTokenCode(feishuInvalidTokenCodes, respData.Code) {
+			GetTokenManager().Invalidate(ctx, tokenKey, token)
+			continue
+		}
+		break
+	}
+
+	if respData.Code != 0 {
+		return &SendResponse{
+			Success:      false,
+			ErrorCode:    strconv.Itoa(respData.Code),
+			ErrorMessage: respData.Msg,
+			TaskID:       req.Task.TaskID,
+			RequestData:  string(body),
+			ResponseData: string(respBody),
+		}, nil
+	}
+
+	return &SendResponse{
+		Success:      true,
+		ProviderID:   respData.Data.MessageID, // 已读回执通过 message_id 关联
+		TaskID:       req.Task.TaskID,
+		Status:       constants.TaskStatusSuccess, // 飞书消息发送成功即完成
+		RequestData:  string(body),
+		ResponseData: string(respBody),
+	}, nil
+}
+
+// sendBotMessage 通过群自定义机器人发送消息
+func (s *FeishuSender) sendBotMessage(ctx context.Context, client *http.Client, req *SendRequest, webhookURL, secret, msgType string, content map[string]interface{}) (*SendResponse, error) {
+	payload := map[string]interface{}{
+		"msg_type": msgType,
+	}
+	// 机器人消息卡片使用 card 字段，富文本需包在 content.post 下，其余类型使用 content 字段
+	switch msgType {
+	case "interactive":
+		payload["card"] = content
+	case "post":
+		payload["content"] = map[string]interface{}{"post": content}
+	default:
+		payload["content"] = content
+	}
+
+	if secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		payload["timestamp"] = timestamp
+		payload["sign"] = s.botSign(timestamp, secret)
+	}
+
+	body, _ := json.Marshal(payload)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	var respData struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	respBody, err := s.do(client, httpReq, &respData)
+	if err != nil {
+		return nil, err
+	}
+
+	if respData.Code != 0 {
+		return &SendResponse{
+			Success:      false,
+			ErrorCode:    strconv.Itoa(respData.Code),
+			ErrorMessage: respData.Msg,
+			TaskID:       req.Task.TaskID,
+			RequestData:  string(body),
+			ResponseData: string(respBody),
+		}, nil
+	}
+
+	return &SendResponse{
+		Success:      true,
+		ProviderID:   fmt.Sprintf("feishu_%s", req.Task.TaskID), // 机器人消息不返回消息ID
+		TaskID:       req.Task.TaskID,
+		Status:       constants.TaskStatusSuccess,
+		RequestData:  string(body),
+		ResponseData: string(respBody),
+	}, nil
+}
+
+// buildContent 根据消息类型构造消息内容
+// 富文本和卡片消息的内容为合法 JSON 时直接使用，否则由标题和正文生成
+func (s *FeishuSender) buildContent(msgType, title, content string) (map[string]interface{}, error) {
+	switch msgType {
+	case "text":
+		if content == "" {
+			content = title
+		}
+		return map[string]interface{}{"text": content}, nil
+	case "post":
+		var post map[string]interface{}
+		if json.Unmarshal([]byte(content), &post) == nil && len(post) > 0 {
+			return post, nil
+		}
+		return map[string]interface{}{
+			"zh_cn": map[string]interface{}{
+				"title": title,
+				"content": [][]map[string]string{
+					{{"tag": "text", "text": content}},
+				},
+			},
+		}, nil
+	case "interactive":
+		var card map[string]interface{}
+		if json.Unmarshal([]byte(content), &card) == nil && len(card) > 0 {
+			return card, nil
+		}
+		card = map[string]interface{}{
+			"elements": []map[string]string{
+				{"tag": "markdown", "content": content},
+			},
+		}
+		if title != "" {
+			card["header"] = map[string]interface{}{
+				"title": map[string]string{"tag": "plain_text", "content": title},
+			}
+		}
+		return card, nil
+	default:
+		return nil, fmt.Errorf("unsupported feishu msg_type: %s", msgType)
+	}
+}
+
+// resolveReceiver 解析接收者ID类型
+// 优先使用接收者中的显式类型（如 chat_id:oc_xxx），其次使用配置，最后按ID前缀识别
+func (s *FeishuSender) resolveReceiver(receiver, configType string) (string, string) {
+	receiver = strings.TrimSpace(receiver)
+	if idType, id, found := strings.Cut(receiver, ":"); found {
+		switch idType {
+		case "open_id", "user_id", "union_id", "chat_id", "email":
+			return idType, id
+		}
+	}
+
+	if configType != "" {
+		return configType, receiver
+	}
+
+	switch {
+	case strings.HasPrefix(receiver, "ou_"):
+		return "open_id", receiver
+	case strings.HasPrefix(receiver, "on_"):
+		return "union_id", receiver
+	case strings.HasPrefix(receiver, "oc_"):
+		return "chat_id", receiver
+	case strings.Contains(receiver, "@"):
+		return "email", receiver
+	default:
+		return "user_id", receiver
+	}
+}
+
+// botSign 计算机器人签名：以 timestamp + "\n" + secret 为密钥对空串做 HMAC-SHA256，再 Base64
+func (s *FeishuSender) botSign(timestamp, secret string) string {
+	h := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// do 发送请求并解析 JSON 响应，返回原始响应体
+func (s *FeishuSender) do(client *http.Client, httpReq *http.Request, out interface{}) ([]byte, error) {
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return respBody, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return respBody, nil
+}
+
+// getTenantAccessToken 获取 tenant_access_token（由令牌管理器缓存）
+func (s *FeishuSender) getTenantAccessToken(ctx context.Context, client *http.Client, key, apiBase, appID, appSecret string) (string, error) {
+	return GetTokenManager().Get(ctx, key, func(ctx context.Context) (string, time.Duration, error) {
+		body, _ := json.Marshal(map[string]string{
+			"app_id":     appID,
+			"app_secret": appSecret,
+		})
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBase+"/auth/v3/tenant_access_token/internal", bytes.NewReader(body))
+		if err != nil {
+			return "", 0, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		var data struct {
+			Code              int    `json:"code"`
+			Msg               string `json:"msg"`
+			TenantAccessToken string `json:"tenant_access_token"`
+			Expire            int    `json:"expire"`
+		}
+		if _, err := s.do(client, httpReq, &data); err != nil {
+			return "", 0, err
+		}
+
+		if data.Code != 0 {
+			return "", 0, fmt.Errorf("failed to get tenant access token: %s", data.Msg)
+		}
+
+		return data.TenantAccessToken, time.Duration(data.Expire) * time.Second, nil
+	})
+}
+
+// ==================== CallbackHandler 接口实现 ====================
+
+// SupportsCallback 是否支持回调
+func (s *FeishuSender) SupportsCallback() bool {
+	return true
+}
+
+// HandleCallback 处理飞书事件回调
+// 支持订阅地址校验（url_verification）和消息已读事件（im.message.message_read_v1）
+func (s *FeishuSender) HandleCallback(ctx context.Context, req *CallbackRequest) (CallbackResponse, []*CallbackResult, error) {
+	resp := CallbackResponse{
+		StatusCode: 200,
+		Body:       `{"code":0}`,
+	}
+
+	var config map[string]interface{}
+	if req.ProviderAccount != nil {
+		config, _ = req.ProviderAccount.GetConfig()
+	}
+
+	rawBody := req.RawBody
+
+	// 配置了 Encrypt Key 时事件内容为加密数据
+	var encrypted struct {
+		Encrypt string `json:"encrypt"`
+	}
+	if err := json.Unmarshal(rawBody, &encrypted); err == nil && encrypted.Encrypt != "" {
+		encryptKey := configString(config, "encrypt_key")
+		if encryptKey == "" {
+			return resp, nil, fmt.Errorf("received encrypted event but encrypt_key is not configured")
+		}
+		decrypted, err := s.decryptEvent(encrypted.Encrypt, encryptKey)
+		if err != nil {
+			return resp, nil, fmt.Errorf("failed to decrypt event: %w", err)
+		}
+		rawBody = decrypted
+	}
+
+	var event struct {
+		// 订阅地址校验
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Token     string `json:"token"`
+		// 2.0 版本事件
+		Header struct {
+			EventType string `json:"event_type"`
+			Token     string `json:"token"`
+		} `json:"header"`
+		Event struct {
+			Reader struct {
+				ReadTime string `json:"read_time"`
+			} `json:"reader"`
+			MessageIDList []string `json:"message_id_list"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(rawBody, &event); err != nil {
+		return resp, nil, fmt.Errorf("invalid callback data: %w", err)
+	}
+
+	// 校验 Verification Token
+	if token := configString(config, "verification_token"); token != "" {
+		eventToken := event.Token
+		if eventToken == "" {
+			eventToken = event.Header.Token
+		}
+		if !hmac.Equal([]byte(eventToken), []byte(token)) {
+			resp.StatusCode = 403
+			resp.Body = `{"code":403,"msg":"invalid token"}`
+			return resp, nil, fmt.Errorf("invalid feishu verification token")
+		}
+	}
+
+	if event.Type == feishuEventURLVerify {
+		challenge, _ := json.Marshal(map[string]string{"challenge": event.Challenge})
+		resp.Body = string(challenge)
+		return resp, nil, nil
+	}
+
+	if event.Header.EventType != feishuEventRead {
+		// 其他事件不处理
+		return resp, nil, nil
+	}
+
+	reportTime := time.Now()
+	if ms, err := strconv.ParseInt(event.Event.Reader.ReadTime, 10, 64); err == nil && ms > 0 {
+		reportTime = time.UnixMilli(ms)
+	}
+
+	results := make([]*CallbackResult, 0, len(event.Event.MessageIDList))
+	for _, messageID := range event.Event.MessageIDList {
+		results = append(results, &CallbackResult{
+			ProviderID: messageID,
+			Status:     constants.CallbackStatusDelivered,
+			ReportTime: reportTime,
+		})
+	}
+
+	return resp, results, nil
+}
+
+// decryptEvent 解密事件内容：AES-256-CBC，密钥为 SHA256(encrypt_key)，密文前 16 字节为 IV
+func (s *FeishuSender) decryptEvent(encrypt, encryptKey string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypt)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aes.BlockSize*2 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid ciphertext length")
+	}
+
+	key := sha256.Sum256([]byte(encryptKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	iv, data := ciphertext[:aes.BlockSize], ciphertext[aes.BlockSize:]
+	plaintext := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, data)
+
+	// 去除 PKCS7 填充
+	padding := int(plaintext[len(plaintext)-1])
+	if padding < 1 || padding > aes.BlockSize {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
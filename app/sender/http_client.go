@@ -0,0 +1,312 @@
+package sender
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/registry"
+)
+
+// 出站 HTTP 客户端配置项（所有基于 HTTP 的服务商账号通用）
+const (
+	ConfigKeyHTTPProxy          = "http_proxy"
+	ConfigKeyConnectTimeout     = "connect_timeout"
+	ConfigKeyReadTimeout        = "read_timeout"
+	ConfigKeyMaxIdleConns       = "max_idle_conns"
+	ConfigKeyCACert             = "ca_cert"
+	ConfigKeyInsecureSkipVerify = "insecure_skip_verify"
+)
+
+// 出站 HTTP 客户端默认值
+const (
+	DefaultConnectTimeout = 5   // 默认连接超时（秒）
+	DefaultMaxIdleConns   = 100 // 默认最大空闲连接数
+)
+
+// ErrPrivateNetworkAddress 请求地址解析为回环、链路本地或内网地址（开启 DenyPrivateNetwork 时）
+var ErrPrivateNetworkAddress = errors.New("private network address is not allowed")
+
+// HTTPClientOptions 出站 HTTP 客户端参数
+type HTTPClientOptions struct {
+	ProxyURL           string        // 代理地址，支持 http、https、socks5
+	ConnectTimeout     time.Duration // 连接（含 TLS 握手）超时
+	ReadTimeout        time.Duration // 等待响应超时
+	MaxIdleConns       int           // 最大空闲连接数
+	CACert             string        // 额外信任的 CA 证书（PEM）
+	InsecureSkipVerify bool          // 跳过证书校验，仅用于测试
+	DenyPrivateNetwork bool          // 拒绝访问回环、链路本地和内网地址（请求地址由调用方指定时使用）
+}
+
+// httpClientConfigFields 出站 HTTP 客户端配置项声明
+func httpClientConfigFields() []registry.ConfigField {
+	return []registry.ConfigField{
+		{
+			Key:         ConfigKeyHTTPProxy,
+			Label:       "出站代理",
+			Description: "可选，访问服务商 API 使用的代理地址，支持 http、https、socks5",
+			Type:        registry.FieldTypeURL,
+			Required:    false,
+			Example:     "http://proxy.internal:3128",
+			Placeholder: "留空则直连",
+		},
+		{
+			Key:          ConfigKeyConnectTimeout,
+			Label:        "连接超时（秒）",
+			Description:  "建立连接和 TLS 握手的超时时间",
+			Type:         registry.FieldTypeNumber,
+			Required:     false,
+			Example:      "5",
+			DefaultValue: strconv.Itoa(DefaultConnectTimeout),
+		},
+		{
+			Key:          ConfigKeyReadTimeout,
+			Label:        "读取超时（秒）",
+			Description:  "发出请求后等待响应的超时时间",
+			Type:         registry.FieldTypeNumber,
+			Required:     false,
+			Example:      "10",
+			DefaultValue: strconv.Itoa(DefaultTimeout),
+		},
+		{
+			Key:          ConfigKeyMaxIdleConns,
+			Label:        "最大空闲连接数",
+			Description:  "连接池保留的最大空闲连接数",
+			Type:         registry.FieldTypeNumber,
+			Required:     false,
+			Example:      "100",
+			DefaultValue: strconv.Itoa(DefaultMaxIdleConns),
+		},
+		{
+			Key:         ConfigKeyCACert,
+			Label:       "CA证书",
+			Description: "可选，额外信任的 CA 证书（PEM 格式），用于代理或私有部署的自签名证书",
+			Type:        registry.FieldTypeTextarea,
+			Required:    false,
+			Placeholder: "-----BEGIN CERTIFICATE-----",
+		},
+		{
+			Key:          ConfigKeyInsecureSkipVerify,
+			Label:        "跳过证书校验",
+			Description:  "仅用于测试环境，true 时不校验服务端证书",
+			Type:         registry.FieldTypeText,
+			Required:     false,
+			Example:      "false",
+			DefaultValue: "false",
+		},
+	}
+}
+
+// ParseHTTPClientOptions 从服务商账号配置解析出站 HTTP 客户端参数
+func ParseHTTPClientOptions(config map[string]interface{}) (*HTTPClientOptions, error) {
+	opts := &HTTPClientOptions{
+		ProxyURL:       strings.TrimSpace(configString(config, ConfigKeyHTTPProxy)),
+		ConnectTimeout: time.Duration(DefaultConnectTimeout) * time.Second,
+		ReadTimeout:    time.Duration(DefaultTimeout) * time.Second,
+		MaxIdleConns:   DefaultMaxIdleConns,
+		CACert:         strings.TrimSpace(configString(config, ConfigKeyCACert)),
+	}
+
+	if opts.ProxyURL != "" {
+		if _, err := url.Parse(opts.ProxyURL); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ConfigKeyHTTPProxy, err)
+		}
+	}
+	if seconds, err := strconv.Atoi(configString(config, ConfigKeyConnectTimeout)); err == nil && seconds > 0 {
+		opts.ConnectTimeout = time.Duration(seconds) * time.Second
+	}
+	if seconds, err := strconv.Atoi(configString(config, ConfigKeyReadTimeout)); err == nil && seconds > 0 {
+		opts.ReadTimeout = time.Duration(seconds) * time.Second
+	}
+	if conns, err := strconv.Atoi(configString(config, ConfigKeyMaxIdleConns)); err == nil && conns > 0 {
+		opts.MaxIdleConns = conns
+	}
+	opts.InsecureSkipVerify, _ = strconv.ParseBool(configString(config, ConfigKeyInsecureSkipVerify))
+
+	return opts, nil
+}
+
+// Timeout 单次请求的总超时（连接 + 读取）
+func (o *HTTPClientOptions) Timeout() time.Duration {
+	return o.ConnectTimeout + o.ReadTimeout
+}
+
+// TimeoutFor 结合 ctx 截止时间的单次请求超时，供不支持 context 的 SDK 使用
+func (o *HTTPClientOptions) TimeoutFor(ctx context.Context) time.Duration {
+	timeout := o.Timeout()
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = max(remaining, time.Millisecond)
+		}
+	}
+	return timeout
+}
+
+// cacheKey 客户端缓存 key，参数相同的账号共享连接池
+func (o *HTTPClientOptions) cacheKey() string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%v|%v|%d|%s|%t|%t", o.ProxyURL, o.ConnectTimeout, o.ReadTimeout, o.MaxIdleConns, o.CACert, o.InsecureSkipVerify, o.DenyPrivateNetwork)))
+	return hex.EncodeToString(sum[:])
+}
+
+// HTTPClientFactory 出站 HTTP 客户端工厂
+// 按客户端参数缓存 http.Client，参数相同的服务商账号复用同一连接池
+type HTTPClientFactory struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}
+
+var (
+	httpClientFactory     *HTTPClientFactory
+	httpClientFactoryOnce sync.Once
+)
+
+// GetHTTPClientFactory 获取全局出站 HTTP 客户端工厂
+func GetHTTPClientFactory() *HTTPClientFactory {
+	httpClientFactoryOnce.Do(func() {
+		httpClientFactory = &HTTPClientFactory{
+			clients: make(map[string]*http.Client),
+		}
+	})
+	return httpClientFactory
+}
+
+// Client 获取指定参数的 HTTP 客户端
+// 请求需通过 http.NewRequestWithContext 创建，ctx 截止时间早于客户端超时时以 ctx 为准
+func (f *HTTPClientFactory) Client(opts *HTTPClientOptions) (*http.Client, error) {
+	key := opts.cacheKey()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if client, ok := f.clients[key]; ok {
+		return client, nil
+	}
+
+	transport, err := newHTTPTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   opts.Timeout(),
+	}
+	f.clients[key] = client
+	return client, nil
+}
+
+// newHTTPTransport 按参数创建 Transport
+func newHTTPTransport(opts *HTTPClientOptions) (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+	if opts.CACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(opts.CACert)) {
+			return nil, fmt.Errorf("invalid %s: no certificate found", ConfigKeyCACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ConfigKeyHTTPProxy, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if opts.DenyPrivateNetwork {
+		if opts.ProxyURL == "" {
+			// 直连时在建立连接前检查实际连接的地址，重定向和 DNS 重绑定同样受限；不使用环境变量代理
+			proxy = nil
+			dialer.Control = denyPrivateNetworkControl
+		} else {
+			// 经代理访问时由代理建立连接，只能在发出请求前检查目标地址的解析结果
+			proxy = denyPrivateNetworkProxy(proxy)
+		}
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   opts.ConnectTimeout,
+		ResponseHeaderTimeout: opts.ReadTimeout,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConns,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}, nil
+}
+
+// isPrivateNetworkIP 是否为回环、链路本地、内网、运营商级 NAT、未指定或组播地址
+func isPrivateNetworkIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	// 100.64.0.0/10 运营商级 NAT 地址
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return true
+	}
+	return false
+}
+
+// denyPrivateNetworkControl 建立连接前检查目标地址
+func denyPrivateNetworkControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isPrivateNetworkIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateNetworkAddress, host)
+	}
+	return nil
+}
+
+// denyPrivateNetworkProxy 经代理发出请求前检查目标主机的解析结果
+func denyPrivateNetworkProxy(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		host := req.URL.Hostname()
+		addrs, err := net.DefaultResolver.LookupIPAddr(req.Context(), host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if isPrivateNetworkIP(addr.IP) {
+				return nil, fmt.Errorf("%w: %s", ErrPrivateNetworkAddress, host)
+			}
+		}
+		return proxy(req)
+	}
+}
+
+// httpClientFor 获取服务商账号配置对应的 HTTP 客户端
+func httpClientFor(config map[string]interface{}) (*http.Client, error) {
+	opts, err := ParseHTTPClientOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	return GetHTTPClientFactory().Client(opts)
+}
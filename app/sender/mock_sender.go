@@ -0,0 +1,402 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/app/registry"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func init() {
+	// 注册模拟服务商（支持所有消息类型）
+	registry.Register(&registry.ProviderMeta{
+		Code:         constants.ProviderMock,
+		Name:         "模拟服务商",
+		Type:         constants.MessageTypeSMS,
+		MessageTypes: constants.MessageTypes,
+		Description:  "不实际发送消息，按配置的成功率、延迟和错误码模拟发送结果并回调送达状态，用于压测、联调和沙箱应用",
+		ConfigFields: []registry.ConfigField{
+			{
+				Key:          "success_rate",
+				Label:        "成功率",
+				Description:  "发送成功的概率，取值 0~1",
+				Type:         registry.FieldTypeNumber,
+				Required:     false,
+				Example:      "0.95",
+				DefaultValue: "1",
+			},
+			{
+				Key:          "latency_min_ms",
+				Label:        "最小延迟（毫秒）",
+				Description:  "模拟调用耗时的下限，实际延迟在最小值和最大值之间均匀分布",
+				Type:         registry.FieldTypeNumber,
+				Required:     false,
+				Example:      "50",
+				DefaultValue: "0",
+			},
+			{
+				Key:          "latency_max_ms",
+				Label:        "最大延迟（毫秒）",
+				Description:  "模拟调用耗时的上限",
+				Type:         registry.FieldTypeNumber,
+				Required:     false,
+				Example:      "300",
+				DefaultValue: "0",
+			},
+			{
+				Key:          "error_codes",
+				Label:        "错误码",
+				Description:  "发送失败时随机返回的错误码，逗号分隔，可填写真实服务商错误码以验证失败规则",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "MOCK_FAILED,isv.BUSINESS_LIMIT_CONTROL",
+				DefaultValue: ErrorCodeMockFailed,
+			},
+			{
+				Key:          "callback_enabled",
+				Label:        "模拟回调",
+				Description:  "true 时发送成功后任务保持已发送状态，延迟后通过回调服务回写送达结果；false 时发送成功即完成",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "true",
+				DefaultValue: "true",
+			},
+			{
+				Key:          "callback_delay_ms",
+				Label:        "回调延迟（毫秒）",
+				Description:  "发送成功到模拟回调的时间间隔",
+				Type:         registry.FieldTypeNumber,
+				Required:     false,
+				Example:      "2000",
+				DefaultValue: "2000",
+			},
+			{
+				Key:          "callback_failure_rate",
+				Label:        "回调失败率",
+				Description:  "模拟回调中报告未送达的概率，取值 0~1",
+				Type:         registry.FieldTypeNumber,
+				Required:     false,
+				Example:      "0.05",
+				DefaultValue: "0",
+			},
+		},
+		// 能力声明
+		SupportsSend:      true,
+		SupportsBatchSend: true,
+		SupportsCallback:  true,
+		// 扩展信息
+		Website:    "",
+		Icon:       "",
+		DocsUrl:    "",
+		ConsoleUrl: "",
+		PricingUrl: "",
+		SortOrder:  999,
+		Tags:       []string{"测试", "沙箱"},
+		Regions:    []string{},
+		Deprecated: false,
+	})
+}
+
+// 模拟服务商错误码
+const (
+	ErrorCodeMockFailed      = "MOCK_FAILED"      // 模拟发送失败
+	ErrorCodeMockUndelivered = "MOCK_UNDELIVERED" // 模拟回调未送达
+)
+
+// mockCallbackQueueKey 待投递的模拟回调（有序集合，score 为投递时间毫秒）
+const mockCallbackQueueKey = "mock:callbacks"
+
+// popMockCallbacksScript 原子地取出到期的模拟回调，多实例下每条回调只会被取出一次
+var popMockCallbacksScript = redis.NewScript(`
+	local items = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+	if #items > 0 then
+		redis.call('ZREM', KEYS[1], unpack(items))
+	end
+	return items
+`)
+
+// MockCallbackPayload 模拟回调数据
+// 也可通过 POST /api/callback/{模拟服务商账号ID} 直接推送该格式（单条或数组）模拟回调
+type MockCallbackPayload struct {
+	ProviderID   string `json:"provider_id"`
+	Status       string `json:"status"` // delivered 或 failed
+	ErrorCode    string `json:"error_code,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
+// mockConfig 模拟服务商配置
+type mockConfig struct {
+	SuccessRate         float64
+	LatencyMin          time.Duration
+	LatencyMax          time.Duration
+	ErrorCodes          []string
+	CallbackEnabled     bool
+	CallbackDelay       time.Duration
+	CallbackFailureRate float64
+}
+
+// MockSender 模拟服务商发送器
+type MockSender struct{}
+
+// NewMockSender 创建模拟服务商发送器
+func NewMockSender() *MockSender {
+	return &MockSender{}
+}
+
+// GetProviderCode 获取服务商代码
+func (s *MockSender) GetProviderCode() string {
+	return constants.ProviderMock
+}
+
+// Send 模拟发送单条消息
+func (s *MockSender) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	config, err := s.parseConfig(req.ProviderAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.wait(ctx, config); err != nil {
+		return nil, err
+	}
+
+	return s.simulate(ctx, config, req.Task.TaskID, req.Task.Receiver), nil
+}
+
+// SupportsBatchSend 是否支持批量发送
+func (s *MockSender) SupportsBatchSend() bool {
+	return true
+}
+
+// BatchSend 模拟批量发送，整批只计一次延迟
+func (s *MockSender) BatchSend(ctx context.Context, req *BatchSendRequest) (*BatchSendResponse, error) {
+	config, err := s.parseConfig(req.ProviderAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.wait(ctx, config); err != nil {
+		return nil, err
+	}
+
+	results := make([]*SendResponse, len(req.Tasks))
+	for i, task := range req.Tasks {
+		results[i] = s.simulate(ctx, config, task.TaskID, task.Receiver)
+	}
+	return &BatchSendResponse{Results: results}, nil
+}
+
+// SupportsCallback 是否支持回调
+func (s *MockSender) SupportsCallback() bool {
+	return true
+}
+
+// HandleCallback 解析模拟回调
+func (s *MockSender) HandleCallback(ctx context.Context, req *CallbackRequest) (CallbackResponse, []*CallbackResult, error) {
+	resp := CallbackResponse{
+		StatusCode: 200,
+		Body:       `{"code":0,"message":"ok"}`,
+	}
+
+	var payloads []MockCallbackPayload
+	body := strings.TrimSpace(string(req.RawBody))
+	if strings.HasPrefix(body, "[") {
+		if err := json.Unmarshal(req.RawBody, &payloads); err != nil {
+			return resp, nil, fmt.Errorf("invalid callback data: %w", err)
+		}
+	} else {
+		var payload MockCallbackPayload
+		if err := json.Unmarshal(req.RawBody, &payload); err != nil {
+			return resp, nil, fmt.Errorf("invalid callback data: %w", err)
+		}
+		payloads = append(payloads, payload)
+	}
+
+	results := make([]*CallbackResult, 0, len(payloads))
+	for _, payload := range payloads {
+		if payload.ProviderID == "" {
+			continue
+		}
+
+		status := constants.CallbackStatusDelivered
+		if payload.Status != constants.CallbackStatusDelivered {
+			status = constants.CallbackStatusFailed
+		}
+
+		reportTime := time.Now()
+		if payload.Timestamp > 0 {
+			reportTime = time.UnixMilli(payload.Timestamp)
+		}
+
+		results = append(results, &CallbackResult{
+			ProviderID:   payload.ProviderID,
+			Status:       status,
+			ErrorCode:    payload.ErrorCode,
+			ErrorMessage: payload.ErrorMessage,
+			ReportTime:   reportTime,
+		})
+	}
+
+	return resp, results, nil
+}
+
+// simulate 按配置生成一条发送结果，成功且开启回调时登记模拟回调
+func (s *MockSender) simulate(ctx context.Context, config *mockConfig, taskID, receiver string) *SendResponse {
+	requestData, _ := json.Marshal(map[string]string{
+		"task_id":  taskID,
+		"receiver": receiver,
+	})
+
+	if rand.Float64() >= config.SuccessRate {
+		errorCode := config.ErrorCodes[rand.IntN(len(config.ErrorCodes))]
+		responseData, _ := json.Marshal(map[string]string{"error_code": errorCode})
+		return &SendResponse{
+			Success:      false,
+			ErrorCode:    errorCode,
+			ErrorMessage: "simulated send failure",
+			TaskID:       taskID,
+			RequestData:  string(requestData),
+			ResponseData: string(responseData),
+		}
+	}
+
+	providerID := "mock_" + uuid.NewString()
+	responseData, _ := json.Marshal(map[string]string{"provider_id": providerID})
+	resp := &SendResponse{
+		Success:      true,
+		ProviderID:   providerID,
+		TaskID:       taskID,
+		Status:       constants.TaskStatusSuccess,
+		RequestData:  string(requestData),
+		ResponseData: string(responseData),
+	}
+
+	if config.CallbackEnabled {
+		payload := &MockCallbackPayload{
+			ProviderID: providerID,
+			Status:     constants.CallbackStatusDelivered,
+		}
+		if rand.Float64() < config.CallbackFailureRate {
+			payload.Status = constants.CallbackStatusFailed
+			payload.ErrorCode = ErrorCodeMockUndelivered
+			payload.ErrorMessage = "simulated delivery failure"
+		}
+
+		// 登记失败时按直接成功处理，避免任务一直等待回调
+		if err := scheduleMockCallback(ctx, payload, config.CallbackDelay); err != nil {
+			helper.GetHelper().GetLogger().Warn(fmt.Sprintf("failed to schedule mock callback task_id=%s: %v", taskID, err))
+		} else {
+			resp.Status = constants.TaskStatusSent // 已发送，等待模拟回调
+		}
+	}
+
+	return resp
+}
+
+// wait 模拟调用延迟
+func (s *MockSender) wait(ctx context.Context, config *mockConfig) error {
+	latency := config.LatencyMin
+	if config.LatencyMax > config.LatencyMin {
+		latency += rand.N(config.LatencyMax - config.LatencyMin)
+	}
+	if latency <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseConfig 解析模拟服务商配置
+func (s *MockSender) parseConfig(account *model.ProviderAccount) (*mockConfig, error) {
+	raw, err := account.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider config: %w", err)
+	}
+
+	config := &mockConfig{
+		SuccessRate:     1,
+		ErrorCodes:      []string{ErrorCodeMockFailed},
+		CallbackEnabled: true,
+		CallbackDelay:   2 * time.Second,
+	}
+
+	if rate, err := strconv.ParseFloat(configString(raw, "success_rate"), 64); err == nil {
+		config.SuccessRate = min(max(rate, 0), 1)
+	}
+	if ms, err := strconv.Atoi(configString(raw, "latency_min_ms")); err == nil && ms > 0 {
+		config.LatencyMin = time.Duration(ms) * time.Millisecond
+	}
+	if ms, err := strconv.Atoi(configString(raw, "latency_max_ms")); err == nil && ms > 0 {
+		config.LatencyMax = time.Duration(ms) * time.Millisecond
+	}
+	if codes := configString(raw, "error_codes"); codes != "" {
+		config.ErrorCodes = config.ErrorCodes[:0]
+		for _, code := range strings.Split(codes, ",") {
+			if code = strings.TrimSpace(code); code != "" {
+				config.ErrorCodes = append(config.ErrorCodes, code)
+			}
+		}
+		if len(config.ErrorCodes) == 0 {
+			config.ErrorCodes = []string{ErrorCodeMockFailed}
+		}
+	}
+	if enabled, err := strconv.ParseBool(configString(raw, "callback_enabled")); err == nil {
+		config.CallbackEnabled = enabled
+	}
+	if ms, err := strconv.Atoi(configString(raw, "callback_delay_ms")); err == nil && ms >= 0 {
+		config.CallbackDelay = time.Duration(ms) * time.Millisecond
+	}
+	if rate, err := strconv.ParseFloat(configString(raw, "callback_failure_rate"), 64); err == nil {
+		config.CallbackFailureRate = min(max(rate, 0), 1)
+	}
+
+	return config, nil
+}
+
+// scheduleMockCallback 登记模拟回调，到期后由调度器交给回调服务处理
+func scheduleMockCallback(ctx context.Context, payload *MockCallbackPayload, delay time.Duration) error {
+	dueAt := time.Now().Add(delay)
+	payload.Timestamp = dueAt.UnixMilli()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return helper.GetHelper().GetRedis().ZAdd(ctx, mockCallbackQueueKey, redis.Z{
+		Score:  float64(dueAt.UnixMilli()),
+		Member: string(data),
+	}).Err()
+}
+
+// PopDueMockCallbacks 取出已到期的模拟回调（每条回调数据可直接作为 CallbackRequest.RawBody）
+func PopDueMockCallbacks(ctx context.Context, limit int) ([][]byte, error) {
+	items, err := popMockCallbacksScript.Run(ctx, helper.GetHelper().GetRedis(), []string{mockCallbackQueueKey}, time.Now().UnixMilli(), limit).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([][]byte, len(items))
+	for i, item := range items {
+		payloads[i] = []byte(item)
+	}
+	return payloads, nil
+}
@@ -0,0 +1,118 @@
+package sender
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/redis/go-redis/v9"
+)
+
+// 群机器人限流：每个机器人每分钟最多发送 20 条消息
+const (
+	RobotRateLimit       = 20
+	RobotRateLimitWindow = time.Minute
+)
+
+// ErrorCodeRobotRateLimited 超出机器人限流时的错误码，任务延迟到下一个窗口重新发送
+const ErrorCodeRobotRateLimited = "ROBOT_RATE_LIMITED"
+
+// robotRateLimitScript 固定窗口计数，未超限时计数加一并返回 0，超限时返回当前窗口剩余毫秒数
+var robotRateLimitScript = redis.NewScript(`
+	local current = redis.call('INCR', KEYS[1])
+	if current == 1 then
+		redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	end
+	if current > tonumber(ARGV[1]) then
+		local ttl = redis.call('PTTL', KEYS[1])
+		if ttl <= 0 then
+			ttl = tonumber(ARGV[2])
+		end
+		return ttl
+	end
+	return 0
+`)
+
+// allowRobotSend 检查机器人是否还有发送额度（按 Webhook 地址区分机器人）
+// 超出额度时返回距下一个窗口的等待时长；Redis 异常时放行，由服务商限流兜底
+func allowRobotSend(ctx context.Context, providerCode, webhookURL string) (bool, time.Duration) {
+	sum := sha1.Sum([]byte(webhookURL))
+	key := fmt.Sprintf("robot:rate_limit:%s:%s", providerCode, hex.EncodeToString(sum[:]))
+
+	h := helper.GetHelper()
+	wait, err := robotRateLimitScript.Run(ctx, h.GetRedis(), []string{key}, RobotRateLimit, RobotRateLimitWindow.Milliseconds()).Int64()
+	if err != nil {
+		h.GetLogger().Warn(fmt.Sprintf("failed to check robot rate limit provider=%s: %v", providerCode, err))
+		return true, 0
+	}
+	return wait == 0, time.Duration(wait) * time.Millisecond
+}
+
+// robotRateLimitedResponse 超出机器人限流时的发送结果
+// 请求未发出，由 worker 延迟到下一个窗口重新发送，不计入熔断和绑定健康统计
+func robotRateLimitedResponse(taskID string, retryAfter time.Duration) *SendResponse {
+	return &SendResponse{
+		Success:      false,
+		ErrorCode:    ErrorCodeRobotRateLimited,
+		ErrorMessage: fmt.Sprintf("robot rate limit exceeded: %d messages per %v", RobotRateLimit, RobotRateLimitWindow),
+		TaskID:       taskID,
+		RetryAfter:   retryAfter,
+	}
+}
+
+// robotMentions 群机器人 @ 对象
+type robotMentions struct {
+	All     bool
+	Mobiles []string
+	UserIDs []string
+}
+
+// parseRobotMentions 解析接收者中的 @ 对象
+// 接收者为逗号分隔的手机号或用户ID，@all 表示 @所有人
+func parseRobotMentions(receiver string) *robotMentions {
+	mentions := &robotMentions{}
+	for _, item := range strings.Split(receiver, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "":
+		case strings.EqualFold(item, "@all") || strings.EqualFold(item, "all"):
+			mentions.All = true
+		case isMobile(item):
+			mentions.Mobiles = append(mentions.Mobiles, item)
+		default:
+			mentions.UserIDs = append(mentions.UserIDs, item)
+		}
+	}
+	return mentions
+}
+
+// isMobile 是否为手机号（可带 + 前缀的纯数字）
+func isMobile(value string) bool {
+	digits := strings.TrimPrefix(value, "+")
+	if len(digits) < 6 || len(digits) > 15 {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// robotMessageParams 获取机器人链接、卡片消息使用的模板参数（url、pic_url、button_title 等）
+func robotMessageParams(req *SendRequest) map[string]string {
+	params := req.MappedParams
+	if params == nil && req.Task.TemplateParams != "" {
+		_ = json.Unmarshal([]byte(req.Task.TemplateParams), &params)
+	}
+	if params == nil {
+		params = map[string]string{}
+	}
+	return params
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/model"
 )
 
@@ -12,8 +13,21 @@ import (
 const (
 	MaxBatchSizeTencentSMS = 200  // 腾讯云短信批量上限
 	MaxBatchSizeAliyunSMS  = 1000 // 阿里云短信批量上限
+	MaxBatchSizeDefault    = 100  // 其他服务商默认批量上限
 )
 
+// MaxBatchSize 获取服务商单次批量发送的任务数上限
+func MaxBatchSize(providerCode string) int {
+	switch providerCode {
+	case constants.ProviderTencentSMS:
+		return MaxBatchSizeTencentSMS
+	case constants.ProviderAliyunSMS:
+		return MaxBatchSizeAliyunSMS
+	default:
+		return MaxBatchSizeDefault
+	}
+}
+
 // ==================== 默认通道配置 ====================
 
 const (
@@ -39,10 +53,11 @@ type SendResponse struct {
 	ProviderID   string // 服务商返回的消息ID
 	ErrorCode    string
 	ErrorMessage string
-	TaskID       string // 批量发送时用于关联；单发时可忽略
-	Status       string // 任务状态：processing(等待回调) 或 success(直接成功)
-	RequestData  string // 发送给供应商的请求参数（JSON格式），用于调试
-	ResponseData string // 供应商返回的响应数据（JSON格式），用于调试
+	TaskID       string        // 批量发送时用于关联；单发时可忽略
+	Status       string        // 任务状态：processing(等待回调) 或 success(直接成功)
+	RequestData  string        // 发送给供应商的请求参数（JSON格式），用于调试
+	ResponseData string        // 供应商返回的响应数据（JSON格式），用于调试
+	RetryAfter   time.Duration // 本地限流时大于 0：请求未发出，应在该时长后重新发送（不计入失败统计）
 }
 
 // Sender 发送器接口
@@ -85,11 +100,12 @@ type BatchSender interface {
 
 // CallbackRequest 回调请求
 type CallbackRequest struct {
-	ProviderCode string            // 服务商代码
-	RawBody      []byte            // 原始请求体
-	Headers      map[string]string // 请求头（用于签名验证等）
-	QueryParams  map[string]string // URL 查询参数
-	FormData     map[string]string // 表单数据（用于 form-data 请求）
+	ProviderCode    string                 // 服务商代码
+	ProviderAccount *model.ProviderAccount // 接收回调的服务商账号（用于校验令牌、解密等）
+	RawBody         []byte                 // 原始请求体
+	Headers         map[string]string      // 请求头（用于签名验证等）
+	QueryParams     map[string]string      // URL 查询参数
+	FormData        map[string]string      // 表单数据（用于 form-data 请求）
 }
 
 // CallbackResult 回调结果
@@ -119,6 +135,39 @@ type CallbackHandler interface {
 	SupportsCallback() bool
 }
 
+// ==================== 上行消息相关 ====================
+
+// InboundMessage 上行消息（用户回复的短信）
+type InboundMessage struct {
+	Phone       string    // 发送者手机号（国内号码不含国家码）
+	Content     string    // 回复内容
+	SignName    string    // 用户回复的短信签名
+	ExtendCode  string    // 上行扩展码（发送时通过扩展码区分业务）
+	DestCode    string    // 上行目的号码（通道号+扩展码）
+	SequenceID  string    // 服务商上行消息序列号
+	ReceiveTime time.Time // 接收时间
+}
+
+// InboundHandler 上行消息处理器接口（可选实现）
+// 服务商将用户回复推送到 /api/callback/:id/inbound，与状态报告回调地址分开配置
+type InboundHandler interface {
+	// HandleInbound 解析上行消息推送
+	// 返回值：响应信息（实体，始终返回）、上行消息列表、错误
+	HandleInbound(ctx context.Context, req *CallbackRequest) (CallbackResponse, []*InboundMessage, error)
+	// GetProviderCode 获取服务商代码
+	GetProviderCode() string
+	// SupportsInbound 是否支持上行消息
+	SupportsInbound() bool
+}
+
+// bindingExtendCode 获取通道绑定配置的上行扩展码，未绑定（如沙箱）时为空
+func bindingExtendCode(binding *model.ChannelTemplateBinding) string {
+	if binding == nil {
+		return ""
+	}
+	return binding.ExtendCode
+}
+
 // ==================== 状态查询相关 ====================
 
 // StatusQueryRequest 单条状态查询请求（阿里云、腾讯云）
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"cnb.cool/mliev/push/message-push/app/constants"
@@ -21,7 +22,7 @@ func init() {
 		Name:        "腾讯云短信",
 		Type:        constants.MessageTypeSMS,
 		Description: "腾讯云短信服务，支持国内短信和国际短信发送。注意：短信签名需在「签名管理」中单独配置",
-		ConfigFields: []registry.ConfigField{
+		ConfigFields: append([]registry.ConfigField{
 			{
 				Key:            "secret_id",
 				Label:          "SecretId",
@@ -63,11 +64,13 @@ func init() {
 				Placeholder:  "请输入地域",
 				DefaultValue: "ap-guangzhou",
 			},
-		},
+			endpointConfigField(TencentSMSDefaultEndpoint),
+		}, httpClientConfigFields()...),
 		// 能力声明
 		SupportsSend:        true,
 		SupportsBatchSend:   true,
 		SupportsCallback:    true,
+		SupportsInbound:     true,
 		SupportsStatusQuery: true,
 		// 扩展信息
 		Website:    "https://cloud.tencent.com/product/sms",
@@ -113,10 +116,10 @@ func (s *TencentSMSSender) Send(ctx context.Context, req *SendRequest) (*SendRes
 	}
 
 	// 2. 初始化客户端
-	credential := common.NewCredential(secretId, secretKey)
-	cpf := profile.NewClientProfile()
-	cpf.HttpProfile.Endpoint = "sms.tencentcloudapi.com"
-	client, _ := sms.NewClient(credential, region, cpf)
+	client, err := s.createClient(config, secretId, secretKey, region)
+	if err != nil {
+		return nil, err
+	}
 
 	// 3. 构造请求
 	request := sms.NewSendSmsRequest()
@@ -148,6 +151,11 @@ func (s *TencentSMSSender) Send(ctx context.Context, req *SendRequest) (*SendRes
 	request.SignName = common.StringPtr(signName)
 	request.TemplateId = common.StringPtr(templateID)
 
+	// 上行扩展码（用户回复时原样带回，用于关联原始任务）
+	if extendCode := bindingExtendCode(req.ChannelTemplateBinding); extendCode != "" {
+		request.ExtendCode = common.StringPtr(extendCode)
+	}
+
 	// 接收者
 	request.PhoneNumberSet = common.StringPtrs([]string{req.Task.Receiver})
 
@@ -165,7 +173,7 @@ func (s *TencentSMSSender) Send(ctx context.Context, req *SendRequest) (*SendRes
 	})
 
 	// 5. 发送
-	response, err := client.SendSms(request)
+	response, err := client.SendSmsWithContext(ctx, request)
 	if err != nil {
 		return &SendResponse{
 			Success:      false,
@@ -314,6 +322,32 @@ func (s *TencentSMSSender) buildParamsFromBatchMapping(req *BatchSendRequest) []
 	return values
 }
 
+// createClient 创建腾讯云短信客户端，使用账号配置的 API 地址和出站 HTTP 客户端
+func (s *TencentSMSSender) createClient(config map[string]interface{}, secretId, secretKey, region string) (*sms.Client, error) {
+	opts, err := ParseHTTPClientOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := GetHTTPClientFactory().Client(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := common.NewCredential(secretId, secretKey)
+	cpf := profile.NewClientProfile()
+	scheme, host := splitEndpoint(configEndpoint(config, TencentSMSDefaultEndpoint))
+	cpf.HttpProfile.Scheme = strings.ToUpper(scheme)
+	cpf.HttpProfile.Endpoint = host
+	cpf.HttpProfile.ReqTimeout = int(opts.Timeout().Seconds())
+
+	client, err := sms.NewClient(credential, region, cpf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tencent sms client: %w", err)
+	}
+	client.WithHttpTransport(httpClient.Transport)
+	return client, nil
+}
+
 // ==================== BatchSender 接口实现 ====================
 
 // SupportsBatchSend 是否支持批量发送
@@ -346,10 +380,10 @@ func (s *TencentSMSSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 	}
 
 	// 2. 初始化客户端
-	credential := common.NewCredential(secretId, secretKey)
-	cpf := profile.NewClientProfile()
-	cpf.HttpProfile.Endpoint = "sms.tencentcloudapi.com"
-	client, _ := sms.NewClient(credential, region, cpf)
+	client, err := s.createClient(config, secretId, secretKey, region)
+	if err != nil {
+		return nil, err
+	}
 
 	// 3. 构造请求
 	request := sms.NewSendSmsRequest()
@@ -381,6 +415,11 @@ func (s *TencentSMSSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 	request.SignName = common.StringPtr(signName)
 	request.TemplateId = common.StringPtr(templateID)
 
+	// 上行扩展码（用户回复时原样带回，用于关联原始任务）
+	if extendCode := bindingExtendCode(req.ChannelTemplateBinding); extendCode != "" {
+		request.ExtendCode = common.StringPtr(extendCode)
+	}
+
 	// 收集所有手机号
 	phoneNumbers := make([]string, len(req.Tasks))
 	taskIDMap := make(map[string]string) // phone -> taskID
@@ -404,7 +443,7 @@ func (s *TencentSMSSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 	})
 
 	// 5. 发送
-	response, err := client.SendSms(request)
+	response, err := client.SendSmsWithContext(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -473,10 +512,10 @@ func (s *TencentSMSSender) QueryStatus(ctx context.Context, req *StatusQueryRequ
 	}
 
 	// 2. 初始化客户端
-	credential := common.NewCredential(secretId, secretKey)
-	cpf := profile.NewClientProfile()
-	cpf.HttpProfile.Endpoint = "sms.tencentcloudapi.com"
-	client, _ := sms.NewClient(credential, region, cpf)
+	client, err := s.createClient(config, secretId, secretKey, region)
+	if err != nil {
+		return nil, err
+	}
 
 	// 3. 构造查询请求
 	// 腾讯云按手机号拉取状态，需要指定时间范围
@@ -493,7 +532,7 @@ func (s *TencentSMSSender) QueryStatus(ctx context.Context, req *StatusQueryRequ
 	request.Limit = common.Uint64Ptr(100)
 
 	// 4. 发送查询请求
-	response, err := client.PullSmsSendStatusByPhoneNumber(request)
+	response, err := client.PullSmsSendStatusByPhoneNumberWithContext(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query send status: %w", err)
 	}
@@ -603,3 +642,65 @@ func (s *TencentSMSSender) HandleCallback(ctx context.Context, req *CallbackRequ
 
 	return resp, results, nil
 }
+
+// ==================== InboundHandler 接口实现 ====================
+
+// tencentInboundReport 腾讯云短信回复推送
+type tencentInboundReport struct {
+	Extend     string `json:"extend"`
+	Mobile     string `json:"mobile"`
+	NationCode string `json:"nationcode"`
+	Sign       string `json:"sign"`
+	Text       string `json:"text"`
+	Time       int64  `json:"time"`
+}
+
+// SupportsInbound 是否支持上行消息
+func (s *TencentSMSSender) SupportsInbound() bool {
+	return true
+}
+
+// HandleInbound 处理腾讯云短信回复推送
+// 腾讯云短信回复格式（单条对象，兼容数组）：
+// {"extend":"01","mobile":"13xxxxxxxxx","nationcode":"86","sign":"腾讯云","text":"TD","time":1457336869}
+func (s *TencentSMSSender) HandleInbound(ctx context.Context, req *CallbackRequest) (CallbackResponse, []*InboundMessage, error) {
+	// 腾讯云期望返回 {"result": 0, "errmsg": "OK"}
+	resp := CallbackResponse{
+		StatusCode: 200,
+		Body:       `{"result":0,"errmsg":"OK"}`,
+	}
+
+	var reports []tencentInboundReport
+	if err := json.Unmarshal(req.RawBody, &reports); err != nil {
+		var report tencentInboundReport
+		if err := json.Unmarshal(req.RawBody, &report); err != nil {
+			// 即使解析失败也返回成功响应，避免服务商重复推送
+			return resp, nil, fmt.Errorf("invalid inbound data: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	messages := make([]*InboundMessage, 0, len(reports))
+	for _, report := range reports {
+		var receiveTime time.Time
+		if report.Time > 0 {
+			receiveTime = time.Unix(report.Time, 0)
+		}
+
+		// 国内号码不带国家码，其他地区使用 E.164 格式
+		phone := report.Mobile
+		if report.NationCode != "" && report.NationCode != "86" {
+			phone = "+" + report.NationCode + report.Mobile
+		}
+
+		messages = append(messages, &InboundMessage{
+			Phone:       phone,
+			Content:     report.Text,
+			SignName:    report.Sign,
+			ExtendCode:  report.Extend,
+			ReceiveTime: receiveTime,
+		})
+	}
+
+	return resp, messages, nil
+}
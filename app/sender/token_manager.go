@@ -0,0 +1,120 @@
+package sender
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+	"golang.org/x/sync/singleflight"
+)
+
+// accessTokenKeyPrefix 访问令牌缓存 key 前缀，完整 key 为 push:access_token:{provider}:{凭证摘要}
+const accessTokenKeyPrefix = "push:access_token:"
+
+// accessTokenExpiryMargin 令牌提前过期时长，避免使用即将失效的令牌
+const accessTokenExpiryMargin = 5 * time.Minute
+
+// 令牌无效或过期的错误码，收到后强制刷新令牌并重试一次
+var (
+	weChatWorkInvalidTokenCodes = []int{40001, 40014, 42001}
+	dingTalkInvalidTokenCodes   = []int{88, 40001, 40014, 42001}
+	feishuInvalidTokenCodes     = []int{99991661, 99991663, 99991668}
+)
+
+// AccessTokenFetcher 从服务商获取访问令牌，返回令牌及有效期
+type AccessTokenFetcher func(ctx context.Context) (string, time.Duration, error)
+
+// TokenManager 访问令牌管理器
+// 令牌缓存在 gsr 缓存中（redis 驱动时多实例共享），按服务商和账号凭证区分；
+// 同一进程内并发的刷新请求合并为一次调用
+type TokenManager struct {
+	cache gsr.Cacher
+	group singleflight.Group
+}
+
+var (
+	tokenManager     *TokenManager
+	tokenManagerOnce sync.Once
+)
+
+// GetTokenManager 获取全局访问令牌管理器
+func GetTokenManager() *TokenManager {
+	tokenManagerOnce.Do(func() {
+		tokenManager = &TokenManager{
+			cache: helper.GetHelper().GetCache(),
+		}
+	})
+	return tokenManager
+}
+
+// AccessTokenKey 生成访问令牌缓存 key，凭证只保存摘要
+func AccessTokenKey(providerCode string, credentials ...string) string {
+	h := sha1.New()
+	for _, credential := range credentials {
+		h.Write([]byte(credential))
+		h.Write([]byte{0})
+	}
+	return accessTokenKeyPrefix + providerCode + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Get 获取访问令牌，缓存未命中时调用 fetch 刷新并按返回的有效期缓存
+func (m *TokenManager) Get(ctx context.Context, key string, fetch AccessTokenFetcher) (string, error) {
+	var token string
+	if err := m.cache.Get(ctx, key, &token); err == nil && token != "" {
+		return token, nil
+	}
+
+	value, err, _ := m.group.Do(key, func() (interface{}, error) {
+		// 等待期间可能已被其他实例刷新
+		var cached string
+		if err := m.cache.Get(ctx, key, &cached); err == nil && cached != "" {
+			return cached, nil
+		}
+
+		token, expiresIn, err := fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		if token == "" {
+			return "", fmt.Errorf("empty access token")
+		}
+
+		ttl := expiresIn - accessTokenExpiryMargin
+		if ttl <= 0 {
+			ttl = expiresIn / 2
+		}
+		if ttl > 0 {
+			if err := m.cache.Set(ctx, key, token, ttl); err != nil {
+				helper.GetHelper().GetLogger().Warn(fmt.Sprintf("failed to cache access token key=%s: %v", key, err))
+			}
+		}
+		return token, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// Invalidate 使被服务商判定为无效的令牌失效
+// 仅当缓存中仍是该令牌时删除，避免误删其他协程刚刷新的令牌
+func (m *TokenManager) Invalidate(ctx context.Context, key, staleToken string) {
+	var cached string
+	if err := m.cache.Get(ctx, key, &cached); err != nil || cached != staleToken {
+		return
+	}
+	if err := m.cache.Del(ctx, key); err != nil {
+		helper.GetHelper().GetLogger().Warn(fmt.Sprintf("failed to invalidate access token key=%s: %v", key, err))
+	}
+}
+
+// isInvalidTokenCode 是否为令牌无效的错误码
+func isInvalidTokenCode(codes []int, code int) bool {
+	return code != 0 && slices.Contains(codes, code)
+}
@@ -0,0 +1,419 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/registry"
+)
+
+func init() {
+	// 注册通用 Webhook 服务商
+	registry.Register(&registry.ProviderMeta{
+		Code:        constants.ProviderWebhook,
+		Name:        "通用Webhook",
+		Type:        constants.MessageTypeWebhook,
+		Description: "将渲染后的消息通过 HTTP 请求推送到内部系统，支持自定义请求体、请求头和 HMAC 签名",
+		ConfigFields: append([]registry.ConfigField{
+			{
+				Key:         "url",
+				Label:       "请求地址",
+				Description: "消息推送地址，为空时使用接收者（receiver）作为请求地址（受允许域名和内网地址限制）",
+				Type:        registry.FieldTypeURL,
+				Required:    false,
+				Example:     "https://internal.example.com/notify",
+				Placeholder: "请输入请求地址",
+			},
+			{
+				Key:         "allowed_hosts",
+				Label:       "允许的域名",
+				Description: "使用接收者作为请求地址时允许访问的域名，逗号分隔，支持 *.example.com 通配子域名；为空时不限制域名",
+				Type:        registry.FieldTypeText,
+				Required:    false,
+				Example:     "hooks.example.com,*.internal.example.com",
+			},
+			{
+				Key:          "allow_private_network",
+				Label:        "允许内网地址",
+				Description:  "使用接收者作为请求地址时，true 表示允许访问回环、链路本地和内网地址；默认拒绝，避免调用方借此访问内部服务",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "false",
+				DefaultValue: "false",
+			},
+			{
+				Key:          "method",
+				Label:        "请求方法",
+				Description:  "HTTP 请求方法：POST 或 PUT",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "POST",
+				DefaultValue: http.MethodPost,
+			},
+			{
+				Key:          "content_type",
+				Label:        "Content-Type",
+				Description:  "请求体类型",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "application/json",
+				DefaultValue: "application/json",
+			},
+			{
+				Key:         "body_template",
+				Label:       "请求体模板",
+				Description: "使用 {变量名} 占位，可用变量：task_id、receiver、title、content 及模板参数；JSON 类型会自动转义变量值。为空时发送默认 JSON 结构",
+				Type:        registry.FieldTypeTextarea,
+				Required:    false,
+				Example:     `{"msg_type":"text","text":"{content}","to":"{receiver}"}`,
+			},
+			{
+				Key:         "headers",
+				Label:       "自定义请求头",
+				Description: "JSON 对象格式的请求头",
+				Type:        registry.FieldTypeTextarea,
+				Required:    false,
+				Example:     `{"Authorization":"Bearer xxx"}`,
+			},
+			{
+				Key:         "secret",
+				Label:       "签名密钥",
+				Description: "配置后使用 HMAC-SHA256(timestamp + \".\" + body, secret) 签名，通过 X-Webhook-Timestamp 和 X-Webhook-Signature 请求头传递",
+				Type:        registry.FieldTypePassword,
+				Required:    false,
+				Placeholder: "请输入签名密钥",
+			},
+			{
+				Key:          "timeout",
+				Label:        "超时时间（秒）",
+				Description:  "请求超时时间",
+				Type:         registry.FieldTypeNumber,
+				Required:     false,
+				Example:      "10",
+				DefaultValue: strconv.Itoa(DefaultTimeout),
+			},
+			{
+				Key:         "success_status_codes",
+				Label:       "成功状态码",
+				Description: "视为发送成功的 HTTP 状态码，逗号分隔；为空时 2xx 视为成功",
+				Type:        registry.FieldTypeText,
+				Required:    false,
+				Example:     "200,201,202",
+			},
+		}, httpClientConfigFields()...),
+		// 能力声明
+		SupportsSend:      true,
+		SupportsBatchSend: false,
+		SupportsCallback:  false,
+		// 扩展信息
+		SortOrder:  110,
+		Tags:       []string{"通用", "Webhook"},
+		Regions:    []string{"全球"},
+		Deprecated: false,
+	})
+}
+
+// webhookBodyVarPattern 请求体模板占位符 {variable}
+var webhookBodyVarPattern = regexp.MustCompile(`\{([a-zA-Z0-9_]+)\}`)
+
+// webhookSenderConfig 通用 Webhook 账号配置
+type webhookSenderConfig struct {
+	URL                 string
+	AllowedHosts        []string // 使用接收者作为请求地址时允许的域名
+	AllowPrivateNetwork bool     // 使用接收者作为请求地址时允许访问内网地址
+	Method              string
+	ContentType         string
+	BodyTemplate        string
+	Headers             map[string]string
+	Secret              string
+	Timeout             time.Duration
+	SuccessStatusCodes  []int
+	Client              *http.Client
+}
+
+// WebhookSender 通用 Webhook 发送器
+type WebhookSender struct {
+}
+
+// NewWebhookSender 创建通用 Webhook 发送器
+func NewWebhookSender() *WebhookSender {
+	return &WebhookSender{}
+}
+
+// GetProviderCode 获取服务商代码
+func (s *WebhookSender) GetProviderCode() string {
+	return constants.ProviderWebhook
+}
+
+// Send 发送 Webhook 消息
+func (s *WebhookSender) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	config, err := s.parseConfig(req)
+	if err != nil {
+		return nil, err
+	}
+
+	targetURL := config.URL
+	if targetURL == "" {
+		targetURL = req.Task.Receiver
+	}
+	if !strings.HasPrefix(targetURL, "http://") && !strings.HasPrefix(targetURL, "https://") {
+		return nil, fmt.Errorf("invalid webhook url: %s", targetURL)
+	}
+	if config.URL == "" {
+		parsed, err := url.Parse(targetURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook url: %w", err)
+		}
+		if !s.isAllowedHost(parsed.Hostname(), config.AllowedHosts) {
+			return nil, fmt.Errorf("webhook host not allowed: %s", parsed.Hostname())
+		}
+	}
+
+	body, err := s.buildBody(req, config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, config.Method, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", config.ContentType)
+	httpReq.Header.Set("User-Agent", "MessagePush-Webhook/1.0")
+	httpReq.Header.Set("X-Webhook-Task-Id", req.Task.TaskID)
+	for key, value := range config.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	if config.Secret != "" {
+		timestamp := time.Now().Unix()
+		mac := hmac.New(sha256.New, []byte(config.Secret))
+		mac.Write([]byte(fmt.Sprintf("%d.%s", timestamp, body)))
+		httpReq.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+		httpReq.Header.Set("X-Webhook-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	requestData, _ := json.Marshal(map[string]interface{}{
+		"method": config.Method,
+		"url":    targetURL,
+		"body":   string(body),
+	})
+
+	resp, err := config.Client.Do(httpReq)
+	if err != nil {
+		return &SendResponse{
+			Success:      false,
+			ErrorCode:    "REQUEST_FAILED",
+			ErrorMessage: err.Error(),
+			TaskID:       req.Task.TaskID,
+			RequestData:  string(requestData),
+		}, nil
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if !s.isSuccessStatus(resp.StatusCode, config.SuccessStatusCodes) {
+		return &SendResponse{
+			Success:      false,
+			ErrorCode:    strconv.Itoa(resp.StatusCode),
+			ErrorMessage: fmt.Sprintf("webhook returned status %d", resp.StatusCode),
+			TaskID:       req.Task.TaskID,
+			RequestData:  string(requestData),
+			ResponseData: string(respBody),
+		}, nil
+	}
+
+	return &SendResponse{
+		Success:      true,
+		ProviderID:   fmt.Sprintf("webhook_%s", req.Task.TaskID),
+		TaskID:       req.Task.TaskID,
+		Status:       constants.TaskStatusSuccess, // 接收方返回成功即完成
+		RequestData:  string(requestData),
+		ResponseData: string(respBody),
+	}, nil
+}
+
+// parseConfig 解析账号配置并填充默认值
+func (s *WebhookSender) parseConfig(req *SendRequest) (*webhookSenderConfig, error) {
+	raw, err := req.ProviderAccount.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider config: %w", err)
+	}
+
+	config := &webhookSenderConfig{
+		URL:          strings.TrimSpace(configString(raw, "url")),
+		Method:       strings.ToUpper(strings.TrimSpace(configString(raw, "method"))),
+		ContentType:  strings.TrimSpace(configString(raw, "content_type")),
+		BodyTemplate: configString(raw, "body_template"),
+		Secret:       configString(raw, "secret"),
+		Timeout:      time.Duration(DefaultTimeout) * time.Second,
+	}
+
+	switch config.Method {
+	case "":
+		config.Method = http.MethodPost
+	case http.MethodPost, http.MethodPut:
+	default:
+		return nil, fmt.Errorf("unsupported webhook method: %s", config.Method)
+	}
+
+	if config.ContentType == "" {
+		config.ContentType = "application/json"
+	}
+
+	if timeout, err := strconv.Atoi(configString(raw, "timeout")); err == nil && timeout > 0 {
+		config.Timeout = time.Duration(timeout) * time.Second
+	}
+
+	// 请求头可以是 JSON 对象或 JSON 字符串
+	switch headers := raw["headers"].(type) {
+	case map[string]interface{}:
+		config.Headers = make(map[string]string, len(headers))
+		for key, value := range headers {
+			config.Headers[key] = fmt.Sprint(value)
+		}
+	case string:
+		if strings.TrimSpace(headers) != "" {
+			if err := json.Unmarshal([]byte(headers), &config.Headers); err != nil {
+				return nil, fmt.Errorf("invalid webhook headers: %w", err)
+			}
+		}
+	}
+
+	for _, host := range strings.Split(configString(raw, "allowed_hosts"), ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			config.AllowedHosts = append(config.AllowedHosts, host)
+		}
+	}
+	config.AllowPrivateNetwork, _ = strconv.ParseBool(configString(raw, "allow_private_network"))
+
+	for _, code := range strings.Split(configString(raw, "success_status_codes"), ",") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		statusCode, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid success status code: %s", code)
+		}
+		config.SuccessStatusCodes = append(config.SuccessStatusCodes, statusCode)
+	}
+
+	// 请求地址来自接收者时默认拒绝访问内网地址
+	opts, err := ParseHTTPClientOptions(raw)
+	if err != nil {
+		return nil, err
+	}
+	opts.DenyPrivateNetwork = config.URL == "" && !config.AllowPrivateNetwork
+	if config.Client, err = GetHTTPClientFactory().Client(opts); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// buildBody 构造请求体
+// 未配置请求体模板时发送默认 JSON 结构
+func (s *WebhookSender) buildBody(req *SendRequest, config *webhookSenderConfig) ([]byte, error) {
+	params := req.MappedParams
+	if params == nil && req.Task.TemplateParams != "" {
+		_ = json.Unmarshal([]byte(req.Task.TemplateParams), &params)
+	}
+
+	if config.BodyTemplate == "" {
+		return json.Marshal(map[string]interface{}{
+			"task_id":   req.Task.TaskID,
+			"receiver":  req.Task.Receiver,
+			"title":     req.Task.Title,
+			"content":   req.Task.Content,
+			"params":    params,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+
+	vars := map[string]string{
+		"task_id":  req.Task.TaskID,
+		"receiver": req.Task.Receiver,
+		"title":    req.Task.Title,
+		"content":  req.Task.Content,
+	}
+	for key, value := range params {
+		if _, exists := vars[key]; !exists {
+			vars[key] = value
+		}
+	}
+
+	escapeJSON := strings.Contains(config.ContentType, "json")
+	body := webhookBodyVarPattern.ReplaceAllStringFunc(config.BodyTemplate, func(match string) string {
+		value, exists := vars[strings.Trim(match, "{}")]
+		if !exists {
+			return match
+		}
+		if escapeJSON {
+			// 转义为 JSON 字符串内容（去掉首尾引号），模板中应写作 "{变量名}"
+			quoted, _ := json.Marshal(value)
+			return string(quoted[1 : len(quoted)-1])
+		}
+		return value
+	})
+
+	return []byte(body), nil
+}
+
+// isAllowedHost 判断接收者地址的域名是否在允许列表中，未配置允许列表时不限制
+func (s *WebhookSender) isAllowedHost(host string, allowedHosts []string) bool {
+	if len(allowedHosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, allowed := range allowedHosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// isSuccessStatus 判断响应状态码是否视为成功
+func (s *WebhookSender) isSuccessStatus(statusCode int, successCodes []int) bool {
+	if len(successCodes) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	return slices.Contains(successCodes, statusCode)
+}
+
+// configString 读取配置项并转换为字符串（兼容数字类型的配置值）
+func configString(config map[string]interface{}, key string) string {
+	switch value := config[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,222 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/registry"
+)
+
+func init() {
+	// 注册企业微信群机器人服务商
+	registry.Register(&registry.ProviderMeta{
+		Code:        constants.ProviderWeChatWorkRobot,
+		Name:        "企业微信群机器人",
+		Type:        constants.MessageTypeWeChatWork,
+		Description: "企业微信群自定义机器人，支持文本、Markdown、图文链接和模板卡片消息，接收者填写需要 @ 的手机号或用户ID",
+		ConfigFields: append([]registry.ConfigField{
+			{
+				Key:         "webhook_url",
+				Label:       "Webhook地址",
+				Description: "群机器人的Webhook地址（包含 key 参数）",
+				Type:        registry.FieldTypeURL,
+				Required:    true,
+				Example:     "https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=xxxxxxxx",
+				Placeholder: "请输入Webhook地址",
+				HelpLink:    "https://developer.work.weixin.qq.com/document/path/91770",
+			},
+			{
+				Key:          "msg_type",
+				Label:        "消息类型",
+				Description:  "text（文本）、markdown、link（图文链接）或 action_card（模板卡片）；链接和卡片使用模板参数 url、pic_url",
+				Type:         registry.FieldTypeText,
+				Required:     false,
+				Example:      "text",
+				DefaultValue: "text",
+			},
+		}, httpClientConfigFields()...),
+		// 能力声明
+		SupportsSend:      true,
+		SupportsBatchSend: false,
+		SupportsCallback:  false,
+		// 扩展信息
+		Website:    "https://work.weixin.qq.com/",
+		Icon:       "/image/logo/wechat_work.png",
+		DocsUrl:    "https://developer.work.weixin.qq.com/document/path/91770",
+		ConsoleUrl: "",
+		PricingUrl: "",
+		SortOrder:  31,
+		Tags:       []string{"企业", "即时通讯", "机器人"},
+		Regions:    []string{"中国大陆"},
+		Deprecated: false,
+	})
+}
+
+// WeChatWorkRobotSender 企业微信群机器人发送器
+type WeChatWorkRobotSender struct{}
+
+// NewWeChatWorkRobotSender 创建企业微信群机器人发送器
+func NewWeChatWorkRobotSender() *WeChatWorkRobotSender {
+	return &WeChatWorkRobotSender{}
+}
+
+// GetProviderCode 获取服务商代码
+func (s *WeChatWorkRobotSender) GetProviderCode() string {
+	return constants.ProviderWeChatWorkRobot
+}
+
+// Send 发送群机器人消息
+func (s *WeChatWorkRobotSender) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	config, err := req.ProviderAccount.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider config: %w", err)
+	}
+
+	webhookURL := configString(config, "webhook_url")
+	if webhookURL == "" {
+		return nil, fmt.Errorf("missing wechat work robot config: webhook_url")
+	}
+
+	msgType := configString(config, "msg_type")
+	if msgType == "" {
+		msgType = "text"
+	}
+
+	payload, err := s.buildPayload(req, msgType)
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed, retryAfter := allowRobotSend(ctx, constants.ProviderWeChatWorkRobot, webhookURL); !allowed {
+		return robotRateLimitedResponse(req.Task.TaskID, retryAfter), nil
+	}
+
+	body, _ := json.Marshal(payload)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client, err := httpClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var respData struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(respBody, &respData); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	if respData.ErrCode != 0 {
+		return &SendResponse{
+			Success:      false,
+			ErrorCode:    strconv.Itoa(respData.ErrCode),
+			ErrorMessage: respData.ErrMsg,
+			TaskID:       req.Task.TaskID,
+			RequestData:  string(body),
+			ResponseData: string(respBody),
+		}, nil
+	}
+
+	return &SendResponse{
+		Success:      true,
+		ProviderID:   fmt.Sprintf("wechat_work_robot_%s", req.Task.TaskID), // 群机器人不返回消息ID
+		TaskID:       req.Task.TaskID,
+		Status:       constants.TaskStatusSuccess, // 群机器人消息发送成功即完成
+		RequestData:  string(body),
+		ResponseData: string(respBody),
+	}, nil
+}
+
+// buildPayload 构造群机器人消息
+func (s *WeChatWorkRobotSender) buildPayload(req *SendRequest, msgType string) (map[string]interface{}, error) {
+	task := req.Task
+	mentions := parseRobotMentions(task.Receiver)
+	params := robotMessageParams(req)
+
+	content := task.Content
+	if content == "" {
+		content = task.Title
+	}
+
+	switch msgType {
+	case "text":
+		mentionedList := mentions.UserIDs
+		if mentions.All {
+			mentionedList = append(mentionedList, "@all")
+		}
+		return map[string]interface{}{
+			"msgtype": "text",
+			"text": map[string]interface{}{
+				"content":               content,
+				"mentioned_list":        mentionedList,
+				"mentioned_mobile_list": mentions.Mobiles,
+			},
+		}, nil
+	case "markdown":
+		// Markdown 消息只支持通过 <@userid> 提醒成员
+		var builder strings.Builder
+		builder.WriteString(content)
+		for _, userID := range mentions.UserIDs {
+			builder.WriteString(fmt.Sprintf("\n<@%s>", userID))
+		}
+		return map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"content": builder.String(),
+			},
+		}, nil
+	case "link":
+		if params["url"] == "" {
+			return nil, fmt.Errorf("missing template param: url")
+		}
+		return map[string]interface{}{
+			"msgtype": "news",
+			"news": map[string]interface{}{
+				"articles": []map[string]string{{
+					"title":       task.Title,
+					"description": task.Content,
+					"url":         params["url"],
+					"picurl":      params["pic_url"],
+				}},
+			},
+		}, nil
+	case "action_card":
+		if params["url"] == "" {
+			return nil, fmt.Errorf("missing template param: url")
+		}
+		return map[string]interface{}{
+			"msgtype": "template_card",
+			"template_card": map[string]interface{}{
+				"card_type": "text_notice",
+				"main_title": map[string]string{
+					"title": task.Title,
+				},
+				"sub_title_text": task.Content,
+				"card_action": map[string]interface{}{
+					"type": 1,
+					"url":  params["url"],
+				},
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported wechat work robot msg_type: %s", msgType)
+	}
+}
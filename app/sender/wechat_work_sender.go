@@ -12,7 +12,6 @@ import (
 
 	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/registry"
-	"cnb.cool/mliev/push/message-push/internal/helper"
 )
 
 func init() {
@@ -22,7 +21,7 @@ func init() {
 		Name:        "企业微信",
 		Type:        constants.MessageTypeWeChatWork,
 		Description: "企业微信应用消息服务，支持文本和Markdown消息",
-		ConfigFields: []registry.ConfigField{
+		ConfigFields: append([]registry.ConfigField{
 			{
 				Key:         "corp_id",
 				Label:       "企业ID",
@@ -52,7 +51,8 @@ func init() {
 				Example:     "1000002",
 				Placeholder: "请输入应用ID",
 			},
-		},
+			endpointConfigField(WeChatWorkDefaultEndpoint),
+		}, httpClientConfigFields()...),
 		// 能力声明
 		SupportsSend:      true,
 		SupportsBatchSend: true,
@@ -95,13 +95,7 @@ func (s *WeChatWorkSender) Send(ctx context.Context, req *SendRequest) (*SendRes
 		return nil, fmt.Errorf("missing wechat work config: corp_id, agent_secret or agent_id")
 	}
 
-	// 1. 获取 Access Token
-	token, err := s.getAccessToken(ctx, corpID, agentSecret)
-	if err != nil {
-		return nil, err
-	}
-
-	// 2. 构造消息
+	// 1. 构造消息
 	// 默认发送文本消息，支持markdown
 	msgType := "text"
 	if req.Task.MessageType == "markdown" {
@@ -139,15 +133,12 @@ func (s *WeChatWorkSender) Send(ctx context.Context, req *SendRequest) (*SendRes
 
 	body, _ := json.Marshal(payload)
 
-	// 3. 发送请求
-	apiURL := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/message/send?access_token=%s", token)
-	resp, err := http.Post(apiURL, "application/json", bytes.NewBuffer(body))
+	// 2. 发送请求
+	respBody, err := s.postMessage(ctx, config, corpID, agentSecret, body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respBody, _ := io.ReadAll(resp.Body)
 	var respData struct {
 		ErrCode int    `json:"errcode"`
 		ErrMsg  string `json:"errmsg"`
@@ -175,43 +166,79 @@ func (s *WeChatWorkSender) Send(ctx context.Context, req *SendRequest) (*SendRes
 	}, nil
 }
 
-func (s *WeChatWorkSender) getAccessToken(ctx context.Context, corpID, secret string) (string, error) {
-	redisClient := helper.GetHelper().GetRedis()
-	key := fmt.Sprintf("wechat:token:%s:%s", corpID, secret)
-
-	// 尝试从Redis获取
-	token, err := redisClient.Get(ctx, key).Result()
-	if err == nil && token != "" {
-		return token, nil
-	}
-
-	// 从API获取
-	apiURL := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/gettoken?corpid=%s&corpsecret=%s", corpID, secret)
-	resp, err := http.Get(apiURL)
+// postMessage 发送应用消息请求，令牌无效时强制刷新并重试一次
+func (s *WeChatWorkSender) postMessage(ctx context.Context, config map[string]interface{}, corpID, secret string, body []byte) ([]byte, error) {
+	endpoint := configEndpoint(config, WeChatWorkDefaultEndpoint)
+	client, err := httpClientFor(config)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var data struct {
-		ErrCode     int    `json:"errcode"`
-		ErrMsg      string `json:"errmsg"`
-		AccessToken string `json:"access_token"`
-		ExpiresIn   int    `json:"expires_in"`
-	}
+	key := AccessTokenKey(constants.ProviderWeChatWork, endpoint, corpID, secret)
+	for attempt := 0; ; attempt++ {
+		token, err := s.getAccessToken(ctx, client, key, endpoint, corpID, secret)
+		if err != nil {
+			return nil, err
+		}
 
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", err
-	}
+		apiURL := fmt.Sprintf("%s/cgi-bin/message/send?access_token=%s", endpoint, token)
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
 
-	if data.ErrCode != 0 {
-		return "", fmt.Errorf("failed to get access token: %s", data.ErrMsg)
+		var respData struct {
+			ErrCode int `json:"errcode"`
+		}
+		_ = json.Unmarshal(respBody, &respData)
+		if attempt == 0 && isInvalidTokenCode(weChatWorkInvalidTokenCodes, respData.ErrCode) {
+			GetTokenManager().Invalidate(ctx, key, token)
+			continue
+		}
+		return respBody, nil
 	}
+}
+
+// getAccessToken 获取 Access Token（由令牌管理器缓存）
+func (s *WeChatWorkSender) getAccessToken(ctx context.Context, client *http.Client, key, endpoint, corpID, secret string) (string, error) {
+	return GetTokenManager().Get(ctx, key, func(ctx context.Context) (string, time.Duration, error) {
+		apiURL := fmt.Sprintf("%s/cgi-bin/gettoken?corpid=%s&corpsecret=%s", endpoint, corpID, secret)
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+		if err != nil {
+			return "", 0, err
+		}
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return "", 0, err
+		}
+		defer resp.Body.Close()
+
+		var data struct {
+			ErrCode     int    `json:"errcode"`
+			ErrMsg      string `json:"errmsg"`
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int    `json:"expires_in"`
+		}
 
-	// 缓存到Redis
-	redisClient.Set(ctx, key, data.AccessToken, time.Duration(data.ExpiresIn-200)*time.Second)
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			return "", 0, err
+		}
+
+		if data.ErrCode != 0 {
+			return "", 0, fmt.Errorf("failed to get access token: %s", data.ErrMsg)
+		}
 
-	return data.AccessToken, nil
+		return data.AccessToken, time.Duration(data.ExpiresIn) * time.Second, nil
+	})
 }
 
 // ==================== BatchSender 接口实现 ====================
@@ -240,12 +267,6 @@ func (s *WeChatWorkSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 		return nil, fmt.Errorf("missing wechat work config: corp_id, agent_secret or agent_id")
 	}
 
-	// 获取 Access Token
-	token, err := s.getAccessToken(ctx, corpID, agentSecret)
-	if err != nil {
-		return nil, err
-	}
-
 	// 收集所有用户ID（用 | 分隔）
 	var userIDs []string
 	for _, task := range req.Tasks {
@@ -287,14 +308,11 @@ func (s *WeChatWorkSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 	body, _ := json.Marshal(payload)
 
 	// 发送请求
-	apiURL := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/message/send?access_token=%s", token)
-	resp, err := http.Post(apiURL, "application/json", bytes.NewBuffer(body))
+	respBody, err := s.postMessage(ctx, config, corpID, agentSecret, body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respBody, _ := io.ReadAll(resp.Body)
 	var respData struct {
 		ErrCode      int    `json:"errcode"`
 		ErrMsg       string `json:"errmsg"`
@@ -15,11 +15,11 @@ import (
 	"cnb.cool/mliev/push/message-push/app/registry"
 )
 
-// 掌榕网 API 端点
+// 掌榕网 API 路径（基于账号配置的 API 地址）
 const (
-	zrwinfoSingleSendURL   = "http://api.1cloudsp.com/api/v2/single_send"
-	zrwinfoBatchSendURL    = "http://api.1cloudsp.com/api/v2/send"
-	zrwinfoReportStatusURL = "http://api.1cloudsp.com/report/status"
+	zrwinfoSingleSendPath   = "/api/v2/single_send"
+	zrwinfoBatchSendPath    = "/api/v2/send"
+	zrwinfoReportStatusPath = "/report/status"
 )
 
 func init() {
@@ -29,7 +29,7 @@ func init() {
 		Name:        "掌榕网短信",
 		Type:        constants.MessageTypeSMS,
 		Description: "掌榕网融合通信产品，提供短信、国际短信、语音、5G智慧短信等服务。注意：短信签名需在「签名管理」中单独配置",
-		ConfigFields: []registry.ConfigField{
+		ConfigFields: append([]registry.ConfigField{
 			{
 				Key:         "accesskey",
 				Label:       "AccessKey",
@@ -48,7 +48,8 @@ func init() {
 				Example:     "your_secret",
 				Placeholder: "请输入 Secret",
 			},
-		},
+			endpointConfigField(ZrwinfoDefaultEndpoint),
+		}, httpClientConfigFields()...),
 		// 能力声明
 		SupportsSend:       true,
 		SupportsBatchSend:  true,
@@ -67,17 +68,11 @@ func init() {
 }
 
 // ZrwinfoSMSSender 掌榕网短信发送器
-type ZrwinfoSMSSender struct {
-	client *http.Client
-}
+type ZrwinfoSMSSender struct{}
 
 // NewZrwinfoSMSSender 创建掌榕网短信发送器
 func NewZrwinfoSMSSender() *ZrwinfoSMSSender {
-	return &ZrwinfoSMSSender{
-		client: &http.Client{
-			Timeout: time.Duration(DefaultTimeout) * time.Second,
-		},
-	}
+	return &ZrwinfoSMSSender{}
 }
 
 // GetProviderCode 获取服务商代码
@@ -151,13 +146,17 @@ func (s *ZrwinfoSMSSender) Send(ctx context.Context, req *SendRequest) (*SendRes
 	})
 
 	// 6. 发送请求
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", zrwinfoSingleSendURL, strings.NewReader(params.Encode()))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", configEndpoint(config, ZrwinfoDefaultEndpoint)+zrwinfoSingleSendPath, strings.NewReader(params.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
 
-	resp, err := s.client.Do(httpReq)
+	client, err := httpClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(httpReq)
 	if err != nil {
 		return &SendResponse{
 			Success:      false,
@@ -311,11 +310,11 @@ func (s *ZrwinfoSMSSender) BatchSend(ctx context.Context, req *BatchSendRequest)
 	}
 
 	// 3. 批量发送使用 MappedParams（所有任务共用相同参数）
-	return s.batchSendSameContent(ctx, req, accesskey, secret, signName, templateCode, templateContent)
+	return s.batchSendSameContent(ctx, req, config, accesskey, secret, signName, templateCode, templateContent)
 }
 
 // batchSendSameContent 批量发送相同内容的短信
-func (s *ZrwinfoSMSSender) batchSendSameContent(ctx context.Context, req *BatchSendRequest, accesskey, secret, signName, templateCode, templateContent string) (*BatchSendResponse, error) {
+func (s *ZrwinfoSMSSender) batchSendSameContent(ctx context.Context, req *BatchSendRequest, config map[string]interface{}, accesskey, secret, signName, templateCode, templateContent string) (*BatchSendResponse, error) {
 	// 收集所有手机号
 	mobiles := make([]string, len(req.Tasks))
 	for i, task := range req.Tasks {
@@ -343,13 +342,17 @@ func (s *ZrwinfoSMSSender) batchSendSameContent(ctx context.Context, req *BatchS
 	})
 
 	// 发送请求
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", zrwinfoBatchSendURL, strings.NewReader(params.Encode()))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", configEndpoint(config, ZrwinfoDefaultEndpoint)+zrwinfoBatchSendPath, strings.NewReader(params.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
 
-	resp, err := s.client.Do(httpReq)
+	client, err := httpClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(httpReq)
 	if err != nil {
 		results := make([]*SendResponse, len(req.Tasks))
 		for i, task := range req.Tasks {
@@ -459,13 +462,17 @@ func (s *ZrwinfoSMSSender) PullStatus(ctx context.Context, req *StatusPullReques
 	params.Set("secret", secret)
 
 	// 3. 发送请求
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", zrwinfoReportStatusURL, strings.NewReader(params.Encode()))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", configEndpoint(config, ZrwinfoDefaultEndpoint)+zrwinfoReportStatusPath, strings.NewReader(params.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
 
-	resp, err := s.client.Do(httpReq)
+	client, err := httpClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -5,8 +5,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
+	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/dao"
 	"cnb.cool/mliev/push/message-push/app/dto"
 	apphelper "cnb.cool/mliev/push/message-push/app/helper"
@@ -79,14 +81,16 @@ func (s *AdminApplicationService) CreateApplication(req *dto.CreateApplicationRe
 	}
 
 	app := &model.Application{
-		AppID:       appID,
-		AppSecret:   encryptedSecret,
-		AppName:     req.Name,
-		Status:      status,
-		DailyQuota:  dailyQuota,
-		RateLimit:   rateLimit,
-		IPWhitelist: ipWhitelist,
-		WebhookURL:  req.WebhookURL,
+		AppID:           appID,
+		AppSecret:       encryptedSecret,
+		AppName:         req.Name,
+		Status:          status,
+		DailyQuota:      dailyQuota,
+		RateLimit:       rateLimit,
+		IPWhitelist:     ipWhitelist,
+		WebhookURL:      req.WebhookURL,
+		Sandbox:         req.Sandbox,
+		FrequencyLimits: model.FormatFrequencyLimits(convertDTOFrequencyLimitsToModel(req.FrequencyLimits)),
 	}
 
 	if err := dao.CreateApp(app); err != nil {
@@ -96,19 +100,27 @@ func (s *AdminApplicationService) CreateApplication(req *dto.CreateApplicationRe
 
 	logger.Info("应用创建成功")
 
+	if app.WebhookURL != "" {
+		if err := s.ensureWebhookConfig(app.AppID, app.WebhookURL); err != nil {
+			logger.Error(fmt.Sprintf("创建应用Webhook配置失败: %v", err))
+		}
+	}
+
 	return &dto.ApplicationResponse{
-		ID:          app.ID,
-		AppName:     app.AppName,
-		Description: req.Description,
-		AppID:       appID,
-		AppSecret:   appSecret, // 仅创建时返回明文
-		Status:      int(app.Status),
-		DailyQuota:  app.DailyQuota,
-		RateLimit:   app.RateLimit,
-		IPWhitelist: app.IPWhitelist,
-		WebhookURL:  app.WebhookURL,
-		CreatedAt:   app.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   app.UpdatedAt.Format(time.RFC3339),
+		ID:              app.ID,
+		AppName:         app.AppName,
+		Description:     req.Description,
+		AppID:           appID,
+		AppSecret:       appSecret, // 仅创建时返回明文
+		Status:          int(app.Status),
+		DailyQuota:      app.DailyQuota,
+		RateLimit:       app.RateLimit,
+		IPWhitelist:     app.IPWhitelist,
+		WebhookURL:      app.WebhookURL,
+		Sandbox:         app.Sandbox,
+		FrequencyLimits: convertModelFrequencyLimitsToDTO(app.FrequencyLimits),
+		CreatedAt:       app.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       app.UpdatedAt.Format(time.RFC3339),
 	}, nil
 }
 
@@ -150,17 +162,19 @@ func (s *AdminApplicationService) GetApplicationList(req *dto.ApplicationListReq
 	items := make([]*dto.ApplicationResponse, 0, len(apps))
 	for _, app := range apps {
 		items = append(items, &dto.ApplicationResponse{
-			ID:          app.ID,
-			AppName:     app.AppName,
-			Description: "",
-			AppID:       app.AppID,
-			Status:      int(app.Status),
-			DailyQuota:  app.DailyQuota,
-			RateLimit:   app.RateLimit,
-			IPWhitelist: app.IPWhitelist,
-			WebhookURL:  app.WebhookURL,
-			CreatedAt:   app.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:   app.UpdatedAt.Format(time.RFC3339),
+			ID:              app.ID,
+			AppName:         app.AppName,
+			Description:     "",
+			AppID:           app.AppID,
+			Status:          int(app.Status),
+			DailyQuota:      app.DailyQuota,
+			RateLimit:       app.RateLimit,
+			IPWhitelist:     app.IPWhitelist,
+			WebhookURL:      app.WebhookURL,
+			Sandbox:         app.Sandbox,
+			FrequencyLimits: convertModelFrequencyLimitsToDTO(app.FrequencyLimits),
+			CreatedAt:       app.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:       app.UpdatedAt.Format(time.RFC3339),
 		})
 	}
 
@@ -180,17 +194,19 @@ func (s *AdminApplicationService) GetApplicationByID(id uint) (*dto.ApplicationR
 	}
 
 	return &dto.ApplicationResponse{
-		ID:          app.ID,
-		AppName:     app.AppName,
-		Description: "",
-		AppID:       app.AppID,
-		Status:      int(app.Status),
-		DailyQuota:  app.DailyQuota,
-		RateLimit:   app.RateLimit,
-		IPWhitelist: app.IPWhitelist,
-		WebhookURL:  app.WebhookURL,
-		CreatedAt:   app.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   app.UpdatedAt.Format(time.RFC3339),
+		ID:              app.ID,
+		AppName:         app.AppName,
+		Description:     "",
+		AppID:           app.AppID,
+		Status:          int(app.Status),
+		DailyQuota:      app.DailyQuota,
+		RateLimit:       app.RateLimit,
+		IPWhitelist:     app.IPWhitelist,
+		WebhookURL:      app.WebhookURL,
+		Sandbox:         app.Sandbox,
+		FrequencyLimits: convertModelFrequencyLimitsToDTO(app.FrequencyLimits),
+		CreatedAt:       app.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       app.UpdatedAt.Format(time.RFC3339),
 	}, nil
 }
 
@@ -214,6 +230,12 @@ func (s *AdminApplicationService) UpdateApplication(id uint, req *dto.UpdateAppl
 	if req.WebhookURL != "" {
 		updates["webhook_url"] = req.WebhookURL
 	}
+	if req.Sandbox != nil {
+		updates["sandbox"] = *req.Sandbox
+	}
+	if req.FrequencyLimits != nil {
+		updates["frequency_limits"] = model.FormatFrequencyLimits(convertDTOFrequencyLimitsToModel(req.FrequencyLimits))
+	}
 
 	// 处理IP白名单（允许清空）
 	if req.IPWhitelist != "" {
@@ -228,7 +250,64 @@ func (s *AdminApplicationService) UpdateApplication(id uint, req *dto.UpdateAppl
 		return nil
 	}
 
-	return dao.UpdateApp(id, updates)
+	if err := dao.UpdateApp(id, updates); err != nil {
+		return err
+	}
+
+	if req.Sandbox != nil || req.FrequencyLimits != nil {
+		app, err := dao.GetAppByID(id)
+		if err != nil {
+			return err
+		}
+		if req.Sandbox != nil {
+			NewSandboxService().Invalidate(context.Background(), app.AppID)
+		}
+		if req.FrequencyLimits != nil {
+			NewFrequencyService().Invalidate(context.Background(), app.AppID)
+		}
+	}
+
+	if req.WebhookURL != "" {
+		app, err := dao.GetAppByID(id)
+		if err != nil {
+			return err
+		}
+		return s.ensureWebhookConfig(app.AppID, req.WebhookURL)
+	}
+
+	return nil
+}
+
+// ensureWebhookConfig 兼容应用旧的 webhook_url 字段：确保该地址存在对应的 Webhook 配置
+// 多个回调地址请通过 /applications/:id/webhooks 管理
+func (s *AdminApplicationService) ensureWebhookConfig(appID, webhookURL string) error {
+	webhookConfigDao := dao.NewWebhookConfigDAO()
+
+	configs, err := webhookConfigDao.ListByAppID(appID)
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		if config.WebhookURL == webhookURL {
+			return nil
+		}
+	}
+
+	secret, err := generateRandomKey(32)
+	if err != nil {
+		return err
+	}
+
+	return webhookConfigDao.Create(&model.WebhookConfig{
+		AppID:      appID,
+		Name:       "default",
+		WebhookURL: webhookURL,
+		Secret:     secret,
+		Events:     strings.Join([]string{constants.WebhookEventTaskDelivered, constants.WebhookEventTaskFailed}, ","),
+		Status:     1,
+		RetryCount: 3,
+		Timeout:    5,
+	})
 }
 
 // DeleteApplication 删除应用
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"cnb.cool/mliev/push/message-push/app/circuit"
 	"cnb.cool/mliev/push/message-push/app/dao"
 	"cnb.cool/mliev/push/message-push/app/dto"
 	"cnb.cool/mliev/push/message-push/app/model"
@@ -53,6 +55,8 @@ type AdminChannelService struct {
 	signatureMappingDAO  *dao.ChannelSignatureMappingDAO
 	providerSignatureDAO *dao.ProviderSignatureDAO
 	channelSelector      *selector.ChannelSelector // 用于在配置变更时重置缓存和权重
+	bindingEventDAO      *dao.ChannelBindingEventDAO
+	bindingHealth        *BindingHealthService
 }
 
 // NewAdminChannelService 创建通道管理服务实例
@@ -65,6 +69,8 @@ func NewAdminChannelService() *AdminChannelService {
 		signatureMappingDAO:  dao.NewChannelSignatureMappingDAO(db),
 		providerSignatureDAO: dao.NewProviderSignatureDAO(db),
 		channelSelector:      selector.NewChannelSelector(),
+		bindingEventDAO:      dao.NewChannelBindingEventDAO(),
+		bindingHealth:        NewBindingHealthService(),
 	}
 }
 
@@ -102,6 +108,7 @@ func (s *AdminChannelService) CreateChannel(req *dto.CreateChannelRequest) (*dto
 		Type:              req.Type,
 		MessageTemplateID: req.MessageTemplateID,
 		Status:            status,
+		FrequencyLimits:   model.FormatFrequencyLimits(convertDTOFrequencyLimitsToModel(req.FrequencyLimits)),
 	}
 
 	// 创建通道（不再自动创建绑定，由用户手动配置）
@@ -119,6 +126,7 @@ func (s *AdminChannelService) CreateChannel(req *dto.CreateChannelRequest) (*dto
 		MessageTemplateID: channel.MessageTemplateID,
 		TemplateName:      messageTemplate.TemplateName,
 		Status:            int(channel.Status),
+		FrequencyLimits:   convertModelFrequencyLimitsToDTO(channel.FrequencyLimits),
 		CreatedAt:         channel.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:         channel.UpdatedAt.Format(time.RFC3339),
 	}, nil
@@ -167,6 +175,7 @@ func (s *AdminChannelService) GetChannelList(req *dto.ChannelListRequest) (*dto.
 			Type:              channel.Type,
 			MessageTemplateID: channel.MessageTemplateID,
 			Status:            int(channel.Status),
+			FrequencyLimits:   convertModelFrequencyLimitsToDTO(channel.FrequencyLimits),
 			CreatedAt:         channel.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:         channel.UpdatedAt.Format(time.RFC3339),
 		}
@@ -204,6 +213,7 @@ func (s *AdminChannelService) GetChannelByID(id uint) (*dto.ChannelResponse, err
 		Type:              channel.Type,
 		MessageTemplateID: channel.MessageTemplateID,
 		Status:            int(channel.Status),
+		FrequencyLimits:   convertModelFrequencyLimitsToDTO(channel.FrequencyLimits),
 		CreatedAt:         channel.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:         channel.UpdatedAt.Format(time.RFC3339),
 		Bindings:          bindings,
@@ -225,6 +235,9 @@ func (s *AdminChannelService) UpdateChannel(id uint, req *dto.UpdateChannelReque
 	if req.Status > 0 {
 		updates["status"] = int8(req.Status)
 	}
+	if req.FrequencyLimits != nil {
+		updates["frequency_limits"] = model.FormatFrequencyLimits(convertDTOFrequencyLimitsToModel(req.FrequencyLimits))
+	}
 
 	if len(updates) == 0 {
 		return nil
@@ -292,6 +305,8 @@ func (s *AdminChannelService) GetChannelBindings(channelID uint) ([]*dto.Channel
 			IsActive:             b.IsActive,
 			AutoDisableOnFail:    b.AutoDisableOnFail,
 			AutoDisableThreshold: b.AutoDisableThreshold,
+			ExtendCode:           b.ExtendCode,
+			CircuitState:         s.getCircuitState(b.ID),
 			CreatedAt:            b.CreatedAt.Format(time.RFC3339),
 		}
 
@@ -342,6 +357,9 @@ func (s *AdminChannelService) UpdateChannelBinding(bindingID uint, req *dto.Upda
 	if req.AutoDisableThreshold > 0 {
 		updates["auto_disable_threshold"] = req.AutoDisableThreshold
 	}
+	if req.ExtendCode != nil {
+		updates["extend_code"] = *req.ExtendCode
+	}
 
 	if len(updates) == 0 {
 		return nil
@@ -378,6 +396,96 @@ func (s *AdminChannelService) DeleteChannelBinding(bindingID uint) error {
 	return nil
 }
 
+// getCircuitState 获取绑定的熔断器状态
+func (s *AdminChannelService) getCircuitState(bindingID uint) string {
+	snapshot, err := s.channelSelector.GetBreakerSnapshot(context.Background(), bindingID)
+	if err != nil {
+		return "unknown"
+	}
+	return snapshot.State.String()
+}
+
+// GetBindingCircuitBreaker 获取通道绑定的熔断器详情
+func (s *AdminChannelService) GetBindingCircuitBreaker(bindingID uint) (*dto.CircuitBreakerResponse, error) {
+	if _, err := s.bindingDAO.GetByID(bindingID); err != nil {
+		return nil, fmt.Errorf("binding not found: %w", err)
+	}
+
+	snapshot, err := s.channelSelector.GetBreakerSnapshot(context.Background(), bindingID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.CircuitBreakerResponse{
+		BindingID:           bindingID,
+		State:               snapshot.State.String(),
+		Requests:            snapshot.Requests,
+		Failures:            snapshot.Failures,
+		ConsecutiveFailures: snapshot.ConsecutiveFailures,
+		OpenedAt:            formatOptionalTime(snapshot.OpenedAt),
+		LastFailureAt:       formatOptionalTime(snapshot.LastFailureAt),
+	}
+	if snapshot.State != circuit.StateClosed {
+		resp.RetryAt = formatOptionalTime(snapshot.Expiry)
+	}
+
+	return resp, nil
+}
+
+// ResetBindingCircuitBreaker 手动重置通道绑定的熔断器
+func (s *AdminChannelService) ResetBindingCircuitBreaker(bindingID uint) error {
+	if _, err := s.bindingDAO.GetByID(bindingID); err != nil {
+		return fmt.Errorf("binding not found: %w", err)
+	}
+
+	return s.channelSelector.ResetBreaker(context.Background(), bindingID)
+}
+
+// EnableChannelBinding 重新启用被自动停用的通道绑定
+func (s *AdminChannelService) EnableChannelBinding(bindingID uint, operator string) error {
+	return s.bindingHealth.Enable(context.Background(), bindingID, operator)
+}
+
+// GetChannelBindingEvents 获取通道绑定事件列表（自动停用、重新启用）
+func (s *AdminChannelService) GetChannelBindingEvents(channelID uint, bindingID uint, page, pageSize int) (*dto.ChannelBindingEventListResponse, error) {
+	events, total, err := s.bindingEventDAO.ListByChannelID(channelID, bindingID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.ChannelBindingEventResponse, 0, len(events))
+	for _, e := range events {
+		items = append(items, &dto.ChannelBindingEventResponse{
+			ID:                  e.ID,
+			ChannelID:           e.ChannelID,
+			BindingID:           e.BindingID,
+			ProviderID:          e.ProviderID,
+			EventType:           e.EventType,
+			ConsecutiveFailures: e.ConsecutiveFailures,
+			ErrorCode:           e.ErrorCode,
+			ErrorMessage:        e.ErrorMessage,
+			Operator:            e.Operator,
+			CreatedAt:           e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &dto.ChannelBindingEventListResponse{
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+		Items: items,
+	}, nil
+}
+
+// formatOptionalTime 格式化可选时间（零值返回 nil）
+func formatOptionalTime(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
+
 // GetActiveChannels 获取活跃通道列表
 func (s *AdminChannelService) GetActiveChannels() ([]*dto.ActiveItem, error) {
 	var channels []*model.Channel
@@ -418,6 +526,8 @@ func (s *AdminChannelService) GetChannelBinding(bindingID uint) (*dto.ChannelBin
 		IsActive:             binding.IsActive,
 		AutoDisableOnFail:    binding.AutoDisableOnFail,
 		AutoDisableThreshold: binding.AutoDisableThreshold,
+		ExtendCode:           binding.ExtendCode,
+		CircuitState:         s.getCircuitState(binding.ID),
 		CreatedAt:            binding.CreatedAt.Format(time.RFC3339),
 	}
 
@@ -507,6 +617,7 @@ func (s *AdminChannelService) CreateChannelBinding(channelID uint, req *dto.Crea
 		IsActive:             isActive,
 		AutoDisableOnFail:    req.AutoDisableOnFail,
 		AutoDisableThreshold: autoDisableThreshold,
+		ExtendCode:           req.ExtendCode,
 	}
 
 	// 设置参数映射
@@ -541,6 +652,8 @@ func (s *AdminChannelService) CreateChannelBinding(channelID uint, req *dto.Crea
 		IsActive:             binding.IsActive,
 		AutoDisableOnFail:    binding.AutoDisableOnFail,
 		AutoDisableThreshold: binding.AutoDisableThreshold,
+		ExtendCode:           binding.ExtendCode,
+		CircuitState:         s.getCircuitState(binding.ID),
 		CreatedAt:            binding.CreatedAt.Format(time.RFC3339),
 	}
 
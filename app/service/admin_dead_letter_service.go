@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/app/queue"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// AdminDeadLetterService 管理后台死信队列服务
+type AdminDeadLetterService struct {
+	deadLetterQueue *queue.DeadLetterQueue
+	producer        *queue.Producer
+	pushTaskDAO     *dao.PushTaskDAO
+	taskService     *AdminTaskService
+	batchProgress   *BatchProgressService
+	logger          gsr.Logger
+}
+
+// NewAdminDeadLetterService 创建服务
+func NewAdminDeadLetterService() *AdminDeadLetterService {
+	h := helper.GetHelper()
+	return &AdminDeadLetterService{
+		deadLetterQueue: queue.NewDeadLetterQueue(h.GetRedis()),
+		producer:        queue.NewProducer(h.GetRedis()),
+		pushTaskDAO:     dao.NewPushTaskDAO(),
+		taskService:     NewAdminTaskService(),
+		batchProgress:   NewBatchProgressService(),
+		logger:          h.GetLogger(),
+	}
+}
+
+// GetDeadLetterList 获取死信消息列表（按进入时间倒序）
+func (s *AdminDeadLetterService) GetDeadLetterList(ctx context.Context, req *dto.DeadLetterListRequest) (*dto.DeadLetterListResponse, error) {
+	total, err := s.deadLetterQueue.Len(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	letters, next, err := s.deadLetterQueue.List(ctx, req.Cursor, int64(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.DeadLetterItem, 0, len(letters))
+	for _, letter := range letters {
+		items = append(items, s.convertDeadLetterToItem(letter))
+	}
+
+	return &dto.DeadLetterListResponse{
+		Total:      total,
+		Size:       req.PageSize,
+		NextCursor: next,
+		Items:      items,
+	}, nil
+}
+
+// GetDeadLetter 获取单条死信消息详情
+func (s *AdminDeadLetterService) GetDeadLetter(ctx context.Context, id string) (*dto.DeadLetterItem, error) {
+	letter, err := s.deadLetterQueue.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.convertDeadLetterToItem(letter), nil
+}
+
+// ReplayDeadLetter 重放单条死信消息
+func (s *AdminDeadLetterService) ReplayDeadLetter(ctx context.Context, id string, targetChannelID uint) error {
+	letter, err := s.deadLetterQueue.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	targetChannel, err := s.getTargetChannel(targetChannelID)
+	if err != nil {
+		return err
+	}
+
+	return s.replay(ctx, letter, nil, targetChannel)
+}
+
+// ReplayDeadLetters 批量重放死信消息
+func (s *AdminDeadLetterService) ReplayDeadLetters(ctx context.Context, req *dto.DeadLetterBatchReplayRequest) (*dto.DeadLetterReplayResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	var startTime, endTime time.Time
+	var err error
+	if req.StartTime != "" {
+		if startTime, err = time.Parse(time.RFC3339, req.StartTime); err != nil {
+			return nil, fmt.Errorf("invalid start_time: %w", err)
+		}
+	}
+	if req.EndTime != "" {
+		if endTime, err = time.Parse(time.RFC3339, req.EndTime); err != nil {
+			return nil, fmt.Errorf("invalid end_time: %w", err)
+		}
+	}
+
+	targetChannel, err := s.getTargetChannel(req.TargetChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.DeadLetterReplayResponse{}
+	replayOne := func(letter *queue.DeadLetter, task *model.PushTask) {
+		if err := s.replay(ctx, letter, task, targetChannel); err != nil {
+			resp.Failed++
+			resp.Errors = append(resp.Errors, &dto.DeadLetterReplayFailed{
+				ID:     letter.ID,
+				TaskID: letter.TaskID,
+				Error:  err.Error(),
+			})
+			return
+		}
+		resp.Replayed++
+	}
+
+	// 指定了消息 ID，仅重放这些消息
+	if len(req.IDs) > 0 {
+		if len(req.IDs) > limit {
+			return nil, fmt.Errorf("too many ids, max %d", limit)
+		}
+		for _, id := range req.IDs {
+			letter, err := s.deadLetterQueue.Get(ctx, id)
+			if err != nil {
+				resp.Failed++
+				resp.Errors = append(resp.Errors, &dto.DeadLetterReplayFailed{ID: id, Error: err.Error()})
+				continue
+			}
+			replayOne(letter, nil)
+		}
+		return resp, nil
+	}
+
+	// 按过滤条件从最早的消息开始扫描
+	after := ""
+	for resp.Replayed+resp.Failed < limit {
+		letters, err := s.deadLetterQueue.Range(ctx, startTime, endTime, after, 100)
+		if err != nil {
+			return resp, err
+		}
+		if len(letters) == 0 {
+			break
+		}
+
+		for _, letter := range letters {
+			if resp.Replayed+resp.Failed >= limit {
+				break
+			}
+
+			task, err := s.pushTaskDAO.GetByTaskID(letter.TaskID)
+			if err != nil {
+				// 任务不存在时无法判断过滤条件，仅在无过滤条件时计为失败
+				if req.AppID == "" && req.ChannelID == 0 {
+					resp.Failed++
+					resp.Errors = append(resp.Errors, &dto.DeadLetterReplayFailed{
+						ID:     letter.ID,
+						TaskID: letter.TaskID,
+						Error:  fmt.Sprintf("failed to get task: %v", err),
+					})
+				}
+				continue
+			}
+			if req.AppID != "" && task.AppID != req.AppID {
+				continue
+			}
+			if req.ChannelID > 0 && task.ChannelID != req.ChannelID {
+				continue
+			}
+
+			replayOne(letter, task)
+		}
+
+		after = letters[len(letters)-1].ID
+	}
+
+	return resp, nil
+}
+
+// DeleteDeadLetter 删除单条死信消息
+func (s *AdminDeadLetterService) DeleteDeadLetter(ctx context.Context, id string) error {
+	deleted, err := s.deadLetterQueue.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return queue.ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+// TrimDeadLetters 裁剪死信队列
+func (s *AdminDeadLetterService) TrimDeadLetters(ctx context.Context, req *dto.DeadLetterTrimRequest) (*dto.DeadLetterTrimResponse, error) {
+	if req.Before == "" && req.MaxLen == nil {
+		return nil, fmt.Errorf("before or max_len is required")
+	}
+
+	resp := &dto.DeadLetterTrimResponse{}
+
+	if req.Before != "" {
+		before, err := time.Parse(time.RFC3339, req.Before)
+		if err != nil {
+			return nil, fmt.Errorf("invalid before: %w", err)
+		}
+		deleted, err := s.deadLetterQueue.TrimBefore(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+		resp.Deleted += deleted
+	}
+
+	if req.MaxLen != nil {
+		if *req.MaxLen < 0 {
+			return nil, fmt.Errorf("max_len must not be negative")
+		}
+		deleted, err := s.deadLetterQueue.TrimMaxLen(ctx, *req.MaxLen)
+		if err != nil {
+			return nil, err
+		}
+		resp.Deleted += deleted
+	}
+
+	s.logger.Info(fmt.Sprintf("dead letter queue trimmed deleted=%d", resp.Deleted))
+	return resp, nil
+}
+
+// PurgeDeadLetters 清空死信队列
+func (s *AdminDeadLetterService) PurgeDeadLetters(ctx context.Context) (*dto.DeadLetterTrimResponse, error) {
+	deleted, err := s.deadLetterQueue.Purge(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn(fmt.Sprintf("dead letter queue purged deleted=%d", deleted))
+	return &dto.DeadLetterTrimResponse{Deleted: deleted}, nil
+}
+
+// getTargetChannel 获取并校验重放目标通道，targetChannelID 为0时返回 nil（使用原通道）
+func (s *AdminDeadLetterService) getTargetChannel(targetChannelID uint) (*model.Channel, error) {
+	if targetChannelID == 0 {
+		return nil, nil
+	}
+
+	var channel model.Channel
+	if err := helper.GetHelper().GetDatabase().First(&channel, targetChannelID).Error; err != nil {
+		return nil, fmt.Errorf("invalid target_channel_id: %w", err)
+	}
+
+	if channel.Status != 1 {
+		return nil, fmt.Errorf("target channel is not active")
+	}
+
+	return &channel, nil
+}
+
+// replay 将死信消息对应的任务重新推送到消息队列，成功后从死信队列删除
+// task 为空时按死信消息中的 task_id 加载
+func (s *AdminDeadLetterService) replay(ctx context.Context, letter *queue.DeadLetter, task *model.PushTask, targetChannel *model.Channel) error {
+	if task == nil {
+		if letter.TaskID == "" {
+			return errors.New("dead letter has no task_id")
+		}
+
+		var err error
+		task, err = s.pushTaskDAO.GetByTaskID(letter.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+	}
+
+	// 已成功的任务不再重放，避免重复发送
+	if task.Status == constants.TaskStatusSuccess {
+		return errors.New("task already succeeded")
+	}
+
+	if targetChannel != nil && targetChannel.ID != task.ChannelID {
+		if targetChannel.Type != task.MessageType {
+			return fmt.Errorf("target channel type %s does not match message type %s", targetChannel.Type, task.MessageType)
+		}
+		task.ChannelID = targetChannel.ID
+		// 切换通道后原有的供应商排除列表不再适用
+		task.SetExcludeProviderIDs(nil)
+	}
+
+	task.Status = constants.TaskStatusPending
+	task.RetryCount = 0
+	task.NextRetryAt = nil
+	if err := s.pushTaskDAO.Update(task); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	if err := s.producer.Push(ctx, task); err != nil {
+		return fmt.Errorf("failed to push task to queue: %w", err)
+	}
+
+	// 批次内任务重新进入处理，恢复批次进度
+	s.batchProgress.Refresh(task.BatchID)
+
+	if _, err := s.deadLetterQueue.Delete(ctx, letter.ID); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to delete replayed dead letter id=%s: %v", letter.ID, err))
+	}
+
+	s.logger.Info(fmt.Sprintf("dead letter replayed id=%s task_id=%s channel_id=%d", letter.ID, task.TaskID, task.ChannelID))
+	return nil
+}
+
+// convertDeadLetterToItem 转换死信消息为DTO
+func (s *AdminDeadLetterService) convertDeadLetterToItem(letter *queue.DeadLetter) *dto.DeadLetterItem {
+	item := &dto.DeadLetterItem{
+		ID:                letter.ID,
+		TaskID:            letter.TaskID,
+		LastError:         letter.Error,
+		OriginalMessageID: letter.OriginalID,
+		Consumer:          letter.Consumer,
+		DeadAt:            letter.DeadAt.Format(time.RFC3339),
+		Data:              letter.Data,
+	}
+
+	if letter.TaskID != "" {
+		if task, err := s.pushTaskDAO.GetByTaskID(letter.TaskID); err == nil {
+			item.Task = s.taskService.convertPushTaskToItem(task)
+		}
+
+		// 旧版本死信消息未记录错误，使用最新推送日志中的错误信息
+		if item.LastError == "" {
+			if logs, err := s.taskService.pushLogDAO.GetByTaskID(letter.TaskID); err == nil && len(logs) > 0 {
+				item.LastError = logs[0].ErrorMessage
+			}
+		}
+	}
+
+	return item
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrFrequencyAllowlistExists 接收者已在白名单中
+	ErrFrequencyAllowlistExists = errors.New("receiver already in frequency allowlist")
+	// ErrFrequencyAllowlistNotFound 白名单记录不存在
+	ErrFrequencyAllowlistNotFound = errors.New("frequency allowlist entry not found")
+)
+
+// convertModelFrequencyLimitsToDTO 将频率限制规则（JSON）转换为 dto.FrequencyLimitItem
+func convertModelFrequencyLimitsToDTO(raw string) []dto.FrequencyLimitItem {
+	limits, err := model.ParseFrequencyLimits(raw)
+	if err != nil || len(limits) == 0 {
+		return []dto.FrequencyLimitItem{}
+	}
+	result := make([]dto.FrequencyLimitItem, len(limits))
+	for i, limit := range limits {
+		result[i] = dto.FrequencyLimitItem{
+			Window: limit.Window,
+			Limit:  limit.Limit,
+		}
+	}
+	return result
+}
+
+// convertDTOFrequencyLimitsToModel 将 dto.FrequencyLimitItem 转换为 model.FrequencyLimit
+func convertDTOFrequencyLimitsToModel(items []dto.FrequencyLimitItem) []model.FrequencyLimit {
+	if items == nil {
+		return nil
+	}
+	result := make([]model.FrequencyLimit, len(items))
+	for i, item := range items {
+		result[i] = model.FrequencyLimit{
+			Window: item.Window,
+			Limit:  item.Limit,
+		}
+	}
+	return result
+}
+
+// AdminFrequencyService 频率限制白名单管理服务
+type AdminFrequencyService struct {
+	allowlistDao *dao.FrequencyAllowlistDAO
+}
+
+// NewAdminFrequencyService 创建频率限制白名单管理服务
+func NewAdminFrequencyService() *AdminFrequencyService {
+	return &AdminFrequencyService{
+		allowlistDao: dao.NewFrequencyAllowlistDAO(),
+	}
+}
+
+// GetAllowlist 获取应用的频率限制白名单
+func (s *AdminFrequencyService) GetAllowlist(appDBID uint, receiver string, page, pageSize int) (*dto.FrequencyAllowlistListResponse, error) {
+	app, err := s.getApplication(appDBID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, total, err := s.allowlistDao.ListByAppID(app.AppID, receiver, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frequency allowlist: %w", err)
+	}
+
+	items := make([]*dto.FrequencyAllowlistResponse, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, s.convertToResponse(entry))
+	}
+
+	return &dto.FrequencyAllowlistListResponse{
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+		Items: items,
+	}, nil
+}
+
+// AddAllowlist 添加频率限制白名单
+func (s *AdminFrequencyService) AddAllowlist(appDBID uint, req *dto.CreateFrequencyAllowlistRequest) (*dto.FrequencyAllowlistResponse, error) {
+	app, err := s.getApplication(appDBID)
+	if err != nil {
+		return nil, err
+	}
+
+	receiver := strings.TrimSpace(req.Receiver)
+	exists, err := s.allowlistDao.ExistsByAppIDAndReceiver(app.AppID, receiver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check frequency allowlist: %w", err)
+	}
+	if exists {
+		return nil, ErrFrequencyAllowlistExists
+	}
+
+	entry := &model.FrequencyAllowlist{
+		AppID:    app.AppID,
+		Receiver: receiver,
+		Remark:   req.Remark,
+	}
+	if err := s.allowlistDao.Create(entry); err != nil {
+		return nil, fmt.Errorf("failed to create frequency allowlist: %w", err)
+	}
+
+	return s.convertToResponse(entry), nil
+}
+
+// DeleteAllowlist 删除频率限制白名单
+func (s *AdminFrequencyService) DeleteAllowlist(appDBID, id uint) error {
+	app, err := s.getApplication(appDBID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := s.allowlistDao.DeleteByIDAndAppID(id, app.AppID)
+	if err != nil {
+		return fmt.Errorf("failed to delete frequency allowlist: %w", err)
+	}
+	if affected == 0 {
+		return ErrFrequencyAllowlistNotFound
+	}
+	return nil
+}
+
+// getApplication 获取应用
+func (s *AdminFrequencyService) getApplication(appDBID uint) (*model.Application, error) {
+	app, err := dao.GetAppByID(appDBID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	return app, nil
+}
+
+// convertToResponse 转换为响应
+func (s *AdminFrequencyService) convertToResponse(entry *model.FrequencyAllowlist) *dto.FrequencyAllowlistResponse {
+	return &dto.FrequencyAllowlistResponse{
+		ID:        entry.ID,
+		Receiver:  entry.Receiver,
+		Remark:    entry.Remark,
+		CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/dto"
+)
+
+// AdminInboundService 上行消息管理服务
+type AdminInboundService struct {
+	inboundDao *dao.InboundMessageDAO
+}
+
+// NewAdminInboundService 创建上行消息管理服务
+func NewAdminInboundService() *AdminInboundService {
+	return &AdminInboundService{
+		inboundDao: dao.NewInboundMessageDAO(),
+	}
+}
+
+// GetInboundMessageList 获取上行消息列表
+func (s *AdminInboundService) GetInboundMessageList(req *dto.InboundMessageListRequest) (*dto.InboundMessageListResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	filters := make(map[string]interface{})
+	if req.Phone != "" {
+		filters["phone"] = req.Phone
+	}
+	if req.AppID != "" {
+		filters["app_id"] = req.AppID
+	}
+	if req.TaskID != "" {
+		filters["task_id"] = req.TaskID
+	}
+	if req.ProviderAccountID > 0 {
+		filters["provider_account_id"] = req.ProviderAccountID
+	}
+	if req.Unsubscribe != nil {
+		filters["unsubscribe"] = *req.Unsubscribe
+	}
+
+	messages, total, err := s.inboundDao.List(req.Page, req.PageSize, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inbound message list: %w", err)
+	}
+
+	items := make([]*dto.InboundMessageResponse, 0, len(messages))
+	for _, msg := range messages {
+		var sequenceID string
+		if msg.SequenceID != nil {
+			sequenceID = *msg.SequenceID
+		}
+		items = append(items, &dto.InboundMessageResponse{
+			ID:                msg.ID,
+			AppID:             msg.AppID,
+			TaskID:            msg.TaskID,
+			ProviderAccountID: msg.ProviderAccountID,
+			ProviderCode:      msg.ProviderCode,
+			Phone:             msg.Phone,
+			Content:           msg.Content,
+			SignName:          msg.SignName,
+			ExtendCode:        msg.ExtendCode,
+			DestCode:          msg.DestCode,
+			SequenceID:        sequenceID,
+			Unsubscribe:       msg.Unsubscribe,
+			ReceivedAt:        msg.ReceivedAt.Format(time.RFC3339),
+			CreatedAt:         msg.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &dto.InboundMessageListResponse{
+		Total: total,
+		Page:  req.Page,
+		Size:  req.PageSize,
+		Items: items,
+	}, nil
+}
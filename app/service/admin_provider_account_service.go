@@ -36,6 +36,17 @@ func (s *AdminProviderAccountService) generateCallbackURL(c *gin.Context, accoun
 	return appHelper.GetBaseURL(c, fmt.Sprintf("/api/callback/%d", accountID))
 }
 
+// generateInboundURL 生成上行消息推送地址
+// 仅当服务商支持上行消息时返回URL，否则返回空字符串
+func (s *AdminProviderAccountService) generateInboundURL(c *gin.Context, accountID uint, providerCode string) string {
+	meta, err := registry.GetByCode(providerCode)
+	if err != nil || !meta.SupportsInbound {
+		return ""
+	}
+
+	return appHelper.GetBaseURL(c, fmt.Sprintf("/api/callback/%d/inbound", accountID))
+}
+
 // GetAvailableProviders 获取可用的服务商列表（从注册中心）
 func (s *AdminProviderAccountService) GetAvailableProviders(providerType string) ([]*dto.AvailableProviderResponse, error) {
 	var providers []*registry.ProviderMeta
@@ -70,11 +81,13 @@ func (s *AdminProviderAccountService) GetAvailableProviders(providerType string)
 			Code:              p.Code,
 			Name:              p.Name,
 			Type:              p.Type,
+			MessageTypes:      p.MessageTypes,
 			Description:       p.Description,
 			ConfigFields:      configFields,
 			SupportsSend:      p.SupportsSend,
 			SupportsBatchSend: p.SupportsBatchSend,
 			SupportsCallback:  p.SupportsCallback,
+			SupportsInbound:   p.SupportsInbound,
 			// 扩展信息
 			Website:    p.Website,
 			Icon:       p.Icon,
@@ -127,6 +140,15 @@ func (s *AdminProviderAccountService) CreateProviderAccount(c *gin.Context, req
 		return nil, fmt.Errorf("invalid provider_code: %s not registered", req.ProviderCode)
 	}
 
+	// 消息类型从注册信息中获取，支持多种消息类型的服务商可由请求指定
+	providerType := meta.Type
+	if req.ProviderType != "" {
+		if !meta.SupportsMessageType(req.ProviderType) {
+			return nil, fmt.Errorf("invalid provider_type: %s does not support %s", req.ProviderCode, req.ProviderType)
+		}
+		providerType = req.ProviderType
+	}
+
 	status := int8(req.Status)
 	if status == 0 {
 		status = 1 // 默认启用
@@ -143,7 +165,7 @@ func (s *AdminProviderAccountService) CreateProviderAccount(c *gin.Context, req
 		AccountCode:  accountCode,
 		AccountName:  req.Name,
 		ProviderCode: req.ProviderCode,
-		ProviderType: meta.Type, // 从注册信息中获取类型
+		ProviderType: providerType,
 		Status:       status,
 		Remark:       req.Description,
 	}
@@ -174,6 +196,7 @@ func (s *AdminProviderAccountService) CreateProviderAccount(c *gin.Context, req
 		Config:       config,
 		Status:       int(account.Status),
 		CallbackURL:  s.generateCallbackURL(c, account.ID, account.ProviderCode),
+		InboundURL:   s.generateInboundURL(c, account.ID, account.ProviderCode),
 		CreatedAt:    account.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:    account.UpdatedAt.Format(time.RFC3339),
 	}, nil
@@ -217,6 +240,7 @@ func (s *AdminProviderAccountService) GetProviderAccountList(c *gin.Context, req
 			Config:       config,
 			Status:       int(account.Status),
 			CallbackURL:  s.generateCallbackURL(c, account.ID, account.ProviderCode),
+			InboundURL:   s.generateInboundURL(c, account.ID, account.ProviderCode),
 			CreatedAt:    account.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:    account.UpdatedAt.Format(time.RFC3339),
 		})
@@ -257,6 +281,7 @@ func (s *AdminProviderAccountService) GetProviderAccountByID(c *gin.Context, id
 		Config:       config,
 		Status:       int(account.Status),
 		CallbackURL:  s.generateCallbackURL(c, account.ID, account.ProviderCode),
+		InboundURL:   s.generateInboundURL(c, account.ID, account.ProviderCode),
 		CreatedAt:    account.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:    account.UpdatedAt.Format(time.RFC3339),
 	}, nil
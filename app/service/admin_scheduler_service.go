@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"cnb.cool/mliev/push/message-push/app/dto"
+	apphelper "cnb.cool/mliev/push/message-push/app/helper"
+	"cnb.cool/mliev/push/message-push/app/scheduler"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+)
+
+// AdminSchedulerService 管理后台调度器服务
+type AdminSchedulerService struct{}
+
+// NewAdminSchedulerService 创建服务
+func NewAdminSchedulerService() *AdminSchedulerService {
+	return &AdminSchedulerService{}
+}
+
+// GetLeader 获取当前调度器 leader
+func (s *AdminSchedulerService) GetLeader(ctx context.Context) (*dto.SchedulerLeaderResponse, error) {
+	info, err := scheduler.GetLeaderInfo(ctx, helper.GetHelper().GetRedis())
+	if err != nil {
+		return nil, err
+	}
+
+	currentInstanceID := apphelper.ResolveInstanceID()
+	return &dto.SchedulerLeaderResponse{
+		LeaderInstanceID:  info.InstanceID,
+		TTLSeconds:        int64(info.TTL.Seconds()),
+		CurrentInstanceID: currentInstanceID,
+		IsCurrentLeader:   info.InstanceID != "" && info.InstanceID == currentInstanceID,
+	}, nil
+}
@@ -1,13 +1,16 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
 
 	"cnb.cool/mliev/push/message-push/app/dto"
 	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/app/queue"
 	"cnb.cool/mliev/push/message-push/internal/helper"
 )
 
@@ -26,6 +29,9 @@ func (s *AdminStatisticsService) GetStatistics(req *dto.StatisticsRequest) (*dto
 	// 基本查询
 	query := db.Model(&model.PushLog{}).Where("DATE(created_at) >= ? AND DATE(created_at) <= ?", req.StartDate, req.EndDate)
 
+	// 频率限制拒绝数来自应用配额统计
+	throttledQuery := db.Model(&model.AppQuotaStat{}).Where("stat_date >= ? AND stat_date <= ?", req.StartDate, req.EndDate)
+
 	// 条件过滤
 	if req.AppID > 0 {
 		// PushLog 中的 AppID 是 string 类型 (app_id string)，这里 req.AppID 是 uint
@@ -33,6 +39,7 @@ func (s *AdminStatisticsService) GetStatistics(req *dto.StatisticsRequest) (*dto
 		var app model.Application
 		if err := db.First(&app, req.AppID).Error; err == nil {
 			query = query.Where("app_id = ?", app.AppID)
+			throttledQuery = throttledQuery.Where("app_id = ?", app.AppID)
 		}
 	}
 	if req.ChannelID > 0 {
@@ -70,6 +77,21 @@ func (s *AdminStatisticsService) GetStatistics(req *dto.StatisticsRequest) (*dto
 		Order("date ASC").
 		Scan(&dailyStats)
 
+	// 每日频率限制拒绝数
+	var throttledStats []struct {
+		StatDate  time.Time
+		Throttled int64
+	}
+	throttledQuery.
+		Select("stat_date, SUM(throttled_count) as throttled").
+		Group("stat_date").
+		Scan(&throttledStats)
+
+	throttledByDate := make(map[string]int64, len(throttledStats))
+	for _, stat := range throttledStats {
+		throttledByDate[stat.StatDate.Format("2006-01-02")] = stat.Throttled
+	}
+
 	// 构建响应
 	response := &dto.StatisticsResponse{}
 	response.Summary.TotalCount = summary.Total
@@ -83,19 +105,41 @@ func (s *AdminStatisticsService) GetStatistics(req *dto.StatisticsRequest) (*dto
 
 	response.Daily = make([]*dto.DailyStatistics, 0, len(dailyStats))
 	for _, stat := range dailyStats {
+		// 部分驱动返回的日期带时间部分，按 YYYY-MM-DD 匹配
+		dateKey := stat.Date
+		if len(dateKey) > 10 {
+			dateKey = dateKey[:10]
+		}
 		failed := stat.Total - stat.Success
 		successRate := "0.00%"
 		if stat.Total > 0 {
 			successRate = fmt.Sprintf("%.2f%%", float64(stat.Success)/float64(stat.Total)*100)
 		}
 		response.Daily = append(response.Daily, &dto.DailyStatistics{
-			Date:         stat.Date,
-			TotalCount:   stat.Total,
-			SuccessCount: stat.Success,
-			FailureCount: failed,
-			SuccessRate:  successRate,
+			Date:           stat.Date,
+			TotalCount:     stat.Total,
+			SuccessCount:   stat.Success,
+			FailureCount:   failed,
+			SuccessRate:    successRate,
+			ThrottledCount: throttledByDate[dateKey],
+		})
+		delete(throttledByDate, dateKey)
+	}
+
+	// 只有频率限制拒绝、没有发送记录的日期
+	for date, throttled := range throttledByDate {
+		response.Daily = append(response.Daily, &dto.DailyStatistics{
+			Date:           date,
+			SuccessRate:    "0.00%",
+			ThrottledCount: throttled,
 		})
 	}
+	for _, stat := range throttledStats {
+		response.Summary.ThrottledCount += stat.Throttled
+	}
+	sort.Slice(response.Daily, func(i, j int) bool {
+		return response.Daily[i].Date < response.Daily[j].Date
+	})
 
 	return response, nil
 }
@@ -139,9 +183,21 @@ func (s *AdminStatisticsService) GetDashboard() (*dto.DashboardResponse, error)
 		resp.TodaySuccessRate = "0.00%"
 	}
 
+	// 今日频率限制拒绝数
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	db.Model(&model.AppQuotaStat{}).
+		Select("COALESCE(SUM(throttled_count), 0)").
+		Where("stat_date = ?", today).
+		Scan(&resp.TodayThrottledCount)
+
 	// 5. 统计总推送量
 	db.Model(&model.PushLog{}).Count(&resp.TotalPushCount)
 
+	// 6. 统计死信队列积压
+	if count, err := queue.NewDeadLetterQueue(helper.GetHelper().GetRedis()).Len(context.Background()); err == nil {
+		resp.DeadLetterCount = count
+	}
+
 	return resp, nil
 }
 
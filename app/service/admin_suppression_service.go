@@ -0,0 +1,394 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"gorm.io/gorm"
+)
+
+// MaxSuppressionImportRows 单次导入的最大数据行数
+const MaxSuppressionImportRows = 10000
+
+// maxSuppressionImportErrors 导入结果中最多返回的错误行数
+const maxSuppressionImportErrors = 100
+
+var (
+	// ErrSuppressionNotFound 屏蔽记录不存在
+	ErrSuppressionNotFound = errors.New("suppression not found")
+	// ErrSuppressionExists 接收者在同一范围内已有屏蔽记录
+	ErrSuppressionExists = errors.New("receiver already suppressed in this scope")
+	// ErrInvalidSuppression 屏蔽记录参数无效
+	ErrInvalidSuppression = errors.New("invalid suppression")
+)
+
+// AdminSuppressionService 屏蔽名单管理服务
+type AdminSuppressionService struct {
+	suppressionDao *dao.SuppressionDAO
+	appDao         *dao.ApplicationDAO
+	db             *gorm.DB
+}
+
+// NewAdminSuppressionService 创建屏蔽名单管理服务
+func NewAdminSuppressionService() *AdminSuppressionService {
+	return &AdminSuppressionService{
+		suppressionDao: dao.NewSuppressionDAO(),
+		appDao:         dao.NewApplicationDAO(),
+		db:             helper.GetHelper().GetDatabase(),
+	}
+}
+
+// GetSuppressionList 获取屏蔽名单列表
+func (s *AdminSuppressionService) GetSuppressionList(req *dto.SuppressionListRequest) (*dto.SuppressionListResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	filters := make(map[string]interface{})
+	if req.Receiver != "" {
+		filters["receiver"] = req.Receiver
+	}
+	if req.Type != "" {
+		filters["type"] = req.Type
+	}
+	if req.Scope != "" {
+		filters["scope"] = req.Scope
+	}
+	if req.AppID != "" {
+		filters["app_id"] = req.AppID
+	}
+	if req.ChannelID > 0 {
+		filters["channel_id"] = req.ChannelID
+	}
+
+	entries, total, err := s.suppressionDao.List(req.Page, req.PageSize, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suppression list: %w", err)
+	}
+
+	items := make([]*dto.SuppressionResponse, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, s.convertToResponse(entry))
+	}
+
+	return &dto.SuppressionListResponse{
+		Total: total,
+		Page:  req.Page,
+		Size:  req.PageSize,
+		Items: items,
+	}, nil
+}
+
+// GetSuppression 获取屏蔽记录详情
+func (s *AdminSuppressionService) GetSuppression(id uint) (*dto.SuppressionResponse, error) {
+	entry, err := s.getSuppression(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.convertToResponse(entry), nil
+}
+
+// CreateSuppression 添加屏蔽记录
+func (s *AdminSuppressionService) CreateSuppression(req *dto.CreateSuppressionRequest) (*dto.SuppressionResponse, error) {
+	entry := &model.Suppression{
+		Receiver:  req.Receiver,
+		Type:      req.Type,
+		Scope:     req.Scope,
+		AppID:     req.AppID,
+		ChannelID: req.ChannelID,
+		Reason:    req.Reason,
+		Source:    model.SuppressionSourceManual,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.normalize(entry, nil); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.suppressionDao.Exists(entry.Receiver, entry.Scope, entry.AppID, entry.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check suppression: %w", err)
+	}
+	if exists {
+		return nil, ErrSuppressionExists
+	}
+
+	if err := s.suppressionDao.Create(entry); err != nil {
+		return nil, fmt.Errorf("failed to create suppression: %w", err)
+	}
+
+	return s.convertToResponse(entry), nil
+}
+
+// UpdateSuppression 更新屏蔽记录的类型、原因和过期时间
+func (s *AdminSuppressionService) UpdateSuppression(id uint, req *dto.UpdateSuppressionRequest) (*dto.SuppressionResponse, error) {
+	entry, err := s.getSuppression(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type != nil {
+		entry.Type = *req.Type
+	}
+	if req.Reason != nil {
+		entry.Reason = *req.Reason
+	}
+	entry.ExpiresAt = req.ExpiresAt
+
+	if err := s.normalize(entry, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.suppressionDao.Update(entry); err != nil {
+		return nil, fmt.Errorf("failed to update suppression: %w", err)
+	}
+
+	return s.convertToResponse(entry), nil
+}
+
+// DeleteSuppression 删除屏蔽记录
+func (s *AdminSuppressionService) DeleteSuppression(id uint) error {
+	affected, err := s.suppressionDao.Delete(id)
+	if err != nil {
+		return fmt.Errorf("failed to delete suppression: %w", err)
+	}
+	if affected == 0 {
+		return ErrSuppressionNotFound
+	}
+	return nil
+}
+
+// ImportSuppressions 从 CSV 批量导入屏蔽记录
+// 首行为表头，支持的列：receiver（必填）、type、scope、app_id、channel_id、reason、expires_at，列顺序不限；
+// type 默认为 manual，scope 默认为 global；同一接收者和范围已存在时覆盖原记录
+func (s *AdminSuppressionService) ImportSuppressions(r io.Reader) (*dto.SuppressionImportResponse, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read csv header: %v", ErrInvalidSuppression, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	if _, ok := columns["receiver"]; !ok {
+		return nil, fmt.Errorf("%w: csv header must contain receiver column", ErrInvalidSuppression)
+	}
+
+	resp := &dto.SuppressionImportResponse{}
+	addError := func(line int, receiver string, err error) {
+		resp.Failed++
+		if len(resp.Errors) < maxSuppressionImportErrors {
+			resp.Errors = append(resp.Errors, &dto.SuppressionImportError{
+				Line:     line,
+				Receiver: receiver,
+				Error:    err.Error(),
+			})
+		}
+	}
+
+	// 同一文件中重复的接收者和范围以最后一行为准
+	checked := make(map[string]bool)
+	entries := make(map[string]*model.Suppression)
+	var keys []string
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		resp.Total++
+		if resp.Total > MaxSuppressionImportRows {
+			return nil, fmt.Errorf("%w: csv must not exceed %d rows", ErrInvalidSuppression, MaxSuppressionImportRows)
+		}
+		if err != nil {
+			addError(line, "", err)
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := &model.Suppression{
+			Receiver: field("receiver"),
+			Type:     field("type"),
+			Scope:    field("scope"),
+			AppID:    field("app_id"),
+			Reason:   field("reason"),
+			Source:   model.SuppressionSourceImport,
+		}
+		if entry.Type == "" {
+			entry.Type = model.SuppressionTypeManual
+		}
+		if entry.Scope == "" {
+			entry.Scope = model.SuppressionScopeGlobal
+		}
+		if value := field("channel_id"); value != "" {
+			channelID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				addError(line, entry.Receiver, fmt.Errorf("invalid channel_id: %s", value))
+				continue
+			}
+			entry.ChannelID = uint(channelID)
+		}
+		if value := field("expires_at"); value != "" {
+			expiresAt, err := parseSuppressionTime(value)
+			if err != nil {
+				addError(line, entry.Receiver, err)
+				continue
+			}
+			entry.ExpiresAt = &expiresAt
+		}
+
+		if err := s.normalize(entry, checked); err != nil {
+			addError(line, entry.Receiver, err)
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%s|%s|%d", entry.Receiver, entry.Scope, entry.AppID, entry.ChannelID)
+		if _, exists := entries[key]; !exists {
+			keys = append(keys, key)
+		}
+		entries[key] = entry
+	}
+
+	batch := make([]*model.Suppression, 0, len(keys))
+	for _, key := range keys {
+		batch = append(batch, entries[key])
+	}
+	if err := s.suppressionDao.Upsert(batch); err != nil {
+		return nil, fmt.Errorf("failed to import suppressions: %w", err)
+	}
+	resp.Imported = len(batch)
+
+	return resp, nil
+}
+
+// normalize 校验屏蔽记录并按范围清理无关字段
+// checked 用于缓存已校验存在的应用和通道（导入时避免重复查询），可为 nil
+func (s *AdminSuppressionService) normalize(entry *model.Suppression, checked map[string]bool) error {
+	entry.Receiver = strings.TrimSpace(entry.Receiver)
+	if entry.Receiver == "" {
+		return fmt.Errorf("%w: receiver is required", ErrInvalidSuppression)
+	}
+	if len(entry.Receiver) > 100 {
+		return fmt.Errorf("%w: receiver must not exceed 100 characters", ErrInvalidSuppression)
+	}
+	if !model.IsValidSuppressionType(entry.Type) {
+		return fmt.Errorf("%w: invalid type %s", ErrInvalidSuppression, entry.Type)
+	}
+	if len(entry.Reason) > 255 {
+		return fmt.Errorf("%w: reason must not exceed 255 characters", ErrInvalidSuppression)
+	}
+	if entry.ExpiresAt != nil && !entry.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidSuppression)
+	}
+
+	switch entry.Scope {
+	case model.SuppressionScopeGlobal:
+		entry.AppID = ""
+		entry.ChannelID = 0
+	case model.SuppressionScopeApp:
+		entry.ChannelID = 0
+		if entry.AppID == "" {
+			return fmt.Errorf("%w: app_id is required for app scope", ErrInvalidSuppression)
+		}
+		key := "app:" + entry.AppID
+		if !checked[key] {
+			if _, err := s.appDao.GetByAppID(entry.AppID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("%w: application %s not found", ErrInvalidSuppression, entry.AppID)
+				}
+				return fmt.Errorf("failed to get application: %w", err)
+			}
+			if checked != nil {
+				checked[key] = true
+			}
+		}
+	case model.SuppressionScopeChannel:
+		entry.AppID = ""
+		if entry.ChannelID == 0 {
+			return fmt.Errorf("%w: channel_id is required for channel scope", ErrInvalidSuppression)
+		}
+		key := fmt.Sprintf("channel:%d", entry.ChannelID)
+		if !checked[key] {
+			var channel model.Channel
+			if err := s.db.First(&channel, entry.ChannelID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("%w: channel %d not found", ErrInvalidSuppression, entry.ChannelID)
+				}
+				return fmt.Errorf("failed to get channel: %w", err)
+			}
+			if checked != nil {
+				checked[key] = true
+			}
+		}
+	default:
+		return fmt.Errorf("%w: invalid scope %s", ErrInvalidSuppression, entry.Scope)
+	}
+	return nil
+}
+
+// getSuppression 获取屏蔽记录
+func (s *AdminSuppressionService) getSuppression(id uint) (*model.Suppression, error) {
+	entry, err := s.suppressionDao.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSuppressionNotFound
+		}
+		return nil, fmt.Errorf("failed to get suppression: %w", err)
+	}
+	return entry, nil
+}
+
+// convertToResponse 转换为响应
+func (s *AdminSuppressionService) convertToResponse(entry *model.Suppression) *dto.SuppressionResponse {
+	resp := &dto.SuppressionResponse{
+		ID:        entry.ID,
+		Receiver:  entry.Receiver,
+		Type:      entry.Type,
+		Scope:     entry.Scope,
+		AppID:     entry.AppID,
+		ChannelID: entry.ChannelID,
+		Reason:    entry.Reason,
+		Source:    entry.Source,
+		CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: entry.UpdatedAt.Format(time.RFC3339),
+	}
+	if entry.ExpiresAt != nil {
+		resp.ExpiresAt = entry.ExpiresAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+// parseSuppressionTime 解析导入文件中的过期时间，支持 RFC3339、"2006-01-02 15:04:05" 和 "2006-01-02"（本地时区）
+func parseSuppressionTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid expires_at: %s", value)
+}
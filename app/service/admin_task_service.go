@@ -46,6 +46,9 @@ func (s *AdminTaskService) GetPushTaskList(req *dto.PushTaskListRequest) (*dto.P
 	if req.BatchID != "" {
 		filters["batch_id"] = req.BatchID
 	}
+	if req.IdempotencyKey != "" {
+		filters["idempotency_key"] = req.IdempotencyKey
+	}
 	if req.StartDate != "" {
 		filters["start_date"] = req.StartDate
 	}
@@ -89,6 +92,7 @@ func (s *AdminTaskService) GetPushTaskList(req *dto.PushTaskListRequest) (*dto.P
 			ID:             task.ID,
 			TaskID:         task.TaskID,
 			AppID:          task.AppID,
+			BatchID:        task.BatchID,
 			ChannelID:      task.ChannelID,
 			ProviderMsgID:  providerMsgID,
 			MessageType:    task.MessageType,
@@ -98,12 +102,14 @@ func (s *AdminTaskService) GetPushTaskList(req *dto.PushTaskListRequest) (*dto.P
 			TemplateCode:   task.TemplateCode,
 			TemplateParams: task.TemplateParams,
 			Signature:      task.Signature,
+			IdempotencyKey: task.IdempotencyKey,
 			Status:         task.Status,
 			CallbackStatus: task.CallbackStatus,
 			CallbackTime:   task.CallbackTime,
 			RetryCount:     task.RetryCount,
 			MaxRetry:       task.MaxRetry,
 			ScheduledAt:    task.ScheduledAt,
+			NextRetryAt:    task.NextRetryAt,
 			CreatedAt:      task.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:      task.UpdatedAt.Format(time.RFC3339),
 			ChannelName:    channelName,
@@ -224,6 +230,7 @@ func (s *AdminTaskService) convertPushTaskToItem(task *model.PushTask) *dto.Push
 		ID:             task.ID,
 		TaskID:         task.TaskID,
 		AppID:          task.AppID,
+		BatchID:        task.BatchID,
 		ChannelID:      task.ChannelID,
 		ProviderMsgID:  providerMsgID,
 		MessageType:    task.MessageType,
@@ -233,12 +240,14 @@ func (s *AdminTaskService) convertPushTaskToItem(task *model.PushTask) *dto.Push
 		TemplateCode:   task.TemplateCode,
 		TemplateParams: task.TemplateParams,
 		Signature:      task.Signature,
+		IdempotencyKey: task.IdempotencyKey,
 		Status:         task.Status,
 		CallbackStatus: task.CallbackStatus,
 		CallbackTime:   task.CallbackTime,
 		RetryCount:     task.RetryCount,
 		MaxRetry:       task.MaxRetry,
 		ScheduledAt:    task.ScheduledAt,
+		NextRetryAt:    task.NextRetryAt,
 		CreatedAt:      task.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:      task.UpdatedAt.Format(time.RFC3339),
 		ChannelName:    channelName,
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrApplicationNotFound 应用不存在
+	ErrApplicationNotFound = errors.New("application not found")
+	// ErrWebhookConfigNotFound Webhook 配置不存在
+	ErrWebhookConfigNotFound = errors.New("webhook config not found")
+	// ErrWebhookConfigDisabled Webhook 配置已停用
+	ErrWebhookConfigDisabled = errors.New("webhook config is disabled")
+	// ErrInvalidWebhookEvent 不支持订阅的事件
+	ErrInvalidWebhookEvent = errors.New("invalid webhook event")
+	// ErrWebhookLogNotFound Webhook 投递记录不存在
+	ErrWebhookLogNotFound = errors.New("webhook log not found")
+	// ErrInvalidTimeRange 时间范围无效
+	ErrInvalidTimeRange = errors.New("invalid time range")
+)
+
+// AdminWebhookService Webhook 配置管理服务
+type AdminWebhookService struct {
+	webhookConfigDao *dao.WebhookConfigDAO
+	webhookLogDao    *dao.WebhookLogDAO
+	webhookService   *WebhookService
+}
+
+// NewAdminWebhookService 创建 Webhook 配置管理服务
+func NewAdminWebhookService() *AdminWebhookService {
+	return &AdminWebhookService{
+		webhookConfigDao: dao.NewWebhookConfigDAO(),
+		webhookLogDao:    dao.NewWebhookLogDAO(),
+		webhookService:   NewWebhookService(),
+	}
+}
+
+// GetWebhookConfigs 获取应用的 Webhook 配置列表
+func (s *AdminWebhookService) GetWebhookConfigs(appDBID uint) (*dto.WebhookConfigListResponse, error) {
+	app, err := s.getApplication(appDBID)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := s.webhookConfigDao.ListByAppID(app.AppID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook configs: %w", err)
+	}
+
+	items := make([]*dto.WebhookConfigResponse, 0, len(configs))
+	for _, config := range configs {
+		items = append(items, s.convertToResponse(config))
+	}
+
+	return &dto.WebhookConfigListResponse{
+		Items:  items,
+		Events: constants.WebhookEvents,
+	}, nil
+}
+
+// CreateWebhookConfig 创建 Webhook 配置
+func (s *AdminWebhookService) CreateWebhookConfig(appDBID uint, req *dto.CreateWebhookConfigRequest) (*dto.WebhookConfigResponse, error) {
+	app, err := s.getApplication(appDBID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateRandomKey(32); err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+	}
+
+	config := &model.WebhookConfig{
+		AppID:         app.AppID,
+		Name:          req.Name,
+		WebhookURL:    req.WebhookURL,
+		Secret:        secret,
+		Events:        events,
+		Status:        1,
+		RetryCount:    3,
+		RetryInterval: 1,
+		Timeout:       5,
+		Description:   req.Description,
+	}
+	if req.Status != nil {
+		config.Status = *req.Status
+	}
+	if req.RetryCount != nil {
+		config.RetryCount = *req.RetryCount
+	}
+	if req.RetryInterval != nil {
+		config.RetryInterval = *req.RetryInterval
+	}
+	if req.Timeout != nil {
+		config.Timeout = *req.Timeout
+	}
+
+	if err := s.webhookConfigDao.Create(config); err != nil {
+		return nil, fmt.Errorf("failed to create webhook config: %w", err)
+	}
+
+	// 零值字段（禁用、不重试）创建时会被数据库默认值覆盖，需要再保存一次
+	if config.Status == 0 || config.RetryCount == 0 {
+		if err := s.webhookConfigDao.Update(config); err != nil {
+			return nil, fmt.Errorf("failed to update webhook config: %w", err)
+		}
+	}
+
+	return s.convertToResponse(config), nil
+}
+
+// UpdateWebhookConfig 更新 Webhook 配置
+func (s *AdminWebhookService) UpdateWebhookConfig(appDBID, id uint, req *dto.UpdateWebhookConfigRequest) (*dto.WebhookConfigResponse, error) {
+	config, err := s.getWebhookConfig(appDBID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		config.Name = *req.Name
+	}
+	if req.WebhookURL != "" {
+		config.WebhookURL = req.WebhookURL
+	}
+	if req.Secret != "" {
+		config.Secret = req.Secret
+	}
+	if len(req.Events) > 0 {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		config.Events = events
+	}
+	if req.Status != nil {
+		config.Status = *req.Status
+	}
+	if req.RetryCount != nil {
+		config.RetryCount = *req.RetryCount
+	}
+	if req.RetryInterval != nil {
+		config.RetryInterval = *req.RetryInterval
+	}
+	if req.Timeout != nil {
+		config.Timeout = *req.Timeout
+	}
+	if req.Description != nil {
+		config.Description = *req.Description
+	}
+
+	if err := s.webhookConfigDao.Update(config); err != nil {
+		return nil, fmt.Errorf("failed to update webhook config: %w", err)
+	}
+
+	return s.convertToResponse(config), nil
+}
+
+// DeleteWebhookConfig 删除 Webhook 配置
+func (s *AdminWebhookService) DeleteWebhookConfig(appDBID, id uint) error {
+	config, err := s.getWebhookConfig(appDBID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.webhookConfigDao.Delete(config.ID)
+}
+
+// SendTestEvent 向 Webhook 配置发送测试事件
+func (s *AdminWebhookService) SendTestEvent(ctx context.Context, appDBID, id uint) (*dto.WebhookTestResponse, error) {
+	config, err := s.getWebhookConfig(appDBID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookLog, err := s.webhookService.SendTestEvent(ctx, config)
+	if webhookLog == nil {
+		return nil, err
+	}
+
+	return &dto.WebhookTestResponse{
+		Success:        err == nil,
+		ResponseStatus: webhookLog.ResponseStatus,
+		ResponseData:   webhookLog.ResponseData,
+		ErrorMessage:   webhookLog.ErrorMessage,
+		LogID:          webhookLog.ID,
+	}, nil
+}
+
+// GetDeliveries 获取 Webhook 配置的投递记录
+func (s *AdminWebhookService) GetDeliveries(appDBID, id uint, event, status string, page, pageSize int) (*dto.WebhookDeliveryListResponse, error) {
+	config, err := s.getWebhookConfig(appDBID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, total, err := s.webhookLogDao.ListByWebhookConfigID(config.ID, event, status, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	items := make([]*dto.WebhookLogItem, 0, len(logs))
+	for _, log := range logs {
+		items = append(items, convertWebhookLogToItem(log))
+	}
+
+	return &dto.WebhookDeliveryListResponse{
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
+		Items: items,
+	}, nil
+}
+
+// RedeliverWebhookLog 重新投递单条 Webhook 记录
+func (s *AdminWebhookService) RedeliverWebhookLog(id uint) (*dto.WebhookLogItem, error) {
+	webhookLog, err := s.webhookLogDao.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookLogNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook log: %w", err)
+	}
+
+	redelivery, err := s.webhookService.Redeliver(webhookLog)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertWebhookLogToItem(redelivery), nil
+}
+
+// RedeliverFailedWebhookLogs 重新投递时间范围内投递失败的记录
+func (s *AdminWebhookService) RedeliverFailedWebhookLogs(req *dto.WebhookRedeliverRequest) (*dto.WebhookRedeliverResponse, error) {
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start_time: %v", ErrInvalidTimeRange, err)
+	}
+	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end_time: %v", ErrInvalidTimeRange, err)
+	}
+	if endTime.Before(startTime) {
+		return nil, fmt.Errorf("%w: end_time is before start_time", ErrInvalidTimeRange)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	logs, err := s.webhookLogDao.GetFailedByTimeRange(startTime, endTime, req.AppID, req.WebhookConfigID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed webhook logs: %w", err)
+	}
+
+	resp := &dto.WebhookRedeliverResponse{}
+	for _, log := range logs {
+		if _, err := s.webhookService.Redeliver(log); err != nil {
+			resp.Failed++
+			resp.Errors = append(resp.Errors, &dto.WebhookRedeliverFailed{
+				LogID: log.ID,
+				Error: err.Error(),
+			})
+			continue
+		}
+		resp.Redelivered++
+	}
+
+	return resp, nil
+}
+
+// getApplication 获取应用
+func (s *AdminWebhookService) getApplication(appDBID uint) (*model.Application, error) {
+	app, err := dao.GetAppByID(appDBID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	return app, nil
+}
+
+// getWebhookConfig 获取属于指定应用的 Webhook 配置
+func (s *AdminWebhookService) getWebhookConfig(appDBID, id uint) (*model.WebhookConfig, error) {
+	app, err := s.getApplication(appDBID)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := s.webhookConfigDao.GetByIDAndAppID(id, app.AppID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookConfigNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook config: %w", err)
+	}
+	return config, nil
+}
+
+// convertToResponse 转换为响应
+func (s *AdminWebhookService) convertToResponse(config *model.WebhookConfig) *dto.WebhookConfigResponse {
+	events := []string{}
+	if config.Events != "" {
+		events = strings.Split(config.Events, ",")
+	}
+
+	return &dto.WebhookConfigResponse{
+		ID:            config.ID,
+		AppID:         config.AppID,
+		Name:          config.Name,
+		WebhookURL:    config.WebhookURL,
+		Secret:        config.Secret,
+		Events:        events,
+		Status:        config.Status,
+		RetryCount:    config.RetryCount,
+		RetryInterval: config.RetryInterval,
+		Timeout:       config.Timeout,
+		Description:   config.Description,
+		CreatedAt:     config.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     config.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// convertWebhookLogToItem 转换投递记录
+func convertWebhookLogToItem(log *model.WebhookLog) *dto.WebhookLogItem {
+	item := &dto.WebhookLogItem{
+		ID:              log.ID,
+		TaskID:          log.TaskID,
+		AppID:           log.AppID,
+		WebhookConfigID: log.WebhookConfigID,
+		WebhookURL:      log.WebhookURL,
+		Event:           log.Event,
+		RequestData:     log.RequestData,
+		ResponseStatus:  log.ResponseStatus,
+		ResponseData:    log.ResponseData,
+		Status:          log.Status,
+		ErrorMessage:    log.ErrorMessage,
+		RetryCount:      log.RetryCount,
+		CreatedAt:       log.CreatedAt.Format(time.RFC3339),
+	}
+	if log.NextRetryAt != nil {
+		item.NextRetryAt = log.NextRetryAt.Format(time.RFC3339)
+	}
+	return item
+}
+
+// normalizeWebhookEvents 校验并去重订阅事件，旧版事件名转换为事件目录中的名称，返回逗号分隔的事件列表
+func normalizeWebhookEvents(events []string) (string, error) {
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		event = constants.NormalizeWebhookEvent(strings.TrimSpace(event))
+		if !constants.IsValidWebhookEvent(event) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidWebhookEvent, event)
+		}
+		if !slices.Contains(result, event) {
+			result = append(result, event)
+		}
+	}
+	return strings.Join(result, ","), nil
+}
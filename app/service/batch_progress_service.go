@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/dao"
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// BatchProgressService 批次进度服务
+// 任务进入或离开最终状态时原子地调整批次计数，全部任务结束时完成批次并通知 batch.completed 事件；
+// 仅在任务重新进入处理（如死信重放）时按任务表重新统计
+type BatchProgressService struct {
+	logger         gsr.Logger
+	taskDao        *dao.PushTaskDAO
+	batchDao       *dao.PushBatchTaskDAO
+	webhookService *WebhookService
+}
+
+// NewBatchProgressService 创建批次进度服务
+func NewBatchProgressService() *BatchProgressService {
+	return &BatchProgressService{
+		logger:         internalHelper.GetHelper().GetLogger(),
+		taskDao:        dao.NewPushTaskDAO(),
+		batchDao:       dao.NewPushBatchTaskDAO(),
+		webhookService: NewWebhookService(),
+	}
+}
+
+// Transition 记录批次内任务的状态变化
+// 未结束 → 结束时计入成功或失败数并减少未结束数，结束状态之间切换（如回执改判）时转移计数，
+// 结束 → 未结束时按任务表重新统计；未结束状态之间的变化不影响计数
+func (s *BatchProgressService) Transition(batchID, fromStatus, toStatus string) {
+	if batchID == "" || fromStatus == toStatus {
+		return
+	}
+
+	fromFinal, toFinal := isBatchFinalStatus(fromStatus), isBatchFinalStatus(toStatus)
+	switch {
+	case !fromFinal && !toFinal:
+		return
+	case fromFinal && !toFinal:
+		s.Refresh(batchID)
+		return
+	}
+
+	var successDelta, failedDelta, pendingDelta int
+	if fromFinal {
+		if fromStatus == constants.TaskStatusSuccess {
+			successDelta--
+		} else {
+			failedDelta--
+		}
+	} else {
+		pendingDelta--
+	}
+	if toStatus == constants.TaskStatusSuccess {
+		successDelta++
+	} else {
+		failedDelta++
+	}
+
+	if err := s.batchDao.AdjustCounts(batchID, successDelta, failedDelta, pendingDelta); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to adjust batch counts batch_id=%s: %v", batchID, err))
+		return
+	}
+
+	s.CompleteIfDone(batchID)
+}
+
+// Refresh 按任务表重新统计批次进度
+// 用于任务重新进入处理（如死信重放）后恢复批次计数，仍有未结束任务时批次恢复为处理中
+func (s *BatchProgressService) Refresh(batchID string) {
+	if batchID == "" {
+		return
+	}
+
+	counts, err := s.taskDao.CountByBatchIDGroupByStatus(batchID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to count batch tasks batch_id=%s: %v", batchID, err))
+		return
+	}
+
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+	successCount := counts[constants.TaskStatusSuccess]
+	// 命中屏蔽名单的任务同样未送达，计入失败数
+	failedCount := counts[constants.TaskStatusFailed] + counts[constants.TaskStatusSuppressed]
+	pendingCount := total - successCount - failedCount
+
+	if err := s.batchDao.UpdateCounts(batchID, int(successCount), int(failedCount), int(pendingCount)); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to update batch counts batch_id=%s: %v", batchID, err))
+		return
+	}
+
+	// 仍有未结束的任务，恢复为处理中
+	if pendingCount > 0 {
+		for _, status := range []string{constants.BatchStatusCompleted, constants.BatchStatusFailed} {
+			if _, err := s.batchDao.UpdateStatusIf(batchID, status, constants.BatchStatusProcessing); err != nil {
+				s.logger.Error(fmt.Sprintf("failed to reopen batch batch_id=%s: %v", batchID, err))
+			}
+		}
+		return
+	}
+
+	s.CompleteIfDone(batchID)
+}
+
+// CompleteIfDone 批次没有未结束的任务时结束批次（全部失败标记为失败，否则为完成）并通知 batch.completed 事件
+// 条件更新保证多个 worker 同时结束最后几个任务时只通知一次
+func (s *BatchProgressService) CompleteIfDone(batchID string) {
+	updated, err := s.batchDao.CompleteIfDone(batchID, constants.BatchStatusProcessing, constants.BatchStatusCompleted, constants.BatchStatusFailed)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to complete batch batch_id=%s: %v", batchID, err))
+		return
+	}
+	if !updated {
+		return
+	}
+
+	batch, err := s.batchDao.GetByBatchID(batchID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to get batch batch_id=%s: %v", batchID, err))
+		return
+	}
+
+	s.logger.Info(fmt.Sprintf("batch completed batch_id=%s status=%s success=%d failed=%d",
+		batchID, batch.Status, batch.SuccessCount, batch.FailedCount))
+
+	go func() {
+		if err := s.webhookService.NotifyBatchCompleted(context.Background(), batch); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to notify batch completed webhook batch_id=%s: %v", batchID, err))
+		}
+	}()
+}
+
+// isBatchFinalStatus 任务是否已结束（计入批次的成功或失败数）
+func isBatchFinalStatus(status string) bool {
+	switch status {
+	case constants.TaskStatusSuccess, constants.TaskStatusFailed, constants.TaskStatusSuppressed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/app/selector"
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+	"github.com/redis/go-redis/v9"
+)
+
+// bindingFailCountKeyPrefix 通道绑定连续失败计数 key 前缀
+const bindingFailCountKeyPrefix = "binding_fail_count:"
+
+// bindingFailCountTTL 连续失败计数的兜底过期时间
+const bindingFailCountTTL = 24 * time.Hour
+
+// BindingHealthService 通道绑定健康服务
+// 按绑定统计连续发送失败次数，开启 AutoDisableOnFail 的绑定在达到阈值后自动停用
+type BindingHealthService struct {
+	logger            gsr.Logger
+	redis             *redis.Client
+	bindingDAO        *dao.ChannelTemplateBindingDAO
+	eventDAO          *dao.ChannelBindingEventDAO
+	channelSelector   *selector.ChannelSelector
+	httpClient        *http.Client
+	defaultWebhookURL string // 系统默认告警 Webhook URL
+}
+
+// NewBindingHealthService 创建通道绑定健康服务
+func NewBindingHealthService() *BindingHealthService {
+	h := internalHelper.GetHelper()
+	return &BindingHealthService{
+		logger:            h.GetLogger(),
+		redis:             h.GetRedis(),
+		bindingDAO:        dao.NewChannelTemplateBindingDAO(),
+		eventDAO:          dao.NewChannelBindingEventDAO(),
+		channelSelector:   selector.NewChannelSelector(),
+		defaultWebhookURL: h.GetEnv().GetString("alert.default_webhook_url", ""),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// buildBindingFailCountKey 构建连续失败计数 key
+func buildBindingFailCountKey(bindingID uint) string {
+	return fmt.Sprintf("%s%d", bindingFailCountKeyPrefix, bindingID)
+}
+
+// RecordSuccess 记录发送成功，清零连续失败计数
+func (s *BindingHealthService) RecordSuccess(ctx context.Context, binding *model.ChannelTemplateBinding) {
+	if binding == nil || !binding.AutoDisableOnFail {
+		return
+	}
+
+	if err := s.redis.Del(ctx, buildBindingFailCountKey(binding.ID)).Err(); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to reset binding fail count binding_id=%d: %v", binding.ID, err))
+	}
+}
+
+// RecordFailure 记录发送失败，连续失败达到阈值时自动停用绑定
+func (s *BindingHealthService) RecordFailure(ctx context.Context, binding *model.ChannelTemplateBinding, errorCode, errorMessage string) {
+	if binding == nil || !binding.AutoDisableOnFail || binding.AutoDisableThreshold <= 0 {
+		return
+	}
+
+	key := buildBindingFailCountKey(binding.ID)
+	count, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to incr binding fail count binding_id=%d: %v", binding.ID, err))
+		return
+	}
+	s.redis.Expire(ctx, key, bindingFailCountTTL)
+
+	if count < int64(binding.AutoDisableThreshold) {
+		return
+	}
+
+	// 条件更新，多实例同时达到阈值时只有一个实例执行停用后续动作
+	disabled, err := s.bindingDAO.DeactivateIfActive(binding.ID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to auto disable binding_id=%d: %v", binding.ID, err))
+		return
+	}
+	if !disabled {
+		return
+	}
+
+	s.channelSelector.InvalidateCacheForBinding(binding.ChannelID)
+	s.channelSelector.ResetWeightsByChannelID(binding.ChannelID)
+
+	s.eventDAO.Create(&model.ChannelBindingEvent{
+		ChannelID:           binding.ChannelID,
+		BindingID:           binding.ID,
+		ProviderID:          binding.ProviderID,
+		EventType:           model.BindingEventAutoDisabled,
+		ConsecutiveFailures: int(count),
+		ErrorCode:           errorCode,
+		ErrorMessage:        errorMessage,
+		Operator:            "system",
+	})
+
+	s.logger.Warn(fmt.Sprintf("binding auto disabled binding_id=%d channel_id=%d consecutive_failures=%d threshold=%d",
+		binding.ID, binding.ChannelID, count, binding.AutoDisableThreshold))
+
+	s.sendDisabledAlert(ctx, binding, count, errorCode, errorMessage)
+}
+
+// Enable 管理员重新启用被自动停用的绑定
+func (s *BindingHealthService) Enable(ctx context.Context, bindingID uint, operator string) error {
+	binding, err := s.bindingDAO.GetByID(bindingID)
+	if err != nil {
+		return fmt.Errorf("binding not found: %w", err)
+	}
+
+	if err := s.bindingDAO.UpdateIsActive(bindingID, 1); err != nil {
+		return err
+	}
+
+	// 清零失败计数并重置熔断器，避免启用后立即被再次停用
+	s.redis.Del(ctx, buildBindingFailCountKey(bindingID))
+	if err := s.channelSelector.ResetBreaker(ctx, bindingID); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to reset circuit breaker binding_id=%d: %v", bindingID, err))
+	}
+
+	s.channelSelector.InvalidateCacheForBinding(binding.ChannelID)
+	s.channelSelector.ResetWeightsByChannelID(binding.ChannelID)
+
+	s.eventDAO.Create(&model.ChannelBindingEvent{
+		ChannelID:  binding.ChannelID,
+		BindingID:  binding.ID,
+		ProviderID: binding.ProviderID,
+		EventType:  model.BindingEventReEnabled,
+		Operator:   operator,
+	})
+
+	s.logger.Info(fmt.Sprintf("binding re-enabled binding_id=%d operator=%s", bindingID, operator))
+	return nil
+}
+
+// sendDisabledAlert 发送绑定自动停用告警
+func (s *BindingHealthService) sendDisabledAlert(ctx context.Context, binding *model.ChannelTemplateBinding, count int64, errorCode, errorMessage string) {
+	if s.defaultWebhookURL == "" {
+		s.logger.Warn(fmt.Sprintf("no alert webhook configured, skipping binding disabled alert binding_id=%d", binding.ID))
+		return
+	}
+
+	payload := map[string]interface{}{
+		"alert_type":           "channel_binding_auto_disabled",
+		"alert_level":          "critical",
+		"channel_id":           binding.ChannelID,
+		"binding_id":           binding.ID,
+		"provider_id":          binding.ProviderID,
+		"consecutive_failures": count,
+		"threshold":            binding.AutoDisableThreshold,
+		"error_code":           errorCode,
+		"error_msg":            errorMessage,
+		"timestamp":            time.Now().Unix(),
+	}
+
+	if err := postAlertWebhook(ctx, s.httpClient, s.defaultWebhookURL, payload); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to send binding disabled alert binding_id=%d: %v", binding.ID, err))
+	}
+}
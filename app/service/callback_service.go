@@ -24,6 +24,7 @@ type CallbackService struct {
 	webhookService *WebhookService
 	ruleEngine     *RuleEngineService
 	actionExecutor *ActionExecutor
+	batchProgress  *BatchProgressService
 }
 
 // NewCallbackService 创建回调服务
@@ -38,6 +39,7 @@ func NewCallbackService() *CallbackService {
 		webhookService: NewWebhookService(),
 		ruleEngine:     GetRuleEngineService(),
 		actionExecutor: NewActionExecutor(),
+		batchProgress:  NewBatchProgressService(),
 	}
 }
 
@@ -189,17 +191,76 @@ func (s *CallbackService) processCallbackResult(ctx context.Context, providerCod
 		s.logger.Info(fmt.Sprintf("task status updated task_id=%s old_status=%s new_status=%s",
 			task.TaskID, oldStatus, task.Status))
 
-		// 4. 触发业务方 Webhook 通知
-		go func() {
-			if err := s.webhookService.NotifyStatusChange(context.Background(), task, result); err != nil {
+		s.batchProgress.Transition(task.BatchID, oldStatus, task.Status)
+
+		// 4. 触发业务方 Webhook 通知（失败结果由规则动作执行器发出 task.failed 事件）
+		if task.Status == constants.TaskStatusSuccess {
+			extra := map[string]interface{}{
+				"provider_id": result.ProviderID,
+				"report_time": result.ReportTime.Format(time.RFC3339),
+			}
+			if err := s.webhookService.NotifyTaskEvent(ctx, constants.WebhookEventTaskDelivered, task, "", "", extra); err != nil {
 				s.logger.Error(fmt.Sprintf("failed to notify webhook for task_id=%s: %v", task.TaskID, err))
 			}
-		}()
+		}
 	}
 
 	return nil
 }
 
+// HandleStatusResults 处理主动查询/拉取到的状态结果（状态对账）
+// 与服务商回调走同一处理流程（回调日志、规则引擎、Webhook），返回实际处理的结果数量
+func (s *CallbackService) HandleStatusResults(ctx context.Context, providerCode string, results []*sender.StatusQueryResult) int {
+	processed := 0
+	for _, result := range results {
+		// 等待回执中，尚无最终状态
+		if result.ProviderMsgID == "" || result.Status == "pending" {
+			continue
+		}
+
+		// 已收到最终回执的任务跳过，避免与回调重复触发规则和通知
+		if pushLog, err := s.logDao.GetByProviderMsgID(result.ProviderMsgID); err == nil {
+			if task, err := s.taskDao.GetByTaskID(pushLog.TaskID); err == nil && isFinalCallbackStatus(task.CallbackStatus) {
+				continue
+			}
+		}
+
+		rawData, _ := json.Marshal(map[string]interface{}{
+			"source":          "reconcile",
+			"provider_msg_id": result.ProviderMsgID,
+			"phone_number":    result.PhoneNumber,
+			"status":          result.Status,
+			"error_code":      result.ErrorCode,
+			"error_message":   result.ErrorMessage,
+			"report_time":     result.ReportTime,
+		})
+
+		callbackResult := &sender.CallbackResult{
+			ProviderID:   result.ProviderMsgID,
+			Status:       result.Status,
+			ErrorCode:    result.ErrorCode,
+			ErrorMessage: result.ErrorMessage,
+			ReportTime:   result.ReportTime,
+		}
+		if err := s.processCallbackResult(ctx, providerCode, callbackResult, string(rawData)); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to process reconciled status provider_id=%s: %v", result.ProviderMsgID, err))
+			continue
+		}
+		processed++
+	}
+
+	return processed
+}
+
+// isFinalCallbackStatus 是否为最终回执状态
+func isFinalCallbackStatus(status string) bool {
+	switch status {
+	case constants.CallbackStatusDelivered, constants.CallbackStatusFailed, constants.CallbackStatusRejected:
+		return true
+	}
+	return false
+}
+
 // GetSupportedProviders 获取支持回调的服务商列表
 func (s *CallbackService) GetSupportedProviders() []string {
 	handlers := s.senderFactory.GetAllCallbackHandlers()
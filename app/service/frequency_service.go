@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/model"
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/google/uuid"
+	"github.com/muleiwu/gsr"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// frequencyKeyPrefix 接收者发送记录 key 前缀
+// 应用维度为 push:frequency:app:{app_id}:{receiver}，通道维度为 push:frequency:channel:{channel_id}:{receiver}
+const frequencyKeyPrefix = "push:frequency:"
+
+// frequencyLimitsCacheTTL 应用频率限制规则缓存时间
+const frequencyLimitsCacheTTL = time.Minute
+
+// ErrFrequencyLimitExceeded 接收者超出频率限制
+var ErrFrequencyLimitExceeded = errors.New("receiver frequency limit exceeded")
+
+// frequencyScript 滑动窗口频率检查
+// KEYS 为各作用域的发送记录（有序集合，score 为发送时间毫秒）
+// ARGV[1] 当前时间毫秒，ARGV[2] 本次发送标识，随后每个作用域依次为规则数 n 和 n 组 (窗口毫秒, 条数上限)
+// 所有作用域的规则都满足时才记录本次发送并返回 0，否则返回被拒绝的作用域序号（从 1 开始）
+var frequencyScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local member = ARGV[2]
+local idx = 3
+local windows = {}
+
+for i, key in ipairs(KEYS) do
+	local n = tonumber(ARGV[idx])
+	idx = idx + 1
+	local maxWindow = 0
+	for j = 1, n do
+		local window = tonumber(ARGV[idx])
+		local limit = tonumber(ARGV[idx + 1])
+		idx = idx + 2
+		if window > maxWindow then
+			maxWindow = window
+		end
+		if redis.call('ZCOUNT', key, '(' .. (now - window), '+inf') >= limit then
+			return i
+		end
+	end
+	windows[i] = maxWindow
+end
+
+for i, key in ipairs(KEYS) do
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', now - windows[i])
+	redis.call('ZADD', key, now, member)
+	redis.call('PEXPIRE', key, windows[i])
+end
+
+return 0
+`)
+
+// frequencyScope 频率限制作用域
+type frequencyScope struct {
+	keyPrefix string
+	limits    []model.FrequencyLimit
+}
+
+// FrequencyService 接收者频率限制服务
+// 按应用和通道配置的规则限制同一接收者的发送频率，白名单中的接收者不受限制
+type FrequencyService struct {
+	logger       gsr.Logger
+	redis        *redis.Client
+	cache        gsr.Cacher
+	db           *gorm.DB
+	appDao       *dao.ApplicationDAO
+	allowlistDao *dao.FrequencyAllowlistDAO
+}
+
+// NewFrequencyService 创建频率限制服务
+func NewFrequencyService() *FrequencyService {
+	h := internalHelper.GetHelper()
+	return &FrequencyService{
+		logger:       h.GetLogger(),
+		redis:        h.GetRedis(),
+		cache:        h.GetCache(),
+		db:           h.GetDatabase(),
+		appDao:       dao.NewApplicationDAO(),
+		allowlistDao: dao.NewFrequencyAllowlistDAO(),
+	}
+}
+
+// Check 检查接收者频率限制并记录本次发送，返回允许发送和被拒绝的接收者
+// 同一请求中重复的接收者按顺序依次计数；Redis 异常时全部放行，不影响正常发送
+func (s *FrequencyService) Check(ctx context.Context, appID string, channel *model.Channel, receivers []string) ([]string, []string) {
+	scopes := s.getScopes(ctx, appID, channel)
+	if len(scopes) == 0 || len(receivers) == 0 {
+		return receivers, nil
+	}
+
+	exempt := s.getAllowlisted(appID, receivers)
+
+	now := time.Now().UnixMilli()
+	pipe := s.redis.Pipeline()
+	cmds := make([]*redis.Cmd, len(receivers))
+	for i, receiver := range receivers {
+		if exempt[receiver] {
+			continue
+		}
+
+		keys := make([]string, 0, len(scopes))
+		args := []interface{}{now, uuid.New().String()}
+		for _, scope := range scopes {
+			keys = append(keys, scope.keyPrefix+receiver)
+			args = append(args, len(scope.limits))
+			for _, limit := range scope.limits {
+				args = append(args, limit.Window*1000, limit.Limit)
+			}
+		}
+		cmds[i] = frequencyScript.Eval(ctx, pipe, keys, args...)
+	}
+
+	if pipe.Len() > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			s.logger.Warn(fmt.Sprintf("failed to check frequency limits app_id=%s, skip: %v", appID, err))
+			return receivers, nil
+		}
+	}
+
+	allowed := make([]string, 0, len(receivers))
+	var rejected []string
+	for i, receiver := range receivers {
+		if cmds[i] != nil {
+			if scope, _ := cmds[i].Int(); scope > 0 {
+				rejected = append(rejected, receiver)
+				continue
+			}
+		}
+		allowed = append(allowed, receiver)
+	}
+
+	if len(rejected) > 0 {
+		s.logger.Info(fmt.Sprintf("receivers throttled by frequency limits app_id=%s channel_id=%d count=%d", appID, channel.ID, len(rejected)))
+		s.recordThrottled(appID, len(rejected))
+	}
+
+	return allowed, rejected
+}
+
+// Invalidate 清除应用频率限制规则缓存（修改规则后调用）
+func (s *FrequencyService) Invalidate(ctx context.Context, appID string) {
+	if err := s.cache.Del(ctx, buildFrequencyLimitsCacheKey(appID)); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to invalidate frequency limits app_id=%s: %v", appID, err))
+	}
+}
+
+// getScopes 获取应用和通道的频率限制作用域，规则解析失败时忽略该作用域
+func (s *FrequencyService) getScopes(ctx context.Context, appID string, channel *model.Channel) []frequencyScope {
+	var scopes []frequencyScope
+
+	var raw string
+	err := s.cache.GetSet(ctx, buildFrequencyLimitsCacheKey(appID), frequencyLimitsCacheTTL, &raw, func(key string, obj any) error {
+		app, err := s.appDao.GetByAppID(appID)
+		if err != nil {
+			return err
+		}
+		*obj.(*string) = app.FrequencyLimits
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to get frequency limits app_id=%s: %v", appID, err))
+	} else if limits, err := model.ParseFrequencyLimits(raw); err != nil {
+		s.logger.Warn(fmt.Sprintf("invalid frequency limits app_id=%s: %v", appID, err))
+	} else if len(limits) > 0 {
+		scopes = append(scopes, frequencyScope{
+			keyPrefix: fmt.Sprintf("%sapp:%s:", frequencyKeyPrefix, appID),
+			limits:    limits,
+		})
+	}
+
+	if limits, err := channel.GetFrequencyLimits(); err != nil {
+		s.logger.Warn(fmt.Sprintf("invalid frequency limits channel_id=%d: %v", channel.ID, err))
+	} else if len(limits) > 0 {
+		scopes = append(scopes, frequencyScope{
+			keyPrefix: fmt.Sprintf("%schannel:%d:", frequencyKeyPrefix, channel.ID),
+			limits:    limits,
+		})
+	}
+
+	return scopes
+}
+
+// getAllowlisted 获取位于应用白名单中的接收者，查询失败时按不在白名单处理
+func (s *FrequencyService) getAllowlisted(appID string, receivers []string) map[string]bool {
+	allowed, err := s.allowlistDao.GetAllowedReceivers(appID, receivers)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to get frequency allowlist app_id=%s: %v", appID, err))
+		return nil
+	}
+
+	exempt := make(map[string]bool, len(allowed))
+	for _, receiver := range allowed {
+		exempt[receiver] = true
+	}
+	return exempt
+}
+
+// recordThrottled 累加应用当日的频率限制拒绝数
+func (s *FrequencyService) recordThrottled(appID string, count int) {
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	stat := model.AppQuotaStat{
+		AppID:          appID,
+		StatDate:       today,
+		ThrottledCount: count,
+	}
+
+	err := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "app_id"}, {Name: "stat_date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"throttled_count": gorm.Expr("throttled_count + ?", count),
+		}),
+	}).Create(&stat).Error
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to record throttled count app_id=%s: %v", appID, err))
+	}
+}
+
+// buildFrequencyLimitsCacheKey 构建应用频率限制规则缓存 key
+func buildFrequencyLimitsCacheKey(appID string) string {
+	return fmt.Sprintf("app:frequency_limits:%s", appID)
+}
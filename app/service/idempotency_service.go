@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+	"github.com/redis/go-redis/v9"
+)
+
+// idempotencyKeyPrefix 幂等记录 key 前缀，完整 key 为 push:idempotency:{app_id}:{idempotency_key}
+const idempotencyKeyPrefix = "push:idempotency:"
+
+// MaxIdempotencyKeyLength 幂等键最大长度
+const MaxIdempotencyKeyLength = 64
+
+// 幂等记录状态
+const (
+	idempotencyStatusProcessing = "processing" // 首次请求处理中
+	idempotencyStatusDone       = "done"       // 首次请求已受理，保存了响应
+)
+
+var (
+	// ErrIdempotencyKeyConflict 同一幂等键携带了不同的请求内容
+	ErrIdempotencyKeyConflict = errors.New("idempotency key already used with a different request")
+	// ErrIdempotencyKeyInProgress 同一幂等键的首次请求仍在处理中
+	ErrIdempotencyKeyInProgress = errors.New("request with the same idempotency key is in progress")
+)
+
+// localIdempotencyRecords 进程内幂等记录（cache.driver=memory 或 Redis 不可用时使用）
+var localIdempotencyRecords = &idempotencyMemoryStore{items: make(map[string]*idempotencyMemoryItem)}
+
+// idempotencyRecord 幂等记录
+type idempotencyRecord struct {
+	Fingerprint string          `json:"fingerprint"` // 请求内容摘要
+	Status      string          `json:"status"`
+	Response    json.RawMessage `json:"response,omitempty"` // 首次受理的响应
+}
+
+// IdempotencyService 发送请求幂等服务
+// 在时间窗口内记录应用的 (app_id, 幂等键) 及首次受理结果，重复请求直接返回首次结果，避免重复创建任务
+type IdempotencyService struct {
+	logger gsr.Logger
+	redis  *redis.Client
+	window time.Duration // 受理结果保留时长
+	lease  time.Duration // 处理中占位的租约时长，处理方崩溃后到期自动释放
+}
+
+// NewIdempotencyService 创建幂等服务
+func NewIdempotencyService() *IdempotencyService {
+	h := internalHelper.GetHelper()
+
+	var redisClient *redis.Client
+	if h.GetConfig().GetString("cache.driver", "redis") == "redis" {
+		redisClient = h.GetRedis()
+	}
+
+	return &IdempotencyService{
+		logger: h.GetLogger(),
+		redis:  redisClient,
+		window: time.Duration(h.GetEnv().GetInt("message.idempotency_window", 86400)) * time.Second,
+		lease:  time.Duration(h.GetEnv().GetInt("message.idempotency_lease", 60)) * time.Second,
+	}
+}
+
+// Fingerprint 计算请求内容摘要，scope 区分不同接口
+func (s *IdempotencyService) Fingerprint(scope string, request any) string {
+	data, _ := json.Marshal(request)
+	sum := sha256.Sum256(append([]byte(scope+":"), data...))
+	return hex.EncodeToString(sum[:])
+}
+
+// Begin 登记幂等键
+// 返回 true 表示重复请求，首次受理的响应已写入 response；返回 false 表示首次请求，调用方处理完成后需调用 Complete 或 Release
+func (s *IdempotencyService) Begin(ctx context.Context, appID, key, fingerprint string, response any) (bool, error) {
+	if len(key) > MaxIdempotencyKeyLength {
+		return false, fmt.Errorf("idempotency key must not exceed %d characters", MaxIdempotencyKeyLength)
+	}
+
+	cacheKey := buildIdempotencyCacheKey(appID, key)
+	data, _ := json.Marshal(&idempotencyRecord{
+		Fingerprint: fingerprint,
+		Status:      idempotencyStatusProcessing,
+	})
+
+	ok, existing, err := s.setNX(ctx, cacheKey, data)
+	if err != nil {
+		return false, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+	if ok {
+		return false, nil
+	}
+
+	var record idempotencyRecord
+	if err := json.Unmarshal(existing, &record); err != nil {
+		return false, fmt.Errorf("failed to parse idempotency record: %w", err)
+	}
+	if record.Fingerprint != fingerprint {
+		return false, ErrIdempotencyKeyConflict
+	}
+	if record.Status != idempotencyStatusDone {
+		return false, ErrIdempotencyKeyInProgress
+	}
+	if err := json.Unmarshal(record.Response, response); err != nil {
+		return false, fmt.Errorf("failed to parse idempotency response: %w", err)
+	}
+	return true, nil
+}
+
+// Complete 保存首次受理的响应并将记录有效期延长至整个窗口期，窗口期内的重复请求直接返回该响应
+func (s *IdempotencyService) Complete(ctx context.Context, appID, key, fingerprint string, response any) {
+	responseData, err := json.Marshal(response)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to marshal idempotency response app_id=%s key=%s: %v", appID, key, err))
+		return
+	}
+	data, _ := json.Marshal(&idempotencyRecord{
+		Fingerprint: fingerprint,
+		Status:      idempotencyStatusDone,
+		Response:    responseData,
+	})
+
+	if err := s.set(ctx, buildIdempotencyCacheKey(appID, key), data); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to save idempotency response app_id=%s key=%s: %v", appID, key, err))
+	}
+}
+
+// Release 释放幂等键（首次请求处理失败时调用，允许调用方重试）
+func (s *IdempotencyService) Release(ctx context.Context, appID, key string) {
+	if err := s.del(ctx, buildIdempotencyCacheKey(appID, key)); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to release idempotency key app_id=%s key=%s: %v", appID, key, err))
+	}
+}
+
+// setNX 记录不存在时写入处理中占位（有效期为租约时长）；已存在时返回现有记录
+func (s *IdempotencyService) setNX(ctx context.Context, key string, data []byte) (bool, []byte, error) {
+	if s.redis == nil {
+		ok, existing := localIdempotencyRecords.setNX(key, data, s.lease)
+		return ok, existing, nil
+	}
+
+	for range 2 {
+		ok, err := s.redis.SetNX(ctx, key, data, s.lease).Result()
+		if err != nil {
+			return false, nil, err
+		}
+		if ok {
+			return true, nil, nil
+		}
+
+		existing, err := s.redis.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			// 记录恰好过期或被释放，重新登记
+			continue
+		}
+		if err != nil {
+			return false, nil, err
+		}
+		return false, existing, nil
+	}
+	return false, nil, fmt.Errorf("idempotency key %s is changing concurrently", key)
+}
+
+// set 覆盖写入记录
+func (s *IdempotencyService) set(ctx context.Context, key string, data []byte) error {
+	if s.redis == nil {
+		localIdempotencyRecords.set(key, data, s.window)
+		return nil
+	}
+	return s.redis.Set(ctx, key, data, s.window).Err()
+}
+
+// del 删除记录
+func (s *IdempotencyService) del(ctx context.Context, key string) error {
+	if s.redis == nil {
+		localIdempotencyRecords.del(key)
+		return nil
+	}
+	return s.redis.Del(ctx, key).Err()
+}
+
+// buildIdempotencyCacheKey 构建幂等记录 key
+func buildIdempotencyCacheKey(appID, key string) string {
+	return idempotencyKeyPrefix + appID + ":" + key
+}
+
+// idempotencyMemoryItem 进程内幂等记录项
+type idempotencyMemoryItem struct {
+	data     []byte
+	expireAt time.Time
+}
+
+// idempotencyMemoryStore 进程内幂等记录存储
+type idempotencyMemoryStore struct {
+	mu        sync.Mutex
+	items     map[string]*idempotencyMemoryItem
+	nextSweep time.Time
+}
+
+// setNX 记录不存在或已过期时写入并返回 true，否则返回现有记录
+func (m *idempotencyMemoryStore) setNX(key string, data []byte, ttl time.Duration) (bool, []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	// 定期清理过期记录，避免内存持续增长
+	if now.After(m.nextSweep) {
+		for k, item := range m.items {
+			if now.After(item.expireAt) {
+				delete(m.items, k)
+			}
+		}
+		m.nextSweep = now.Add(time.Minute)
+	}
+
+	if item, exists := m.items[key]; exists && now.Before(item.expireAt) {
+		return false, item.data
+	}
+
+	m.items[key] = &idempotencyMemoryItem{data: data, expireAt: now.Add(ttl)}
+	return true, nil
+}
+
+// set 覆盖写入记录
+func (m *idempotencyMemoryStore) set(key string, data []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[key] = &idempotencyMemoryItem{data: data, expireAt: time.Now().Add(ttl)}
+}
+
+// del 删除记录
+func (m *idempotencyMemoryStore) del(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, key)
+}
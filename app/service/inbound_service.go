@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/app/sender"
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// inboundMatchCandidates 关联上行回复时最多比较的候选任务数量
+const inboundMatchCandidates = 20
+
+// defaultUnsubscribeKeywords 默认退订关键词
+const defaultUnsubscribeKeywords = "TD,STOP,UNSUBSCRIBE,退订"
+
+// InboundService 上行消息服务
+// 保存用户回复并关联到原始任务，推送 inbound 事件；回复退订关键词时自动加入屏蔽名单
+type InboundService struct {
+	logger           gsr.Logger
+	inboundDao       *dao.InboundMessageDAO
+	taskDao          *dao.PushTaskDAO
+	bindingDao       *dao.ChannelTemplateBindingDAO
+	signatureDao     *dao.ChannelSignatureMappingDAO
+	suppressionDao   *dao.SuppressionDAO
+	senderFactory    *sender.Factory
+	webhookService   *WebhookService
+	matchWindow      time.Duration       // 只关联该时长内发送的任务
+	unsubscribeWords map[string]struct{} // 退订关键词（大写）
+	unsubscribeScope string              // 退订屏蔽范围：app 或 global
+}
+
+// NewInboundService 创建上行消息服务
+func NewInboundService() *InboundService {
+	h := internalHelper.GetHelper()
+	env := h.GetEnv()
+
+	keywords := make(map[string]struct{})
+	for _, keyword := range strings.Split(env.GetString("inbound.unsubscribe_keywords", defaultUnsubscribeKeywords), ",") {
+		if keyword = normalizeInboundContent(keyword); keyword != "" {
+			keywords[keyword] = struct{}{}
+		}
+	}
+
+	scope := env.GetString("inbound.unsubscribe_scope", model.SuppressionScopeApp)
+	if scope != model.SuppressionScopeGlobal {
+		scope = model.SuppressionScopeApp
+	}
+
+	return &InboundService{
+		logger:           h.GetLogger(),
+		inboundDao:       dao.NewInboundMessageDAO(),
+		taskDao:          dao.NewPushTaskDAO(),
+		bindingDao:       dao.NewChannelTemplateBindingDAO(),
+		signatureDao:     dao.NewChannelSignatureMappingDAO(h.GetDatabase()),
+		suppressionDao:   dao.NewSuppressionDAO(),
+		senderFactory:    sender.NewFactory(),
+		webhookService:   NewWebhookService(),
+		matchWindow:      time.Duration(env.GetInt("inbound.match_window", 259200)) * time.Second,
+		unsubscribeWords: keywords,
+		unsubscribeScope: scope,
+	}
+}
+
+// HandleInbound 处理服务商推送的上行消息
+// 返回值：供应商期望的响应信息（实体，始终返回）
+func (s *InboundService) HandleInbound(ctx context.Context, providerCode string, req *sender.CallbackRequest) sender.CallbackResponse {
+	defaultResp := sender.CallbackResponse{
+		StatusCode: 200,
+		Body:       `{"code":0,"message":"ok"}`,
+	}
+
+	handler, err := s.senderFactory.GetInboundHandler(providerCode)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to get inbound handler for provider=%s: %v", providerCode, err))
+		return defaultResp
+	}
+
+	resp, messages, err := handler.HandleInbound(ctx, req)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to handle inbound for provider=%s: %v", providerCode, err))
+		return resp
+	}
+
+	rawData := buildRawDataJSON(req)
+	for _, message := range messages {
+		if err := s.processInboundMessage(ctx, req.ProviderAccount, message, rawData); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to process inbound message phone=%s: %v", message.Phone, err))
+		}
+	}
+
+	return resp
+}
+
+// processInboundMessage 保存单条上行消息，处理退订并推送 inbound 事件
+func (s *InboundService) processInboundMessage(ctx context.Context, account *model.ProviderAccount, message *sender.InboundMessage, rawData string) error {
+	receivedAt := message.ReceiveTime
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	msg := &model.InboundMessage{
+		ProviderAccountID: account.ID,
+		ProviderCode:      account.ProviderCode,
+		Phone:             message.Phone,
+		Content:           message.Content,
+		SignName:          message.SignName,
+		ExtendCode:        message.ExtendCode,
+		DestCode:          message.DestCode,
+		Unsubscribe:       s.isUnsubscribe(message.Content),
+		RawData:           rawData,
+		ReceivedAt:        receivedAt,
+	}
+
+	if message.SequenceID != "" {
+		sequenceID := message.SequenceID
+		msg.SequenceID = &sequenceID
+	}
+
+	task := s.matchTask(account, message)
+	if task != nil {
+		msg.AppID = task.AppID
+		msg.TaskID = task.TaskID
+	}
+
+	// 服务商超时或收到非 2xx 响应时会重复推送，已保存的消息不再处理和通知
+	created, err := s.inboundDao.CreateIfAbsent(msg)
+	if err != nil {
+		return fmt.Errorf("save inbound message: %w", err)
+	}
+	if !created {
+		s.logger.Info(fmt.Sprintf("duplicate inbound message skipped phone=%s sequence_id=%s provider_account_id=%d", msg.Phone, message.SequenceID, account.ID))
+		return nil
+	}
+
+	if msg.Unsubscribe {
+		s.unsubscribe(msg, task)
+	}
+
+	// 未关联到任务时无法确定应用，不推送事件
+	if msg.AppID == "" {
+		s.logger.Warn(fmt.Sprintf("inbound message not matched to any task phone=%s provider_account_id=%d", msg.Phone, account.ID))
+		return nil
+	}
+
+	if err := s.webhookService.NotifyInbound(ctx, msg); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to notify inbound webhook app_id=%s: %v", msg.AppID, err))
+	}
+	return nil
+}
+
+// matchTask 查找上行回复对应的原始任务
+// 候选任务为匹配时间窗口内通过该服务商账号发送给该号码的任务，
+// 扩展码匹配优先于签名匹配，同等匹配程度时取最近发送的任务
+func (s *InboundService) matchTask(account *model.ProviderAccount, message *sender.InboundMessage) *model.PushTask {
+	tasks, err := s.taskDao.GetRecentByReceiversAndAccount(inboundPhoneVariants(message.Phone), account.ID, time.Now().Add(-s.matchWindow), inboundMatchCandidates)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to query tasks for inbound phone=%s: %v", message.Phone, err))
+		return nil
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	extendChannels := make(map[uint]bool)
+	if message.ExtendCode != "" {
+		channelIDs, err := s.bindingDao.GetChannelIDsByExtendCode(account.ID, message.ExtendCode)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("failed to query bindings by extend_code=%s: %v", message.ExtendCode, err))
+		}
+		for _, channelID := range channelIDs {
+			extendChannels[channelID] = true
+		}
+	}
+
+	// 通道ID + 用户自定义签名名称
+	signatures := make(map[string]bool)
+	if message.SignName != "" {
+		mappings, err := s.signatureDao.GetByProviderIDAndSignatureCode(account.ID, message.SignName)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("failed to query signatures by sign_name=%s: %v", message.SignName, err))
+		}
+		for _, mapping := range mappings {
+			signatures[fmt.Sprintf("%d:%s", mapping.ChannelID, mapping.SignatureName)] = true
+		}
+	}
+
+	var matched *model.PushTask
+	bestScore := -1
+	for _, task := range tasks {
+		score := 0
+		if extendChannels[task.ChannelID] {
+			score += 2
+		}
+		if message.SignName != "" && (task.Signature == message.SignName || signatures[fmt.Sprintf("%d:%s", task.ChannelID, task.Signature)]) {
+			score++
+		}
+		// 任务按发送时间倒序，仅在匹配程度更高时替换
+		if score > bestScore {
+			matched = task
+			bestScore = score
+		}
+	}
+	return matched
+}
+
+// isUnsubscribe 回复内容是否为退订关键词（忽略大小写、空白和标点）
+func (s *InboundService) isUnsubscribe(content string) bool {
+	_, ok := s.unsubscribeWords[normalizeInboundContent(content)]
+	return ok
+}
+
+// unsubscribe 将回复退订的号码加入屏蔽名单
+// 按应用范围屏蔽时需要关联到原始任务，否则无法确定应用
+func (s *InboundService) unsubscribe(msg *model.InboundMessage, task *model.PushTask) {
+	entry := &model.Suppression{
+		Receiver: msg.Phone,
+		Type:     model.SuppressionTypeUnsubscribe,
+		Scope:    s.unsubscribeScope,
+		Reason:   truncateRunes("用户回复退订："+msg.Content, 255),
+		Source:   model.SuppressionSourceInbound,
+	}
+	if s.unsubscribeScope == model.SuppressionScopeApp {
+		if msg.AppID == "" {
+			s.logger.Warn(fmt.Sprintf("unsubscribe reply not matched to any app, skip suppression phone=%s", msg.Phone))
+			return
+		}
+		entry.AppID = msg.AppID
+	}
+
+	entries := []*model.Suppression{entry}
+	// 任务接收者可能带国家码，与发送时的号码格式保持一致
+	if task != nil && task.Receiver != msg.Phone {
+		alias := *entry
+		alias.Receiver = task.Receiver
+		entries = append(entries, &alias)
+	}
+
+	if err := s.suppressionDao.Upsert(entries); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to add unsubscribe suppression phone=%s: %v", msg.Phone, err))
+		return
+	}
+	s.logger.Info(fmt.Sprintf("phone=%s unsubscribed by reply, scope=%s app_id=%s", msg.Phone, entry.Scope, entry.AppID))
+}
+
+// normalizeInboundContent 去除首尾空白和标点并转为大写，用于匹配关键词
+func normalizeInboundContent(content string) string {
+	trimmed := strings.TrimFunc(content, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	return strings.ToUpper(trimmed)
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
+
+// inboundPhoneVariants 上行号码可能的接收者格式（任务中的号码可能带或不带 +86/86 国家码）
+func inboundPhoneVariants(phone string) []string {
+	local := strings.TrimPrefix(phone, "+")
+	if strings.HasPrefix(local, "86") && len(local) == 13 {
+		local = local[2:]
+	}
+	if strings.HasPrefix(phone, "+") && local == strings.TrimPrefix(phone, "+") {
+		// 非中国大陆号码，保持原样
+		return []string{phone, local}
+	}
+	return []string{local, "+86" + local, "86" + local}
+}
@@ -15,6 +15,7 @@ import (
 	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
 	"github.com/google/uuid"
 	"github.com/muleiwu/gsr"
+	"gorm.io/gorm"
 )
 
 // MessageService 消息服务
@@ -23,9 +24,16 @@ type MessageService struct {
 	producer           *queue.Producer
 	selector           *selector.ChannelSelector
 	taskDao            *dao.PushTaskDAO
+	batchDao           *dao.PushBatchTaskDAO
 	appDao             *dao.ApplicationDAO
 	messageTemplateDao *dao.MessageTemplateDAO
 	templateHelper     *helper.TemplateHelper
+	webhookService     *WebhookService
+	sandbox            *SandboxService
+	idempotency        *IdempotencyService
+	frequency          *FrequencyService
+	suppression        *SuppressionService
+	batchProgress      *BatchProgressService
 }
 
 // NewMessageService 创建消息服务
@@ -36,14 +44,47 @@ func NewMessageService() *MessageService {
 		producer:           queue.NewProducer(h.GetRedis()),
 		selector:           selector.NewChannelSelector(),
 		taskDao:            dao.NewPushTaskDAO(),
+		batchDao:           dao.NewPushBatchTaskDAO(),
 		appDao:             dao.NewApplicationDAO(),
 		messageTemplateDao: dao.NewMessageTemplateDAO(),
 		templateHelper:     helper.NewTemplateHelper(),
+		webhookService:     NewWebhookService(),
+		sandbox:            NewSandboxService(),
+		idempotency:        NewIdempotencyService(),
+		frequency:          NewFrequencyService(),
+		suppression:        NewSuppressionService(),
+		batchProgress:      NewBatchProgressService(),
 	}
 }
 
 // Send 发送消息
+// 携带幂等键时，窗口期内的重复请求直接返回首次受理结果
 func (s *MessageService) Send(ctx context.Context, req *dto.SendRequest) (*dto.SendResponse, error) {
+	if req.ClientMsgID == "" {
+		return s.send(ctx, req)
+	}
+
+	fingerprint := s.idempotency.Fingerprint("send", req)
+	var replayed dto.SendResponse
+	ok, err := s.idempotency.Begin(ctx, req.AppID, req.ClientMsgID, fingerprint, &replayed)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return &replayed, nil
+	}
+
+	resp, err := s.send(ctx, req)
+	if err != nil {
+		s.idempotency.Release(ctx, req.AppID, req.ClientMsgID)
+		return nil, err
+	}
+	s.idempotency.Complete(ctx, req.AppID, req.ClientMsgID, fingerprint, resp)
+	return resp, nil
+}
+
+// send 受理单条消息
+func (s *MessageService) send(ctx context.Context, req *dto.SendRequest) (*dto.SendResponse, error) {
 	// 1. 验证通道（获取 MessageTemplateID 和 Type）
 	var channel model.Channel
 	db := internalHelper.GetHelper().GetDatabase()
@@ -57,16 +98,8 @@ func (s *MessageService) Send(ctx context.Context, req *dto.SendRequest) (*dto.S
 	}
 
 	// 检查通道是否有可用的模板绑定
-	// GetActiveByChannelID 查询条件：is_active=1 AND status=1（只查可用的绑定）
-	channelBindingDao := dao.NewChannelTemplateBindingDAO()
-	bindings, err := channelBindingDao.GetActiveByChannelID(uint(req.ChannelID))
-	if err != nil {
-		s.logger.Error(fmt.Sprintf("failed to check channel bindings channel_id=%d: %v", req.ChannelID, err))
-		return nil, fmt.Errorf("failed to check channel bindings: %w", err)
-	}
-	if len(bindings) == 0 {
-		s.logger.Error(fmt.Sprintf("no active template bindings configured channel_id=%d app_id=%s", req.ChannelID, req.AppID))
-		return nil, fmt.Errorf("no active template bindings configured for channel_id=%d", req.ChannelID)
+	if err := s.checkChannelBindings(ctx, req.ChannelID, req.AppID); err != nil {
+		return nil, err
 	}
 
 	// 2. 验证消息类型
@@ -98,6 +131,16 @@ func (s *MessageService) Send(ctx context.Context, req *dto.SendRequest) (*dto.S
 		return nil, fmt.Errorf("failed to render template: %w", err)
 	}
 
+	// 屏蔽名单检查：命中时仍创建任务（状态为 suppressed）以便追踪，但不计入频率限制也不进入发送队列
+	suppressed := s.suppression.Match(req.AppID, req.ChannelID, []string{req.Receiver})
+
+	// 接收者频率限制（创建任务前检查并计数）
+	if len(suppressed) == 0 {
+		if allowed, _ := s.frequency.Check(ctx, req.AppID, &channel, []string{req.Receiver}); len(allowed) == 0 {
+			return nil, ErrFrequencyLimitExceeded
+		}
+	}
+
 	// 4. 创建任务
 	taskID := uuid.New().String()
 	templateParamsJSON, _ := s.templateHelper.RenderJSON(req.TemplateParams)
@@ -111,6 +154,7 @@ func (s *MessageService) Send(ctx context.Context, req *dto.SendRequest) (*dto.S
 		TemplateCode:   "", // 将由 worker 更新为实际使用的供应商模板代码
 		TemplateParams: templateParamsJSON,
 		Signature:      req.SignatureName, // 用户自定义签名名称
+		IdempotencyKey: req.ClientMsgID,
 		Status:         constants.TaskStatusPending,
 		RetryCount:     0,
 		MaxRetry:       3,
@@ -118,11 +162,25 @@ func (s *MessageService) Send(ctx context.Context, req *dto.SendRequest) (*dto.S
 		CreatedAt:      time.Now(),
 	}
 
+	if len(suppressed) > 0 {
+		task.Status = constants.TaskStatusSuppressed
+	}
+
 	// 保存任务到数据库
 	if err := s.taskDao.Create(task); err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
+	if len(suppressed) > 0 {
+		s.logger.Info(fmt.Sprintf("receiver suppressed task_id=%s app_id=%s", taskID, req.AppID))
+		s.suppression.Notify(ctx, req.AppID, []*model.PushTask{task}, suppressed)
+		return &dto.SendResponse{
+			TaskID:    taskID,
+			Status:    constants.TaskStatusSuppressed,
+			CreatedAt: task.CreatedAt,
+		}, nil
+	}
+
 	// 5. 推送到队列
 	if err := s.producer.Push(ctx, task); err != nil {
 		// 更新任务状态为失败
@@ -131,6 +189,8 @@ func (s *MessageService) Send(ctx context.Context, req *dto.SendRequest) (*dto.S
 		return nil, fmt.Errorf("failed to push to queue: %w", err)
 	}
 
+	s.notifyAccepted(ctx, req.AppID, []*model.PushTask{task})
+
 	return &dto.SendResponse{
 		TaskID:    taskID,
 		Status:    constants.TaskStatusPending,
@@ -138,8 +198,55 @@ func (s *MessageService) Send(ctx context.Context, req *dto.SendRequest) (*dto.S
 	}, nil
 }
 
+// checkChannelBindings 检查通道是否有可用的模板绑定
+// 沙箱应用的消息统一由模拟服务商处理，不要求通道已绑定模板
+func (s *MessageService) checkChannelBindings(ctx context.Context, channelID uint, appID string) error {
+	if s.sandbox.IsSandbox(ctx, appID) {
+		return nil
+	}
+
+	// GetActiveByChannelID 查询条件：is_active=1 AND status=1（只查可用的绑定）
+	channelBindingDao := dao.NewChannelTemplateBindingDAO()
+	bindings, err := channelBindingDao.GetActiveByChannelID(channelID)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to check channel bindings channel_id=%d: %v", channelID, err))
+		return fmt.Errorf("failed to check channel bindings: %w", err)
+	}
+	if len(bindings) == 0 {
+		s.logger.Error(fmt.Sprintf("no active template bindings configured channel_id=%d app_id=%s", channelID, appID))
+		return fmt.Errorf("no active template bindings configured for channel_id=%d", channelID)
+	}
+	return nil
+}
+
 // BatchSend 批量发送消息
+// 携带幂等键时，窗口期内的重复请求直接返回首次受理结果
 func (s *MessageService) BatchSend(ctx context.Context, req *dto.BatchSendRequest) (*dto.BatchSendResponse, error) {
+	if req.ClientMsgID == "" {
+		return s.batchSend(ctx, req)
+	}
+
+	fingerprint := s.idempotency.Fingerprint("batch", req)
+	var replayed dto.BatchSendResponse
+	ok, err := s.idempotency.Begin(ctx, req.AppID, req.ClientMsgID, fingerprint, &replayed)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return &replayed, nil
+	}
+
+	resp, err := s.batchSend(ctx, req)
+	if err != nil {
+		s.idempotency.Release(ctx, req.AppID, req.ClientMsgID)
+		return nil, err
+	}
+	s.idempotency.Complete(ctx, req.AppID, req.ClientMsgID, fingerprint, resp)
+	return resp, nil
+}
+
+// batchSend 受理批量消息
+func (s *MessageService) batchSend(ctx context.Context, req *dto.BatchSendRequest) (*dto.BatchSendResponse, error) {
 	// 1. 验证通道（获取 MessageTemplateID 和 Type）
 	var channel model.Channel
 	db := internalHelper.GetHelper().GetDatabase()
@@ -153,16 +260,8 @@ func (s *MessageService) BatchSend(ctx context.Context, req *dto.BatchSendReques
 	}
 
 	// 检查通道是否有可用的模板绑定
-	// GetActiveByChannelID 查询条件：is_active=1 AND status=1（只查可用的绑定）
-	channelBindingDao := dao.NewChannelTemplateBindingDAO()
-	bindings, err := channelBindingDao.GetActiveByChannelID(uint(req.ChannelID))
-	if err != nil {
-		s.logger.Error(fmt.Sprintf("failed to check channel bindings channel_id=%d: %v", req.ChannelID, err))
-		return nil, fmt.Errorf("failed to check channel bindings: %w", err)
-	}
-	if len(bindings) == 0 {
-		s.logger.Error(fmt.Sprintf("no active template bindings configured channel_id=%d app_id=%s", req.ChannelID, req.AppID))
-		return nil, fmt.Errorf("no active template bindings configured for channel_id=%d", req.ChannelID)
+	if err := s.checkChannelBindings(ctx, req.ChannelID, req.AppID); err != nil {
+		return nil, err
 	}
 
 	// 2. 加载系统模板（使用 channel 的 MessageTemplateID）
@@ -186,7 +285,6 @@ func (s *MessageService) BatchSend(ctx context.Context, req *dto.BatchSendReques
 
 	batchID := uuid.New().String()
 	var tasks []*model.PushTask
-	successCount := 0
 
 	// 渲染模板内容（所有接收者共用相同的模板参数）
 	content, err := s.templateHelper.RenderSimple(messageTemplate.Content, req.TemplateParams)
@@ -195,11 +293,48 @@ func (s *MessageService) BatchSend(ctx context.Context, req *dto.BatchSendReques
 	}
 	templateParamsJSON, _ := s.templateHelper.RenderJSON(req.TemplateParams)
 
+	// 屏蔽名单检查：命中的接收者创建 suppressed 状态的任务，不计入频率限制也不进入发送队列
+	suppressed := s.suppression.Match(req.AppID, req.ChannelID, req.Receivers)
+	var receivers, suppressedReceivers []string
 	for _, receiver := range req.Receivers {
+		if _, ok := suppressed[receiver]; ok {
+			suppressedReceivers = append(suppressedReceivers, receiver)
+		} else {
+			receivers = append(receivers, receiver)
+		}
+	}
+
+	// 接收者频率限制（创建任务前检查并计数），超出限制的接收者不创建任务
+	receivers, throttled := s.frequency.Check(ctx, req.AppID, &channel, receivers)
+	if len(receivers) == 0 && len(suppressedReceivers) == 0 {
+		return nil, ErrFrequencyLimitExceeded
+	}
+
+	var suppressedTasks []*model.PushTask
+	for _, receiver := range suppressedReceivers {
+		suppressedTasks = append(suppressedTasks, &model.PushTask{
+			TaskID:         uuid.New().String(),
+			AppID:          req.AppID,
+			BatchID:        batchID,
+			ChannelID:      req.ChannelID,
+			MessageType:    channel.Type,
+			Receiver:       receiver,
+			Content:        content,
+			TemplateParams: templateParamsJSON,
+			Signature:      req.SignatureName,
+			IdempotencyKey: req.ClientMsgID,
+			Status:         constants.TaskStatusSuppressed,
+			MaxRetry:       3,
+			ScheduledAt:    req.ScheduledAt,
+		})
+	}
+
+	for _, receiver := range receivers {
 		taskID := uuid.New().String()
 		task := &model.PushTask{
 			TaskID:         taskID,
 			AppID:          req.AppID,
+			BatchID:        batchID,
 			ChannelID:      req.ChannelID,
 			MessageType:    channel.Type,
 			Receiver:       receiver,
@@ -207,6 +342,7 @@ func (s *MessageService) BatchSend(ctx context.Context, req *dto.BatchSendReques
 			TemplateCode:   "", // 将由 worker 更新为实际使用的供应商模板代码
 			TemplateParams: templateParamsJSON,
 			Signature:      req.SignatureName, // 用户自定义签名名称
+			IdempotencyKey: req.ClientMsgID,
 			Status:         constants.TaskStatusPending,
 			RetryCount:     0,
 			MaxRetry:       3,
@@ -216,26 +352,58 @@ func (s *MessageService) BatchSend(ctx context.Context, req *dto.BatchSendReques
 		tasks = append(tasks, task)
 	}
 
-	// 批量保存任务
-	for _, task := range tasks {
-		if err := s.taskDao.Create(task); err != nil {
-			s.logger.Error(fmt.Sprintf("failed to create task id=%s: %v", task.TaskID, err))
-			continue
+	batch := &model.PushBatchTask{
+		BatchID:      batchID,
+		AppID:        req.AppID,
+		TotalCount:   len(tasks) + len(suppressedTasks),
+		FailedCount:  len(suppressedTasks),
+		PendingCount: len(tasks),
+		Status:       constants.BatchStatusProcessing,
+	}
+
+	// 批次与任务在同一事务中保存，避免出现计数与任务不一致的批次
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(batch).Error; err != nil {
+			return fmt.Errorf("failed to create batch: %w", err)
 		}
-		successCount++
+		if err := tx.CreateInBatches(tasks, 500).Error; err != nil {
+			return fmt.Errorf("failed to create tasks: %w", err)
+		}
+		if err := tx.CreateInBatches(suppressedTasks, 500).Error; err != nil {
+			return fmt.Errorf("failed to create suppressed tasks: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to save batch batch_id=%s: %v", batchID, err))
+		return nil, err
 	}
 
 	// 批量推送到队列
-	if err := s.producer.PushBatch(ctx, tasks); err != nil {
-		s.logger.Error(fmt.Sprintf("failed to push batch to queue: %v", err))
+	if len(tasks) > 0 {
+		if err := s.producer.PushBatch(ctx, tasks); err != nil {
+			s.logger.Error(fmt.Sprintf("failed to push batch to queue: %v", err))
+		}
+		s.notifyAccepted(ctx, req.AppID, tasks)
+	}
+
+	if len(suppressedTasks) > 0 {
+		s.logger.Info(fmt.Sprintf("receivers suppressed batch_id=%s app_id=%s count=%d", batchID, req.AppID, len(suppressedTasks)))
+		s.suppression.Notify(ctx, req.AppID, suppressedTasks, suppressed)
+		// 全部接收者被屏蔽时批次直接结束
+		if len(tasks) == 0 {
+			s.batchProgress.CompleteIfDone(batchID)
+		}
 	}
 
 	return &dto.BatchSendResponse{
-		BatchID:      batchID,
-		TotalCount:   len(req.Receivers),
-		SuccessCount: successCount,
-		FailedCount:  len(req.Receivers) - successCount,
-		CreatedAt:    time.Now(),
+		BatchID:             batchID,
+		TotalCount:          len(req.Receivers),
+		SuccessCount:        len(tasks),
+		FailedCount:         len(req.Receivers) - len(tasks),
+		CreatedAt:           batch.CreatedAt,
+		ThrottledReceivers:  throttled,
+		SuppressedReceivers: suppressedReceivers,
 	}, nil
 }
 
@@ -248,6 +416,70 @@ func (s *MessageService) QueryTask(ctx context.Context, taskID string) (*model.P
 	return task, nil
 }
 
+// QueryBatch 查询批次进度及任务列表
+// status 不为空时仅返回该状态的任务
+func (s *MessageService) QueryBatch(ctx context.Context, appID, batchID, status string, page, pageSize int) (*dto.BatchQueryResponse, error) {
+	batch, err := s.batchDao.GetByBatchID(batchID)
+	if err != nil || batch.AppID != appID {
+		return nil, fmt.Errorf("batch not found")
+	}
+
+	statusCounts, err := s.taskDao.CountByBatchIDGroupByStatus(batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count batch tasks: %w", err)
+	}
+
+	filters := map[string]interface{}{
+		"batch_id": batchID,
+	}
+	if status != "" {
+		filters["status"] = status
+	}
+	tasks, total, err := s.taskDao.List(page, pageSize, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list batch tasks: %w", err)
+	}
+
+	items := make([]*dto.BatchTaskItem, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, &dto.BatchTaskItem{
+			TaskID:         task.TaskID,
+			Receiver:       task.Receiver,
+			Status:         task.Status,
+			CallbackStatus: task.CallbackStatus,
+			CallbackTime:   task.CallbackTime,
+			RetryCount:     task.RetryCount,
+			CreatedAt:      task.CreatedAt,
+			UpdatedAt:      task.UpdatedAt,
+		})
+	}
+
+	return &dto.BatchQueryResponse{
+		BatchID:      batch.BatchID,
+		Status:       batch.Status,
+		TotalCount:   batch.TotalCount,
+		SuccessCount: batch.SuccessCount,
+		FailedCount:  batch.FailedCount,
+		PendingCount: batch.PendingCount,
+		StatusCounts: statusCounts,
+		CreatedAt:    batch.CreatedAt,
+		UpdatedAt:    batch.UpdatedAt,
+		Tasks: &dto.BatchTaskPage{
+			Total: total,
+			Page:  page,
+			Size:  pageSize,
+			Items: items,
+		},
+	}, nil
+}
+
+// notifyAccepted 发送 task.accepted 事件，通知失败不影响受理结果
+func (s *MessageService) notifyAccepted(ctx context.Context, appID string, tasks []*model.PushTask) {
+	if err := s.webhookService.NotifyTasksAccepted(ctx, appID, tasks); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to notify webhook event=%s app_id=%s: %v", constants.WebhookEventTaskAccepted, appID, err))
+	}
+}
+
 // validateTemplateParams 验证模板参数是否完整
 func (s *MessageService) validateTemplateParams(templateVars []string, params map[string]string) error {
 	var missingVars []string
@@ -269,6 +501,8 @@ func (s *MessageService) isValidMessageType(messageType string) bool {
 		constants.MessageTypeEmail,
 		constants.MessageTypeWeChatWork,
 		constants.MessageTypeDingTalk,
+		constants.MessageTypeFeishu,
+		constants.MessageTypeWebhook,
 	}
 
 	for _, t := range validTypes {
@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/dto"
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+	"github.com/redis/go-redis/v9"
+)
+
+// otpKeyPrefix 验证码记录 key 前缀，完整 key 为 push:otp:{app_id}:{scene}:{receiver}（哈希，保存验证码摘要和已校验次数）
+const otpKeyPrefix = "push:otp:"
+
+// otpCooldownKeyPrefix 重发冷却 key 前缀，完整 key 为 push:otp_cooldown:{app_id}:{scene}:{receiver}
+const otpCooldownKeyPrefix = "push:otp_cooldown:"
+
+// otpFallbackQueueKey 待检查是否需要备用通道重发的验证码（有序集合，score 为检查时间毫秒）
+const otpFallbackQueueKey = "push:otp_fallback"
+
+// defaultOTPScene 默认业务场景
+const defaultOTPScene = "default"
+
+var (
+	// ErrOTPCooldown 重发冷却期内再次请求发送验证码
+	ErrOTPCooldown = errors.New("verification code requested too frequently")
+	// ErrOTPInvalid 验证码错误
+	ErrOTPInvalid = errors.New("invalid verification code")
+	// ErrOTPExpired 验证码不存在、已过期或已使用
+	ErrOTPExpired = errors.New("verification code expired or not found")
+	// ErrOTPAttemptsExceeded 校验失败次数达到上限，验证码已作废
+	ErrOTPAttemptsExceeded = errors.New("too many verification attempts, please request a new code")
+)
+
+// otpVerifyScript 校验验证码
+// KEYS[1] 验证码记录，ARGV[1] 待校验验证码的摘要，ARGV[2] 最大校验次数
+// 返回 0 表示校验通过（记录已删除，不能再次使用），-1 表示记录不存在，-2 表示失败次数达到上限（记录已删除），
+// 正数表示校验失败及剩余可校验次数
+var otpVerifyScript = redis.NewScript(`
+local stored = redis.call('HGET', KEYS[1], 'hash')
+if not stored then
+	return -1
+end
+if stored == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+local remaining = tonumber(ARGV[2]) - attempts
+if remaining <= 0 then
+	redis.call('DEL', KEYS[1])
+	return -2
+end
+return remaining
+`)
+
+// otpAttachScript 验证码记录仍存在时写入字段（发送期间验证码可能已被使用，避免重新创建无过期时间的记录）
+// KEYS[1] 验证码记录，ARGV 为字段名和值交替排列，返回 1 表示已写入
+var otpAttachScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], unpack(ARGV))
+return 1
+`)
+
+// otpReplaceHashScript 记录中指定字段等于期望值时替换验证码摘要并移除备用通道信息（备用通道重发新验证码时使用）
+// KEYS[1] 验证码记录，ARGV[1] 比较的字段名，ARGV[2] 期望值，ARGV[3] 新的验证码摘要
+// 返回替换前的摘要，条件不满足（验证码已使用、已过期或已重新发送）时返回 nil
+var otpReplaceHashScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return false
+end
+local old = redis.call('HGET', KEYS[1], 'hash')
+redis.call('HSET', KEYS[1], 'hash', ARGV[3])
+redis.call('HDEL', KEYS[1], 'fallback')
+return old
+`)
+
+// popOTPFallbacksScript 原子地取出到期的备用通道检查项，多实例下每项只会被取出一次
+var popOTPFallbacksScript = redis.NewScript(`
+local items = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+if #items > 0 then
+	redis.call('ZREM', KEYS[1], unpack(items))
+end
+return items
+`)
+
+// otpFallback 备用通道重发所需的信息，保存在验证码记录的 fallback 字段中，随验证码过期或使用一同删除
+// 模板参数不含验证码，重发时生成新验证码，Redis 中不保存验证码明文
+type otpFallback struct {
+	AppID          string            `json:"app_id"`
+	Receiver       string            `json:"receiver"`
+	ChannelID      uint              `json:"channel_id"`
+	SignatureName  string            `json:"signature_name"`
+	TemplateParams map[string]string `json:"template_params"`
+}
+
+// otpFallbackItem 备用通道检查项
+type otpFallbackItem struct {
+	Key    string `json:"key"`     // 验证码记录 key
+	TaskID string `json:"task_id"` // 首次发送的任务ID，验证码重发后不再检查
+}
+
+// OTPService 验证码服务
+// 生成验证码并通过消息服务发送，Redis 中只保存验证码摘要；校验通过后立即作废，失败次数达到上限时作废
+type OTPService struct {
+	logger          gsr.Logger
+	redis           *redis.Client
+	messageService  *MessageService
+	taskDao         *dao.PushTaskDAO
+	length          int           // 验证码长度
+	alphabet        []rune        // 验证码字符集
+	ttl             time.Duration // 有效期
+	maxAttempts     int           // 最大校验次数
+	cooldown        time.Duration // 重发冷却时间
+	codeParam       string        // 验证码在模板参数中的名称
+	fallbackTimeout time.Duration // 超过该时长未送达时通过备用通道重发
+}
+
+// NewOTPService 创建验证码服务
+func NewOTPService() *OTPService {
+	h := internalHelper.GetHelper()
+	env := h.GetEnv()
+
+	length := env.GetInt("otp.length", 6)
+	if length <= 0 {
+		length = 6
+	}
+	alphabet := []rune(env.GetString("otp.alphabet", "0123456789"))
+	if len(alphabet) < 2 {
+		alphabet = []rune("0123456789")
+	}
+	maxAttempts := env.GetInt("otp.max_attempts", 5)
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	return &OTPService{
+		logger:          h.GetLogger(),
+		redis:           h.GetRedis(),
+		messageService:  NewMessageService(),
+		taskDao:         dao.NewPushTaskDAO(),
+		length:          length,
+		alphabet:        alphabet,
+		ttl:             time.Duration(env.GetInt("otp.ttl", 300)) * time.Second,
+		maxAttempts:     maxAttempts,
+		cooldown:        time.Duration(env.GetInt("otp.resend_cooldown", 60)) * time.Second,
+		codeParam:       env.GetString("otp.code_param", "code"),
+		fallbackTimeout: time.Duration(env.GetInt("otp.fallback_timeout", 60)) * time.Second,
+	}
+}
+
+// Send 生成并发送验证码
+// 同一应用、场景和接收者重新发送时旧验证码立即作废；发送失败时不占用冷却时间
+func (s *OTPService) Send(ctx context.Context, req *dto.OTPSendRequest) (*dto.OTPSendResponse, error) {
+	if req.FallbackChannelID != 0 && req.FallbackChannelID == req.ChannelID {
+		return nil, fmt.Errorf("fallback_channel_id must be different from channel_id")
+	}
+
+	scene := req.Scene
+	if scene == "" {
+		scene = defaultOTPScene
+	}
+	key := s.otpKey(req.AppID, scene, req.Receiver)
+	cooldownKey := otpCooldownKeyPrefix + req.AppID + ":" + scene + ":" + req.Receiver
+
+	// 1. 重发冷却
+	ok, err := s.redis.SetNX(ctx, cooldownKey, 1, s.cooldown).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check resend cooldown: %w", err)
+	}
+	if !ok {
+		retryAfter, _ := s.redis.TTL(ctx, cooldownKey).Result()
+		return nil, fmt.Errorf("%w, retry after %d seconds", ErrOTPCooldown, int(retryAfter.Seconds()))
+	}
+
+	// 2. 生成验证码并保存摘要（先保存再发送，避免用户收到验证码时记录尚未写入）
+	code, err := s.generateCode()
+	if err != nil {
+		s.redis.Del(ctx, cooldownKey)
+		return nil, fmt.Errorf("failed to generate code: %w", err)
+	}
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, "hash", s.hashCode(key, code), "attempts", 0)
+		pipe.Expire(ctx, key, s.ttl)
+		return nil
+	})
+	if err != nil {
+		s.redis.Del(ctx, cooldownKey)
+		return nil, fmt.Errorf("failed to save code: %w", err)
+	}
+
+	// 3. 通过消息服务发送
+	params := make(map[string]string, len(req.TemplateParams)+1)
+	maps.Copy(params, req.TemplateParams)
+	params[s.codeParam] = code
+
+	sendResp, err := s.messageService.Send(ctx, &dto.SendRequest{
+		AppID:          req.AppID,
+		ChannelID:      req.ChannelID,
+		Receiver:       req.Receiver,
+		TemplateParams: params,
+		SignatureName:  req.SignatureName,
+	})
+	if err != nil {
+		s.redis.Del(ctx, key, cooldownKey)
+		return nil, err
+	}
+
+	resp := &dto.OTPSendResponse{
+		TaskID:      sendResp.TaskID,
+		Status:      sendResp.Status,
+		ExpiresIn:   int(s.ttl.Seconds()),
+		ResendAfter: int(s.cooldown.Seconds()),
+	}
+
+	// 4. 登记备用通道检查
+	fields := []interface{}{"task_id", sendResp.TaskID}
+	if req.FallbackChannelID > 0 {
+		fallback, _ := json.Marshal(&otpFallback{
+			AppID:          req.AppID,
+			Receiver:       req.Receiver,
+			ChannelID:      req.FallbackChannelID,
+			SignatureName:  req.SignatureName,
+			TemplateParams: req.TemplateParams,
+		})
+		fields = append(fields, "fallback", string(fallback))
+	}
+	attached, err := otpAttachScript.Run(ctx, s.redis, []string{key}, fields...).Int()
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to save otp task app_id=%s task_id=%s: %v", req.AppID, sendResp.TaskID, err))
+		return resp, nil
+	}
+	if attached == 0 {
+		return resp, nil
+	}
+
+	if req.FallbackChannelID > 0 {
+		item, _ := json.Marshal(&otpFallbackItem{Key: key, TaskID: sendResp.TaskID})
+		dueAt := time.Now().Add(s.fallbackTimeout)
+		if err := s.redis.ZAdd(ctx, otpFallbackQueueKey, redis.Z{Score: float64(dueAt.UnixMilli()), Member: string(item)}).Err(); err != nil {
+			s.logger.Warn(fmt.Sprintf("failed to schedule otp fallback task_id=%s: %v", sendResp.TaskID, err))
+		} else {
+			resp.FallbackIn = int(s.fallbackTimeout.Seconds())
+		}
+	}
+
+	return resp, nil
+}
+
+// Verify 校验验证码，校验通过后验证码立即作废
+func (s *OTPService) Verify(ctx context.Context, req *dto.OTPVerifyRequest) error {
+	scene := req.Scene
+	if scene == "" {
+		scene = defaultOTPScene
+	}
+	key := s.otpKey(req.AppID, scene, req.Receiver)
+
+	result, err := otpVerifyScript.Run(ctx, s.redis, []string{key}, s.hashCode(key, req.Code), s.maxAttempts).Int()
+	if err != nil {
+		return fmt.Errorf("failed to verify code: %w", err)
+	}
+
+	switch {
+	case result == 0:
+		return nil
+	case result == -1:
+		return ErrOTPExpired
+	case result == -2:
+		return ErrOTPAttemptsExceeded
+	default:
+		return fmt.Errorf("%w, %d attempts remaining", ErrOTPInvalid, result)
+	}
+}
+
+// ProcessDueFallbacks 检查到期的验证码，首次发送仍未送达且验证码未使用时通过备用通道发送新验证码
+func (s *OTPService) ProcessDueFallbacks(ctx context.Context, limit int) {
+	items, err := popOTPFallbacksScript.Run(ctx, s.redis, []string{otpFallbackQueueKey}, time.Now().UnixMilli(), limit).StringSlice()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to pop otp fallbacks: %v", err))
+		return
+	}
+
+	for _, raw := range items {
+		var item otpFallbackItem
+		if err := json.Unmarshal([]byte(raw), &item); err != nil {
+			s.logger.Warn(fmt.Sprintf("invalid otp fallback item: %v", err))
+			continue
+		}
+		s.processFallback(ctx, &item)
+	}
+}
+
+// processFallback 处理单个备用通道检查项
+// 备用通道发送新生成的验证码并替换记录中的摘要，首次发送的验证码随之作废；备用通道发送失败时恢复原验证码
+func (s *OTPService) processFallback(ctx context.Context, item *otpFallbackItem) {
+	// 验证码已使用、已过期或已重新发送时不再处理
+	values, err := s.redis.HMGet(ctx, item.Key, "task_id", "fallback").Result()
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to load otp for fallback task_id=%s: %v", item.TaskID, err))
+		return
+	}
+	taskID, _ := values[0].(string)
+	rawFallback, _ := values[1].(string)
+	if taskID != item.TaskID || rawFallback == "" {
+		return
+	}
+
+	task, err := s.taskDao.GetByTaskID(item.TaskID)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("otp task not found for fallback task_id=%s: %v", item.TaskID, err))
+		return
+	}
+	if task.Status == constants.TaskStatusSuccess || task.CallbackStatus == constants.CallbackStatusDelivered {
+		s.redis.HDel(ctx, item.Key, "fallback")
+		return
+	}
+
+	var fallback otpFallback
+	if err := json.Unmarshal([]byte(rawFallback), &fallback); err != nil {
+		s.logger.Warn(fmt.Sprintf("invalid otp fallback task_id=%s: %v", item.TaskID, err))
+		s.redis.HDel(ctx, item.Key, "fallback")
+		return
+	}
+
+	// 先替换摘要再发送，避免用户收到新验证码时记录尚未更新
+	code, err := s.generateCode()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to generate otp for fallback task_id=%s: %v", item.TaskID, err))
+		return
+	}
+	newHash := s.hashCode(item.Key, code)
+	oldHash, err := otpReplaceHashScript.Run(ctx, s.redis, []string{item.Key}, "task_id", item.TaskID, newHash).Text()
+	if errors.Is(err, redis.Nil) {
+		return
+	}
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to replace otp for fallback task_id=%s: %v", item.TaskID, err))
+		return
+	}
+
+	params := make(map[string]string, len(fallback.TemplateParams)+1)
+	maps.Copy(params, fallback.TemplateParams)
+	params[s.codeParam] = code
+
+	resp, err := s.messageService.Send(ctx, &dto.SendRequest{
+		AppID:          fallback.AppID,
+		ChannelID:      fallback.ChannelID,
+		Receiver:       fallback.Receiver,
+		TemplateParams: params,
+		SignatureName:  fallback.SignatureName,
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("failed to send otp via fallback channel_id=%d task_id=%s: %v", fallback.ChannelID, item.TaskID, err))
+		// 新验证码未发出，恢复首次发送的验证码（期间未被重新发送或使用）
+		otpReplaceHashScript.Run(ctx, s.redis, []string{item.Key}, "hash", newHash, oldHash)
+		return
+	}
+	otpAttachScript.Run(ctx, s.redis, []string{item.Key}, "fallback_task_id", resp.TaskID)
+	s.logger.Info(fmt.Sprintf("otp task_id=%s not delivered (status=%s), resent via channel_id=%d task_id=%s",
+		item.TaskID, task.Status, fallback.ChannelID, resp.TaskID))
+}
+
+// otpKey 验证码记录 key
+func (s *OTPService) otpKey(appID, scene, receiver string) string {
+	return otpKeyPrefix + appID + ":" + scene + ":" + receiver
+}
+
+// generateCode 使用安全随机数按字符集生成验证码
+func (s *OTPService) generateCode() (string, error) {
+	code := make([]rune, s.length)
+	size := big.NewInt(int64(len(s.alphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", err
+		}
+		code[i] = s.alphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// hashCode 计算验证码摘要（以记录 key 作为前缀，不同接收者的相同验证码摘要不同）
+func (s *OTPService) hashCode(key, code string) string {
+	sum := sha256.Sum256([]byte(key + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
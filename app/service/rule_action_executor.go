@@ -21,7 +21,8 @@ type ActionExecutor struct {
 	logger            gsr.Logger
 	taskDAO           *dao.PushTaskDAO
 	logDAO            *dao.PushLogDAO
-	producer          *queue.Producer
+	retryQueue        *queue.DelayQueue
+	webhookService    *WebhookService
 	httpClient        *http.Client
 	defaultWebhookURL string // 系统默认告警 Webhook URL
 }
@@ -33,7 +34,8 @@ func NewActionExecutor() *ActionExecutor {
 		logger:            h.GetLogger(),
 		taskDAO:           dao.NewPushTaskDAO(),
 		logDAO:            dao.NewPushLogDAO(),
-		producer:          queue.NewProducer(h.GetRedis()),
+		retryQueue:        queue.NewRetryQueue(h.GetRedis()),
+		webhookService:    NewWebhookService(),
 		defaultWebhookURL: h.GetEnv().GetString("alert.default_webhook_url", ""),
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
@@ -124,8 +126,11 @@ func (e *ActionExecutor) executeRetry(ctx context.Context, result *EvaluateResul
 	// 计算重试延迟（指数退避）
 	delay := e.calculateBackoff(task.RetryCount, config)
 
-	// 更新任务重试次数
+	// 更新任务重试次数和下次重试时间，任务回到 pending 等待重新认领发送
 	task.RetryCount++
+	nextRetryAt := time.Now().Add(delay)
+	task.NextRetryAt = &nextRetryAt
+	task.Status = constants.TaskStatusPending
 	if err := e.taskDAO.Update(task); err != nil {
 		e.logger.Error(fmt.Sprintf("failed to update task retry count: %v", err))
 	}
@@ -141,17 +146,19 @@ func (e *ActionExecutor) executeRetry(ctx context.Context, result *EvaluateResul
 		ErrorMessage:      execCtx.ErrorMessage,
 	})
 
-	// 延迟后重新推送到队列
-	go func() {
-		time.Sleep(delay)
-		if err := e.producer.Push(context.Background(), task); err != nil {
-			e.logger.Error(fmt.Sprintf("failed to push task to queue for retry task_id=%s: %v", task.TaskID, err))
-		}
-	}()
+	// 写入重试延迟队列，到期后由重试扫描器重新推送
+	if err := e.retryQueue.Add(ctx, task.TaskID, nextRetryAt); err != nil {
+		e.logger.Error(fmt.Sprintf("failed to schedule task retry task_id=%s: %v", task.TaskID, err))
+	}
 
 	e.logger.Info(fmt.Sprintf("task scheduled for retry task_id=%s retry_count=%d delay=%v",
 		task.TaskID, task.RetryCount, delay))
 
+	e.notifyTaskEvent(ctx, constants.WebhookEventTaskRetrying, execCtx, map[string]interface{}{
+		"action":        model.RuleActionRetry,
+		"next_retry_at": nextRetryAt.Format(time.RFC3339),
+	})
+
 	return &ExecuteResult{
 		Action:      model.RuleActionRetry,
 		ShouldRetry: true,
@@ -197,8 +204,11 @@ func (e *ActionExecutor) executeSwitchProvider(ctx context.Context, result *Eval
 			task.TaskID, execCtx.ProviderAccountID, task.GetExcludeProviderIDs()))
 	}
 
-	// 更新任务重试次数和排除列表
+	// 更新任务重试次数、排除列表和下次重试时间，任务回到 pending 等待重新认领发送
 	task.RetryCount++
+	nextRetryAt := time.Now().Add(1 * time.Second)
+	task.NextRetryAt = &nextRetryAt
+	task.Status = constants.TaskStatusPending
 	if err := e.taskDAO.Update(task); err != nil {
 		e.logger.Error(fmt.Sprintf("failed to update task for switch provider: %v", err))
 	}
@@ -214,18 +224,19 @@ func (e *ActionExecutor) executeSwitchProvider(ctx context.Context, result *Eval
 		ErrorMessage:      fmt.Sprintf("switching provider, exclude current: %v, excluded providers: %v", config.ExcludeCurrent, task.GetExcludeProviderIDs()),
 	})
 
-	// 重新推送到队列（选择器会根据 ExcludeProviderIDs 选择其他供应商）
-	go func() {
-		// 稍微延迟一下再推送
-		time.Sleep(1 * time.Second)
-		if err := e.producer.Push(context.Background(), task); err != nil {
-			e.logger.Error(fmt.Sprintf("failed to push task to queue for switch provider task_id=%s: %v", task.TaskID, err))
-		}
-	}()
+	// 稍微延迟后重新推送到队列（选择器会根据 ExcludeProviderIDs 选择其他供应商）
+	if err := e.retryQueue.Add(ctx, task.TaskID, nextRetryAt); err != nil {
+		e.logger.Error(fmt.Sprintf("failed to schedule task for switch provider task_id=%s: %v", task.TaskID, err))
+	}
 
 	e.logger.Info(fmt.Sprintf("task scheduled for switch provider retry task_id=%s exclude_current=%v excluded_providers=%v",
 		task.TaskID, config.ExcludeCurrent, task.GetExcludeProviderIDs()))
 
+	e.notifyTaskEvent(ctx, constants.WebhookEventTaskRetrying, execCtx, map[string]interface{}{
+		"action":        model.RuleActionSwitchProvider,
+		"next_retry_at": nextRetryAt.Format(time.RFC3339),
+	})
+
 	return &ExecuteResult{
 		Action:      model.RuleActionSwitchProvider,
 		ShouldRetry: true,
@@ -265,6 +276,10 @@ func (e *ActionExecutor) executeFail(ctx context.Context, result *EvaluateResult
 	e.logger.Info(fmt.Sprintf("task marked as failed task_id=%s rule=%s error=%s",
 		task.TaskID, ruleName, execCtx.ErrorMessage))
 
+	e.notifyTaskEvent(ctx, constants.WebhookEventTaskFailed, execCtx, map[string]interface{}{
+		"action": model.RuleActionFail,
+	})
+
 	return &ExecuteResult{
 		Action:       model.RuleActionFail,
 		ShouldRetry:  false,
@@ -333,6 +348,10 @@ func (e *ActionExecutor) executeAlert(ctx context.Context, result *EvaluateResul
 	e.logger.Info(fmt.Sprintf("alert sent and task marked as failed task_id=%s alert_level=%s alert_sent=%v",
 		task.TaskID, config.AlertLevel, alertSent))
 
+	e.notifyTaskEvent(ctx, constants.WebhookEventTaskFailed, execCtx, map[string]interface{}{
+		"action": model.RuleActionAlert,
+	})
+
 	return &ExecuteResult{
 		Action:       model.RuleActionAlert,
 		ShouldRetry:  false,
@@ -342,6 +361,16 @@ func (e *ActionExecutor) executeAlert(ctx context.Context, result *EvaluateResul
 	}
 }
 
+// notifyTaskEvent 向业务方发送任务生命周期事件
+func (e *ActionExecutor) notifyTaskEvent(ctx context.Context, event string, execCtx *ExecuteContext, extra map[string]interface{}) {
+	if execCtx.ProviderCode != "" {
+		extra["provider"] = execCtx.ProviderCode
+	}
+	if err := e.webhookService.NotifyTaskEvent(ctx, event, execCtx.Task, execCtx.ErrorCode, execCtx.ErrorMessage, extra); err != nil {
+		e.logger.Error(fmt.Sprintf("failed to notify webhook event=%s task_id=%s: %v", event, execCtx.Task.TaskID, err))
+	}
+}
+
 // sendAlertWebhook 发送告警 Webhook
 func (e *ActionExecutor) sendAlertWebhook(ctx context.Context, config *model.AlertActionConfig, execCtx *ExecuteContext) error {
 	if config.WebhookURL == "" {
@@ -361,12 +390,17 @@ func (e *ActionExecutor) sendAlertWebhook(ctx context.Context, config *model.Ale
 		"timestamp":    time.Now().Unix(),
 	}
 
+	return postAlertWebhook(ctx, e.httpClient, config.WebhookURL, payload)
+}
+
+// postAlertWebhook 投递告警 Webhook
+func postAlertWebhook(ctx context.Context, client *http.Client, webhookURL string, payload map[string]interface{}) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal alert payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.WebhookURL, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to create alert request: %w", err)
 	}
@@ -374,7 +408,7 @@ func (e *ActionExecutor) sendAlertWebhook(ctx context.Context, config *model.Ale
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "MessagePush-Alert/1.0")
 
-	resp, err := e.httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send alert: %w", err)
 	}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/constants"
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// sandboxCacheTTL 应用沙箱标记缓存时间
+const sandboxCacheTTL = time.Minute
+
+// SandboxService 沙箱模式服务
+// 沙箱应用的消息不走通道绑定，统一交给模拟服务商处理
+type SandboxService struct {
+	logger     gsr.Logger
+	cache      gsr.Cacher
+	appDao     *dao.ApplicationDAO
+	accountDao *dao.ProviderAccountDAO
+}
+
+// NewSandboxService 创建沙箱模式服务
+func NewSandboxService() *SandboxService {
+	h := helper.GetHelper()
+	return &SandboxService{
+		logger:     h.GetLogger(),
+		cache:      h.GetCache(),
+		appDao:     dao.NewApplicationDAO(),
+		accountDao: dao.NewProviderAccountDAO(),
+	}
+}
+
+// IsSandbox 应用是否开启沙箱模式（查询失败时按非沙箱处理）
+func (s *SandboxService) IsSandbox(ctx context.Context, appID string) bool {
+	var sandbox bool
+	err := s.cache.GetSet(ctx, buildSandboxCacheKey(appID), sandboxCacheTTL, &sandbox, func(key string, obj any) error {
+		app, err := s.appDao.GetByAppID(appID)
+		if err != nil {
+			return err
+		}
+		*obj.(*bool) = app.Sandbox
+		return nil
+	})
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to get sandbox flag app_id=%s: %v", appID, err))
+		return false
+	}
+	return sandbox
+}
+
+// Invalidate 清除应用沙箱标记缓存（修改沙箱开关后调用）
+func (s *SandboxService) Invalidate(ctx context.Context, appID string) {
+	if err := s.cache.Del(ctx, buildSandboxCacheKey(appID)); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to invalidate sandbox flag app_id=%s: %v", appID, err))
+	}
+}
+
+// GetProviderAccount 获取沙箱使用的模拟服务商账号
+// 优先使用已启用的同类型模拟服务商账号配置，其次任意模拟服务商账号，都没有时使用默认配置
+func (s *SandboxService) GetProviderAccount(messageType string) *model.ProviderAccount {
+	accounts, err := s.accountDao.GetByProviderCode(constants.ProviderMock)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to get mock provider accounts: %v", err))
+	}
+
+	var account *model.ProviderAccount
+	for _, candidate := range accounts {
+		if candidate.ProviderType == messageType {
+			account = candidate
+			break
+		}
+	}
+	if account == nil && len(accounts) > 0 {
+		account = accounts[0]
+	}
+	if account == nil {
+		return &model.ProviderAccount{
+			AccountCode:  "sandbox",
+			AccountName:  "沙箱模拟服务商",
+			ProviderCode: constants.ProviderMock,
+			ProviderType: messageType,
+			Config:       "{}",
+			Status:       1,
+		}
+	}
+
+	// 复制一份，避免修改共享的账号对象
+	sandboxAccount := *account
+	sandboxAccount.ProviderType = messageType
+	return &sandboxAccount
+}
+
+// buildSandboxCacheKey 构建应用沙箱标记缓存 key
+func buildSandboxCacheKey(appID string) string {
+	return fmt.Sprintf("app:sandbox:%s", appID)
+}
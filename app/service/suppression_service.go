@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/model"
+	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// SuppressionService 屏蔽名单服务
+// 受理消息时和发送前各检查一次，命中屏蔽名单的任务以 suppressed 状态结束并通知 task.suppressed 事件
+type SuppressionService struct {
+	logger         gsr.Logger
+	suppressionDao *dao.SuppressionDAO
+	webhookService *WebhookService
+}
+
+// NewSuppressionService 创建屏蔽名单服务
+func NewSuppressionService() *SuppressionService {
+	return &SuppressionService{
+		logger:         internalHelper.GetHelper().GetLogger(),
+		suppressionDao: dao.NewSuppressionDAO(),
+		webhookService: NewWebhookService(),
+	}
+}
+
+// Match 返回 receivers 中命中屏蔽名单的接收者及其屏蔽记录
+// 同一接收者命中多条记录时优先使用范围最大的记录；查询失败时按未命中处理，不影响正常发送
+func (s *SuppressionService) Match(appID string, channelID uint, receivers []string) map[string]*model.Suppression {
+	if len(receivers) == 0 {
+		return nil
+	}
+
+	entries, err := s.suppressionDao.GetActiveByReceivers(appID, channelID, receivers)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to check suppression list app_id=%s channel_id=%d: %v", appID, channelID, err))
+		return nil
+	}
+
+	matched := make(map[string]*model.Suppression, len(entries))
+	for _, entry := range entries {
+		if existing, ok := matched[entry.Receiver]; ok && suppressionScopeRank(existing.Scope) <= suppressionScopeRank(entry.Scope) {
+			continue
+		}
+		matched[entry.Receiver] = entry
+	}
+	return matched
+}
+
+// Notify 通知任务因命中屏蔽名单而未发送（task.suppressed 事件）
+func (s *SuppressionService) Notify(ctx context.Context, appID string, tasks []*model.PushTask, entries map[string]*model.Suppression) {
+	if len(tasks) == 0 {
+		return
+	}
+	if err := s.webhookService.NotifyTasksSuppressed(ctx, appID, tasks, entries); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to notify suppressed webhook app_id=%s: %v", appID, err))
+	}
+}
+
+// suppressionScopeRank 屏蔽范围排序，数值越小范围越大
+func suppressionScopeRank(scope string) int {
+	switch scope {
+	case model.SuppressionScopeGlobal:
+		return 0
+	case model.SuppressionScopeApp:
+		return 1
+	default:
+		return 2
+	}
+}
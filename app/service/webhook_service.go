@@ -9,195 +9,439 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"time"
 
+	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/dao"
 	"cnb.cool/mliev/push/message-push/app/model"
-	"cnb.cool/mliev/push/message-push/app/sender"
 	internalHelper "cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/google/uuid"
 	"github.com/muleiwu/gsr"
+	"github.com/redis/go-redis/v9"
 )
 
+// webhookFailCountKeyPrefix Webhook 连续投递失败计数 key 前缀
+const webhookFailCountKeyPrefix = "webhook_fail_count:"
+
 // WebhookService Webhook 服务
+// 事件写入 webhook_logs 发件箱后立即返回，由 WebhookDispatcher 异步投递，不阻塞回调处理
 type WebhookService struct {
 	logger           gsr.Logger
+	redis            *redis.Client
 	webhookConfigDao *dao.WebhookConfigDAO
 	webhookLogDao    *dao.WebhookLogDAO
-	httpClient       *http.Client
+	maxBackoff       time.Duration // 重试退避上限
+	disableThreshold int           // 连续投递失败（重试耗尽）达到该次数自动停用，0 表示不停用
 }
 
 // NewWebhookService 创建 Webhook 服务
 func NewWebhookService() *WebhookService {
 	h := internalHelper.GetHelper()
+	env := h.GetEnv()
 	return &WebhookService{
 		logger:           h.GetLogger(),
+		redis:            h.GetRedis(),
 		webhookConfigDao: dao.NewWebhookConfigDAO(),
 		webhookLogDao:    dao.NewWebhookLogDAO(),
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		maxBackoff:       time.Duration(env.GetInt("webhook.max_backoff", 3600)) * time.Second,
+		disableThreshold: env.GetInt("webhook.auto_disable_threshold", 10),
 	}
 }
 
-// WebhookPayload Webhook 推送的数据结构
+// WebhookPayload Webhook 推送的数据结构（事件目录 v1）
+// 所有事件使用同一结构，与事件无关的字段为空
 type WebhookPayload struct {
-	Event     string                 `json:"event"`      // 事件类型：success, failed, delivered, rejected
-	TaskID    string                 `json:"task_id"`    // 任务ID
-	AppID     string                 `json:"app_id"`     // 应用ID
-	Status    string                 `json:"status"`     // 任务状态
-	Receiver  string                 `json:"receiver"`   // 接收者
-	ErrorCode string                 `json:"error_code"` // 错误码（失败时）
-	ErrorMsg  string                 `json:"error_msg"`  // 错误信息（失败时）
-	Timestamp int64                  `json:"timestamp"`  // 时间戳
-	Extra     map[string]interface{} `json:"extra"`      // 额外信息
-}
-
-// NotifyStatusChange 通知任务状态变更
-func (s *WebhookService) NotifyStatusChange(ctx context.Context, task *model.PushTask, result *sender.CallbackResult) error {
-	// 1. 获取应用的 Webhook 配置
-	config, err := s.webhookConfigDao.GetEnabledByAppID(task.AppID)
-	if err != nil {
-		// 没有配置 Webhook，不需要通知
-		s.logger.Debug(fmt.Sprintf("no webhook config for app_id=%s", task.AppID))
-		return nil
+	ID             string                 `json:"id"`                        // 事件ID，重试时不变，可用于去重
+	Event          string                 `json:"event"`                     // 事件类型，见事件目录
+	Version        string                 `json:"version"`                   // 载荷结构版本
+	TaskID         string                 `json:"task_id"`                   // 任务ID
+	BatchID        string                 `json:"batch_id,omitempty"`        // 批次ID（批量发送时有值）
+	AppID          string                 `json:"app_id"`                    // 应用ID
+	Status         string                 `json:"status"`                    // 任务（或批次）状态
+	CallbackStatus string                 `json:"callback_status,omitempty"` // 回执状态
+	MessageType    string                 `json:"message_type,omitempty"`    // 消息类型
+	Receiver       string                 `json:"receiver"`                  // 接收者
+	RetryCount     int                    `json:"retry_count"`               // 已重试次数
+	ErrorCode      string                 `json:"error_code"`                // 错误码（失败时）
+	ErrorMsg       string                 `json:"error_msg"`                 // 错误信息（失败时）
+	Timestamp      int64                  `json:"timestamp"`                 // 时间戳
+	Extra          map[string]interface{} `json:"extra"`                     // 额外信息
+}
+
+// newWebhookPayload 创建事件载荷
+func newWebhookPayload(event, appID string) *WebhookPayload {
+	return &WebhookPayload{
+		ID:        uuid.New().String(),
+		Event:     event,
+		Version:   constants.WebhookEventVersion,
+		AppID:     appID,
+		Timestamp: time.Now().Unix(),
+		Extra:     map[string]interface{}{},
 	}
+}
 
-	// 2. 检查是否需要通知此事件
-	event := result.Status // delivered, failed, rejected
-	if !config.ShouldNotify(event) {
-		s.logger.Debug(fmt.Sprintf("event %s not subscribed for app_id=%s", event, task.AppID))
-		return nil
+// newTaskPayload 根据任务当前状态创建任务事件载荷
+func newTaskPayload(event string, task *model.PushTask, errorCode, errorMsg string) *WebhookPayload {
+	payload := newWebhookPayload(event, task.AppID)
+	payload.TaskID = task.TaskID
+	payload.BatchID = task.BatchID
+	payload.Status = task.Status
+	payload.CallbackStatus = task.CallbackStatus
+	payload.MessageType = task.MessageType
+	payload.Receiver = task.Receiver
+	payload.RetryCount = task.RetryCount
+	payload.ErrorCode = errorCode
+	payload.ErrorMsg = errorMsg
+	return payload
+}
+
+// NotifyTaskEvent 通知任务生命周期事件
+// extra 为事件相关的附加信息，可为 nil
+func (s *WebhookService) NotifyTaskEvent(ctx context.Context, event string, task *model.PushTask, errorCode, errorMsg string, extra map[string]interface{}) error {
+	payload := newTaskPayload(event, task, errorCode, errorMsg)
+	maps.Copy(payload.Extra, extra)
+
+	return s.notify(ctx, task.AppID, payload)
+}
+
+// NotifyTasksAccepted 通知同一应用的一组任务已受理（task.accepted 事件）
+func (s *WebhookService) NotifyTasksAccepted(ctx context.Context, appID string, tasks []*model.PushTask) error {
+	payloads := make([]*WebhookPayload, 0, len(tasks))
+	for _, task := range tasks {
+		payload := newTaskPayload(constants.WebhookEventTaskAccepted, task, "", "")
+		if task.ScheduledAt != nil {
+			payload.Extra["scheduled_at"] = task.ScheduledAt.Format(time.RFC3339)
+		}
+		payloads = append(payloads, payload)
 	}
 
-	// 3. 构造 Webhook 数据
-	payload := &WebhookPayload{
-		Event:     event,
-		TaskID:    task.TaskID,
-		AppID:     task.AppID,
-		Status:    task.Status,
-		Receiver:  task.Receiver,
-		ErrorCode: result.ErrorCode,
-		ErrorMsg:  result.ErrorMessage,
-		Timestamp: time.Now().Unix(),
-		Extra: map[string]interface{}{
-			"provider_id": result.ProviderID,
-			"report_time": result.ReportTime.Format(time.RFC3339),
-		},
+	return s.notify(ctx, appID, payloads...)
+}
+
+// NotifyTasksSuppressed 通知同一应用的一组任务因接收者命中屏蔽名单而未发送（task.suppressed 事件）
+// entries 为接收者对应的屏蔽记录，屏蔽类型和范围写入附加信息
+func (s *WebhookService) NotifyTasksSuppressed(ctx context.Context, appID string, tasks []*model.PushTask, entries map[string]*model.Suppression) error {
+	payloads := make([]*WebhookPayload, 0, len(tasks))
+	for _, task := range tasks {
+		payload := newTaskPayload(constants.WebhookEventTaskSuppressed, task, "", "")
+		if entry, ok := entries[task.Receiver]; ok {
+			payload.ErrorMsg = entry.Reason
+			payload.Extra["suppression_type"] = entry.Type
+			payload.Extra["suppression_scope"] = entry.Scope
+		}
+		payloads = append(payloads, payload)
+	}
+
+	return s.notify(ctx, appID, payloads...)
+}
+
+// NotifyTaskExpired 通知任务等待回执超时（task.expired 事件）
+func (s *WebhookService) NotifyTaskExpired(ctx context.Context, task *model.PushTask) error {
+	return s.NotifyTaskEvent(ctx, constants.WebhookEventTaskExpired, task, "", "callback timeout", nil)
+}
+
+// NotifyBatchCompleted 通知批次完成（batch.completed 事件）
+func (s *WebhookService) NotifyBatchCompleted(ctx context.Context, batch *model.PushBatchTask) error {
+	payload := newWebhookPayload(constants.WebhookEventBatchCompleted, batch.AppID)
+	payload.BatchID = batch.BatchID
+	payload.Status = batch.Status
+	payload.Extra = map[string]interface{}{
+		"total_count":   batch.TotalCount,
+		"success_count": batch.SuccessCount,
+		"failed_count":  batch.FailedCount,
+	}
+
+	return s.notify(ctx, batch.AppID, payload)
+}
+
+// NotifyInbound 通知收到用户上行回复（inbound 事件）
+// TaskID 为关联到的原始任务，回复内容等信息写入附加信息
+func (s *WebhookService) NotifyInbound(ctx context.Context, msg *model.InboundMessage) error {
+	payload := newWebhookPayload(constants.WebhookEventInbound, msg.AppID)
+	payload.TaskID = msg.TaskID
+	payload.MessageType = constants.MessageTypeSMS
+	payload.Receiver = msg.Phone
+	payload.Extra = map[string]interface{}{
+		"inbound_id":  msg.ID,
+		"content":     msg.Content,
+		"sign_name":   msg.SignName,
+		"extend_code": msg.ExtendCode,
+		"dest_code":   msg.DestCode,
+		"received_at": msg.ReceivedAt.Format(time.RFC3339),
+		"unsubscribe": msg.Unsubscribe,
 	}
 
-	// 4. 发送 Webhook 请求
-	return s.sendWebhook(ctx, config, payload)
+	return s.notify(ctx, msg.AppID, payload)
 }
 
-// sendWebhook 发送 Webhook 请求
-func (s *WebhookService) sendWebhook(ctx context.Context, config *model.WebhookConfig, payload *WebhookPayload) error {
-	// 序列化数据
+// SendTestEvent 向指定配置同步发送测试事件（不重试），返回投递记录
+func (s *WebhookService) SendTestEvent(ctx context.Context, config *model.WebhookConfig) (*model.WebhookLog, error) {
+	payload := newWebhookPayload(constants.WebhookEventTest, config.AppID)
+	payload.Extra = map[string]interface{}{
+		"webhook_config_id": config.ID,
+		"message":           "this is a test event",
+	}
+
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	// 设置超时
-	timeout := time.Duration(config.Timeout) * time.Second
-	if timeout <= 0 {
-		timeout = 5 * time.Second
+	webhookLog := &model.WebhookLog{
+		AppID:           config.AppID,
+		WebhookConfigID: config.ID,
+		WebhookURL:      config.WebhookURL,
+		Event:           payload.Event,
+		RequestData:     string(body),
 	}
-	s.httpClient.Timeout = timeout
 
-	// 发送请求（带重试）
-	var lastErr error
-	var lastRespStatus int
-	var lastRespBody string
-	maxRetries := config.RetryCount
-	if maxRetries <= 0 {
-		maxRetries = 3
+	deliverErr := s.attempt(ctx, config, webhookLog)
+	if deliverErr == nil {
+		webhookLog.Status = model.WebhookLogStatusSuccess
+	} else {
+		webhookLog.Status = model.WebhookLogStatusFailed
+	}
+	if err := s.webhookLogDao.Create(webhookLog); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to save webhook test log webhook_config_id=%d: %v", config.ID, err))
 	}
 
-	retryCount := 0
-	for i := 0; i <= maxRetries; i++ {
-		if i > 0 {
-			// 重试延迟
-			time.Sleep(time.Duration(i) * time.Second)
-			s.logger.Info(fmt.Sprintf("retrying webhook request for task_id=%s, attempt=%d", payload.TaskID, i))
-			retryCount = i
-		}
+	return webhookLog, deliverErr
+}
 
-		// 每次重试都需要创建新的请求（因为 body reader 会被消耗）
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.WebhookURL, bytes.NewBuffer(body))
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create request: %w", err)
-			continue
-		}
+// Deliver 投递一条发件箱记录（单次尝试）
+// 成功标记为 success；失败时按指数退避安排下次投递，重试耗尽后标记为 failed 并累计连续失败次数
+func (s *WebhookService) Deliver(ctx context.Context, webhookLog *model.WebhookLog) {
+	config, err := s.webhookConfigDao.GetByID(webhookLog.WebhookConfigID)
+	if err != nil || !config.IsEnabled() {
+		webhookLog.Status = model.WebhookLogStatusFailed
+		webhookLog.NextRetryAt = nil
+		webhookLog.ErrorMessage = "webhook config is disabled or deleted"
+		s.saveLog(webhookLog)
+		return
+	}
 
-		// 设置请求头
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("User-Agent", "MessagePush-Webhook/1.0")
-		req.Header.Set("X-Webhook-Event", payload.Event)
-		req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", payload.Timestamp))
+	deliverErr := s.attempt(ctx, config, webhookLog)
+	if deliverErr == nil {
+		webhookLog.Status = model.WebhookLogStatusSuccess
+		webhookLog.NextRetryAt = nil
+		s.saveLog(webhookLog)
+		s.recordSuccess(ctx, config)
+		s.logger.Info(fmt.Sprintf("webhook sent successfully log_id=%d task_id=%s url=%s", webhookLog.ID, webhookLog.TaskID, config.WebhookURL))
+		return
+	}
 
-		// 如果配置了签名密钥，添加签名
-		if config.Secret != "" {
-			signature := s.generateSignature(body, config.Secret, payload.Timestamp)
-			req.Header.Set("X-Webhook-Signature", signature)
-		}
+	s.logger.Warn(fmt.Sprintf("webhook request failed log_id=%d webhook_config_id=%d attempt=%d: %v",
+		webhookLog.ID, config.ID, webhookLog.RetryCount+1, deliverErr))
 
-		resp, err := s.httpClient.Do(req)
-		if err != nil {
-			lastErr = err
-			s.logger.Error(fmt.Sprintf("webhook request failed: %v", err))
-			continue
-		}
+	if webhookLog.RetryCount < config.RetryCount {
+		webhookLog.RetryCount++
+		nextRetryAt := time.Now().Add(s.backoff(config, webhookLog.RetryCount))
+		webhookLog.NextRetryAt = &nextRetryAt
+		s.saveLog(webhookLog)
+		return
+	}
+
+	webhookLog.Status = model.WebhookLogStatusFailed
+	webhookLog.NextRetryAt = nil
+	s.saveLog(webhookLog)
+	s.recordFailure(ctx, config)
+	s.logger.Error(fmt.Sprintf("webhook failed after %d retries log_id=%d webhook_config_id=%d", webhookLog.RetryCount, webhookLog.ID, config.ID))
+}
+
+// Redeliver 重新投递一条记录：以相同事件内容新建待投递记录，保留原记录作为历史
+func (s *WebhookService) Redeliver(webhookLog *model.WebhookLog) (*model.WebhookLog, error) {
+	config, err := s.webhookConfigDao.GetByID(webhookLog.WebhookConfigID)
+	if err != nil {
+		return nil, ErrWebhookConfigNotFound
+	}
+	if !config.IsEnabled() {
+		return nil, ErrWebhookConfigDisabled
+	}
+
+	now := time.Now()
+	redelivery := &model.WebhookLog{
+		TaskID:          webhookLog.TaskID,
+		AppID:           webhookLog.AppID,
+		WebhookConfigID: config.ID,
+		WebhookURL:      config.WebhookURL,
+		Event:           webhookLog.Event,
+		RequestData:     webhookLog.RequestData,
+		Status:          model.WebhookLogStatusPending,
+		NextRetryAt:     &now,
+	}
+	if err := s.webhookLogDao.Create(redelivery); err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
 
-		// 读取响应
-		respBody, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		lastRespStatus = resp.StatusCode
-		lastRespBody = string(respBody)
-
-		// 检查响应状态
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			s.logger.Info(fmt.Sprintf("webhook sent successfully task_id=%s url=%s", payload.TaskID, config.WebhookURL))
-			// 记录成功日志
-			s.webhookLogDao.Create(&model.WebhookLog{
+	return redelivery, nil
+}
+
+// notify 将事件写入应用所有订阅了该事件的 Webhook 配置的发件箱
+func (s *WebhookService) notify(ctx context.Context, appID string, payloads ...*WebhookPayload) error {
+	configs, err := s.webhookConfigDao.ListEnabledByAppID(appID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook configs: %w", err)
+	}
+	if len(configs) == 0 {
+		// 没有配置 Webhook，不需要通知
+		s.logger.Debug(fmt.Sprintf("no webhook config for app_id=%s", appID))
+		return nil
+	}
+
+	var webhookLogs []*model.WebhookLog
+	now := time.Now()
+	for _, payload := range payloads {
+		var body []byte
+		for _, config := range configs {
+			if !config.ShouldNotify(payload.Event) {
+				continue
+			}
+
+			if body == nil {
+				if body, err = json.Marshal(payload); err != nil {
+					return fmt.Errorf("failed to marshal payload: %w", err)
+				}
+			}
+
+			webhookLogs = append(webhookLogs, &model.WebhookLog{
 				TaskID:          payload.TaskID,
-				AppID:           payload.AppID,
+				AppID:           appID,
 				WebhookConfigID: config.ID,
 				WebhookURL:      config.WebhookURL,
 				Event:           payload.Event,
 				RequestData:     string(body),
-				ResponseStatus:  resp.StatusCode,
-				ResponseData:    lastRespBody,
-				Status:          "success",
-				RetryCount:      retryCount,
+				Status:          model.WebhookLogStatusPending,
+				NextRetryAt:     &now,
 			})
-			return nil
 		}
+	}
 
-		lastErr = fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(respBody))
-		s.logger.Error(fmt.Sprintf("webhook request failed: %v", lastErr))
+	if err := s.webhookLogDao.BatchCreate(webhookLogs); err != nil {
+		return fmt.Errorf("failed to create webhook deliveries: %w", err)
 	}
+	return nil
+}
 
-	// 记录失败日志
-	errMsg := ""
-	if lastErr != nil {
-		errMsg = lastErr.Error()
+// attempt 发起一次投递请求，并将响应写入投递记录
+func (s *WebhookService) attempt(ctx context.Context, config *model.WebhookConfig, webhookLog *model.WebhookLog) error {
+	// 每个配置独立的超时时间
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
 	}
-	s.webhookLogDao.Create(&model.WebhookLog{
-		TaskID:          payload.TaskID,
-		AppID:           payload.AppID,
-		WebhookConfigID: config.ID,
-		WebhookURL:      config.WebhookURL,
-		Event:           payload.Event,
-		RequestData:     string(body),
-		ResponseStatus:  lastRespStatus,
-		ResponseData:    lastRespBody,
-		Status:          "failed",
-		ErrorMessage:    errMsg,
-		RetryCount:      retryCount,
-	})
+	httpClient := &http.Client{Timeout: timeout}
 
-	return fmt.Errorf("webhook failed after %d retries: %w", maxRetries, lastErr)
+	// 始终投递到配置的最新地址
+	webhookLog.WebhookURL = config.WebhookURL
+	webhookLog.ResponseStatus = 0
+	webhookLog.ResponseData = ""
+	webhookLog.ErrorMessage = ""
+
+	body := []byte(webhookLog.RequestData)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.WebhookURL, bytes.NewBuffer(body))
+	if err != nil {
+		webhookLog.ErrorMessage = fmt.Sprintf("failed to create request: %v", err)
+		return err
+	}
+
+	// 时间戳和签名使用实际发送时间，避免重试请求被接收方判定为过期
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MessagePush-Webhook/1.0")
+	req.Header.Set("X-Webhook-Event", webhookLog.Event)
+	req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", timestamp))
+	if webhookLog.ID > 0 {
+		// 同一投递记录的重试使用相同 ID，接收方可据此去重
+		req.Header.Set("X-Webhook-Delivery-Id", fmt.Sprintf("%d", webhookLog.ID))
+	}
+
+	// 如果配置了签名密钥，添加签名
+	if config.Secret != "" {
+		req.Header.Set("X-Webhook-Signature", s.generateSignature(body, config.Secret, timestamp))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		webhookLog.ErrorMessage = err.Error()
+		return err
+	}
+	defer resp.Body.Close()
+
+	// 读取响应
+	respBody, _ := io.ReadAll(resp.Body)
+	webhookLog.ResponseStatus = resp.StatusCode
+	webhookLog.ResponseData = string(respBody)
+
+	// 检查响应状态
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(respBody))
+		webhookLog.ErrorMessage = err.Error()
+		return err
+	}
+
+	return nil
+}
+
+// backoff 计算第 n 次重试的退避时长：基础间隔 * 2^(n-1)，不超过上限
+func (s *WebhookService) backoff(config *model.WebhookConfig, retry int) time.Duration {
+	interval := time.Duration(config.RetryInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	delay := interval << min(retry-1, 20)
+	if s.maxBackoff > 0 && delay > s.maxBackoff {
+		delay = s.maxBackoff
+	}
+	return delay
+}
+
+// saveLog 保存投递记录
+func (s *WebhookService) saveLog(webhookLog *model.WebhookLog) {
+	if err := s.webhookLogDao.Update(webhookLog); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to update webhook log id=%d: %v", webhookLog.ID, err))
+	}
+}
+
+// recordSuccess 投递成功，清零连续失败计数
+func (s *WebhookService) recordSuccess(ctx context.Context, config *model.WebhookConfig) {
+	if s.disableThreshold <= 0 {
+		return
+	}
+
+	if err := s.redis.Del(ctx, fmt.Sprintf("%s%d", webhookFailCountKeyPrefix, config.ID)).Err(); err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to reset webhook fail count webhook_config_id=%d: %v", config.ID, err))
+	}
+}
+
+// recordFailure 投递最终失败，连续失败达到阈值时自动停用该 Webhook 配置
+func (s *WebhookService) recordFailure(ctx context.Context, config *model.WebhookConfig) {
+	if s.disableThreshold <= 0 {
+		return
+	}
+
+	key := fmt.Sprintf("%s%d", webhookFailCountKeyPrefix, config.ID)
+	count, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to incr webhook fail count webhook_config_id=%d: %v", config.ID, err))
+		return
+	}
+	s.redis.Expire(ctx, key, 7*24*time.Hour)
+
+	if count < int64(s.disableThreshold) {
+		return
+	}
+
+	if err := s.webhookConfigDao.UpdateStatus(config.ID, 0); err != nil {
+		s.logger.Error(fmt.Sprintf("failed to disable webhook config id=%d: %v", config.ID, err))
+		return
+	}
+	s.redis.Del(ctx, key)
+
+	s.logger.Warn(fmt.Sprintf("webhook config auto disabled id=%d app_id=%s url=%s consecutive_failures=%d",
+		config.ID, config.AppID, config.WebhookURL, count))
 }
 
 // generateSignature 生成签名
@@ -208,19 +452,3 @@ func (s *WebhookService) generateSignature(body []byte, secret string, timestamp
 	h.Write([]byte(message))
 	return hex.EncodeToString(h.Sum(nil))
 }
-
-// NotifyBatchStatus 批量通知任务状态变更
-func (s *WebhookService) NotifyBatchStatus(ctx context.Context, tasks []*model.PushTask, results []*sender.CallbackResult) error {
-	if len(tasks) != len(results) {
-		return fmt.Errorf("tasks and results length mismatch")
-	}
-
-	for i, task := range tasks {
-		if err := s.NotifyStatusChange(ctx, task, results[i]); err != nil {
-			s.logger.Error(fmt.Sprintf("failed to notify webhook for task_id=%s: %v", task.TaskID, err))
-			// 继续处理其他任务
-		}
-	}
-
-	return nil
-}
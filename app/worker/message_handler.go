@@ -3,8 +3,10 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"cnb.cool/mliev/push/message-push/app/constants"
 	"cnb.cool/mliev/push/message-push/app/dao"
@@ -30,6 +32,13 @@ type MessageHandler struct {
 	templateHelper      *helper.TemplateHelper
 	ruleEngine          *service.RuleEngineService
 	actionExecutor      *service.ActionExecutor
+	bindingHealth       *service.BindingHealthService
+	batchProgress       *service.BatchProgressService
+	webhookService      *service.WebhookService
+	sandbox             *service.SandboxService
+	suppression         *service.SuppressionService
+	retryQueue          *queue.DelayQueue
+	claimStaleAfter     time.Duration // processing 状态超过该时长未更新视为处理方已异常退出，允许重新认领
 }
 
 // NewMessageHandler 创建消息处理器
@@ -45,34 +54,130 @@ func NewMessageHandler() *MessageHandler {
 		templateHelper:      helper.NewTemplateHelper(),
 		ruleEngine:          service.GetRuleEngineService(),
 		actionExecutor:      service.NewActionExecutor(),
+		bindingHealth:       service.NewBindingHealthService(),
+		batchProgress:       service.NewBatchProgressService(),
+		webhookService:      service.NewWebhookService(),
+		sandbox:             service.NewSandboxService(),
+		suppression:         service.NewSuppressionService(),
+		retryQueue:          queue.NewRetryQueue(internalHelper.GetHelper().GetRedis()),
+		claimStaleAfter:     time.Duration(internalHelper.GetHelper().GetEnv().GetInt("worker.reclaim_min_idle", 300)) * time.Second,
 	}
 }
 
+// sendJob 已完成通道选择、签名解析和参数映射，等待发送的任务
+type sendJob struct {
+	msg          *queue.Message
+	task         *model.PushTask
+	node         *selector.ChannelNode
+	sender       sender.Sender
+	signature    *model.ProviderSignature
+	mappedParams map[string]string
+}
+
 // Handle 处理消息
 func (h *MessageHandler) Handle(ctx context.Context, msg *queue.Message) error {
+	job, err := h.prepare(ctx, msg)
+	if err != nil || job == nil {
+		return err
+	}
+
+	return h.send(ctx, job)
+}
+
+// HandleBatch 批量处理消息
+// 通道、模板绑定、签名和参数相同的任务合并为一次 BatchSend 调用（按服务商批量上限分批），
+// 不支持批量发送的任务在批量调用之后逐条发送；每条消息处理完成后立即通过 done 回调结果（由调用方确认），
+// 不等待整批处理完成，避免已发送的消息因长时间未确认被其他实例回收重复发送
+func (h *MessageHandler) HandleBatch(ctx context.Context, msgs []*queue.Message, done func(msg *queue.Message, err error)) {
+	groups := make(map[string][]*sendJob)
+	var groupKeys []string
+	var singles []*sendJob
+
+	for _, msg := range msgs {
+		job, err := h.prepare(ctx, msg)
+		if err != nil || job == nil {
+			done(msg, err)
+			continue
+		}
+
+		key, ok := h.batchKey(job)
+		if !ok {
+			singles = append(singles, job)
+			continue
+		}
+
+		if _, exists := groups[key]; !exists {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], job)
+	}
+
+	for _, key := range groupKeys {
+		jobs := groups[key]
+		if len(jobs) == 1 {
+			singles = append(singles, jobs[0])
+			continue
+		}
+
+		batchSender := jobs[0].sender.(sender.BatchSender)
+		size := sender.MaxBatchSize(jobs[0].node.ProviderAccount.ProviderCode)
+		for start := 0; start < len(jobs); start += size {
+			end := min(start+size, len(jobs))
+			h.sendBatch(ctx, batchSender, jobs[start:end], done)
+		}
+	}
+
+	for _, job := range singles {
+		done(job.msg, h.send(ctx, job))
+	}
+}
+
+// prepare 准备发送：认领任务、选择通道、解析签名和模板参数
+// 发送前的失败在此直接处理（标记任务失败），调用方只需返回错误
+// 任务已被处理（回收或重复投递的消息）或接收者命中屏蔽名单时任务在此结束，返回的 job 为 nil
+func (h *MessageHandler) prepare(ctx context.Context, msg *queue.Message) (*sendJob, error) {
 	// 解析任务ID
 	taskID, ok := msg.Data["task_id"].(string)
 	if !ok {
-		return fmt.Errorf("invalid task_id in message")
+		return nil, fmt.Errorf("invalid task_id in message")
 	}
 
 	// 获取任务
 	task, err := h.taskDao.GetByTaskID(taskID)
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("failed to get task id=%s: %v", taskID, err))
-		return err
+		return nil, err
 	}
 
-	// 更新任务状态为处理中
+	// 条件更新认领任务：已发送、已结束或正由其他消费者处理的任务不再发送，避免回收的消息重复发送
+	claimed, err := h.taskDao.ClaimForSend(taskID, time.Now().Add(-h.claimStaleAfter))
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("failed to claim task id=%s: %v", taskID, err))
+		return nil, err
+	}
+	if !claimed {
+		h.logger.Warn(fmt.Sprintf("task already handled, skip message task_id=%s status=%s", taskID, task.Status))
+		return nil, nil
+	}
 	task.Status = constants.TaskStatusProcessing
-	h.taskDao.Update(task)
+
+	// 发送前再次检查屏蔽名单（受理后接收者可能被加入屏蔽名单）
+	if suppressed := h.suppression.Match(task.AppID, task.ChannelID, []string{task.Receiver}); len(suppressed) > 0 {
+		h.handleSuppressed(ctx, task, suppressed)
+		return nil, nil
+	}
 
 	// 选择通道
 	node, err := h.selectChannel(ctx, task)
+	if errors.Is(err, selector.ErrAllBreakersOpen) {
+		// 所有节点熔断时延迟到熔断器进入半开后重新发送，不直接标记失败
+		h.deferSend(ctx, task, 0, h.selector.BreakerTimeout(), err.Error())
+		return nil, nil
+	}
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("failed to select channel task_id=%s: %v", taskID, err))
 		h.handleEarlyFailure(task, 0, err.Error())
-		return err
+		return nil, err
 	}
 
 	// 从节点直接获取服务商账号信息
@@ -81,7 +186,7 @@ func (h *MessageHandler) Handle(ctx context.Context, msg *queue.Message) error {
 		err := fmt.Errorf("provider account not found in channel node")
 		h.logger.Error(fmt.Sprintf("failed to get provider account task_id=%s: %v", taskID, err))
 		h.handleEarlyFailure(task, 0, err.Error())
-		return err
+		return nil, err
 	}
 
 	// 获取发送器（按服务商代码获取）
@@ -89,7 +194,7 @@ func (h *MessageHandler) Handle(ctx context.Context, msg *queue.Message) error {
 	if err != nil {
 		h.logger.Error(fmt.Sprintf("failed to get sender task_id=%s: %v", taskID, err))
 		h.handleEarlyFailure(task, providerAccount.ID, err.Error())
-		return err
+		return nil, err
 	}
 
 	// 查找签名映射，直接获取供应商签名
@@ -123,39 +228,155 @@ func (h *MessageHandler) Handle(ctx context.Context, msg *queue.Message) error {
 		}
 	}
 
-	// 发送消息
+	return &sendJob{
+		msg:          msg,
+		task:         task,
+		node:         node,
+		sender:       messageSender,
+		signature:    providerSignature,
+		mappedParams: mappedParams,
+	}, nil
+}
+
+// send 单条发送
+func (h *MessageHandler) send(ctx context.Context, job *sendJob) error {
+	task := job.task
+	node := job.node
+
 	sendReq := &sender.SendRequest{
 		Task:                   task,
-		ProviderAccount:        providerAccount,
+		ProviderAccount:        node.ProviderAccount,
 		ChannelTemplateBinding: node.ChannelTemplateBinding,
-		Signature:              providerSignature,
-		MappedParams:           mappedParams,
+		Signature:              job.signature,
+		MappedParams:           job.mappedParams,
 	}
 
-	resp, err := messageSender.Send(ctx, sendReq)
+	resp, err := job.sender.Send(ctx, sendReq)
 	if err != nil {
-		h.logger.Error(fmt.Sprintf("sender error task_id=%s: %v", taskID, err))
+		h.logger.Error(fmt.Sprintf("sender error task_id=%s: %v", task.TaskID, err))
 		// 如果 Send 返回了 resp（即使有 error），使用它来记录日志
 		if resp != nil {
-			h.handleSendError(task, providerAccount.ID, resp)
+			h.handleSendError(task, node, resp)
 		} else {
-			h.handleEarlyFailure(task, providerAccount.ID, err.Error())
+			h.selector.ReportFailure(node)
+			h.bindingHealth.RecordFailure(ctx, node.ChannelTemplateBinding, "", err.Error())
+			h.handleEarlyFailure(task, node.ProviderAccount.ID, err.Error())
 		}
 		return err
 	}
 
 	// 处理发送结果
-	if resp.Success {
-		h.handleSuccess(task, providerAccount.ID, resp)
+	if resp.RetryAfter > 0 {
+		h.handleThrottled(ctx, task, node, resp)
+	} else if resp.Success {
+		h.handleSuccess(task, node, resp)
 	} else {
-		h.handleSendError(task, providerAccount.ID, resp)
+		h.handleSendError(task, node, resp)
 	}
 
 	return nil
 }
 
+// sendBatch 批量发送同一分组的任务，并按 TaskID 将结果分发到各任务
+// 整批调用失败时按单条发送失败处理；响应中缺失结果的任务按发送失败处理（走规则引擎）
+// 每个任务在 prepare 选择通道时都占用了一次熔断器放行名额，因此熔断结果按任务逐条上报，
+// 否则半开探测名额无法释放、失败率也会被成功请求稀释
+func (h *MessageHandler) sendBatch(ctx context.Context, batchSender sender.BatchSender, jobs []*sendJob, done func(msg *queue.Message, err error)) {
+	first := jobs[0]
+	node := first.node
+
+	tasks := make([]*model.PushTask, len(jobs))
+	for i, job := range jobs {
+		tasks[i] = job.task
+	}
+
+	resp, err := batchSender.BatchSend(ctx, &sender.BatchSendRequest{
+		Tasks:                  tasks,
+		ProviderAccount:        node.ProviderAccount,
+		ChannelTemplateBinding: node.ChannelTemplateBinding,
+		Signature:              first.signature,
+		MappedParams:           first.mappedParams,
+	})
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("batch sender error provider=%s tasks=%d: %v", node.ProviderAccount.ProviderCode, len(jobs), err))
+		// 绑定健康统计的是连续失败的调用次数，一次调用只计一次
+		h.bindingHealth.RecordFailure(ctx, node.ChannelTemplateBinding, "", err.Error())
+		for _, job := range jobs {
+			h.selector.ReportFailure(job.node)
+			h.handleEarlyFailure(job.task, node.ProviderAccount.ID, err.Error())
+			done(job.msg, err)
+		}
+		return
+	}
+
+	results := make(map[string]*sender.SendResponse, len(resp.Results))
+	for _, result := range resp.Results {
+		if result != nil && result.TaskID != "" {
+			results[result.TaskID] = result
+		}
+	}
+
+	h.logger.Info(fmt.Sprintf("batch sent provider=%s tasks=%d results=%d", node.ProviderAccount.ProviderCode, len(jobs), len(results)))
+
+	for _, job := range jobs {
+		result, ok := results[job.task.TaskID]
+		if !ok {
+			// 缺失结果的任务同样占用了放行名额，按发送失败逐条上报熔断器
+			result = &sender.SendResponse{
+				TaskID:       job.task.TaskID,
+				ErrorMessage: "no result returned for task in batch response",
+			}
+		}
+
+		if result.Success {
+			h.handleSuccess(job.task, job.node, result)
+		} else {
+			h.handleSendError(job.task, job.node, result)
+		}
+		done(job.msg, nil)
+	}
+}
+
+// batchKey 计算批量发送分组键，服务商不支持批量发送时返回 false
+// 批量发送时所有任务共用同一通道、模板绑定、签名和参数，因此这些字段必须完全一致才能合并
+func (h *MessageHandler) batchKey(job *sendJob) (string, bool) {
+	batchSender, ok := job.sender.(sender.BatchSender)
+	if !ok || !batchSender.SupportsBatchSend() {
+		return "", false
+	}
+
+	var bindingID, signatureID uint
+	if job.node.ChannelTemplateBinding != nil {
+		bindingID = job.node.ChannelTemplateBinding.ID
+	}
+	if job.signature != nil {
+		signatureID = job.signature.ID
+	}
+
+	task := job.task
+	key, _ := json.Marshal([]interface{}{
+		task.ChannelID,
+		task.MessageType,
+		job.node.ProviderAccount.ID,
+		bindingID,
+		signatureID,
+		task.TemplateCode,
+		task.TemplateParams,
+		task.Title,
+		task.Content,
+	})
+	return string(key), true
+}
+
 // selectChannel 选择发送通道
 func (h *MessageHandler) selectChannel(ctx context.Context, task *model.PushTask) (*selector.ChannelNode, error) {
+	// 沙箱应用不走通道绑定，统一由模拟服务商处理（不计入绑定熔断和健康统计）
+	if h.sandbox.IsSandbox(ctx, task.AppID) {
+		return &selector.ChannelNode{
+			ProviderAccount: h.sandbox.GetProviderAccount(task.MessageType),
+		}, nil
+	}
+
 	// 使用选择器选择通道
 	channelID, err := parseUint(task.ChannelID)
 	if err != nil {
@@ -176,9 +397,12 @@ func (h *MessageHandler) selectChannel(ctx context.Context, task *model.PushTask
 }
 
 // handleSuccess 处理成功
-func (h *MessageHandler) handleSuccess(task *model.PushTask, providerAccountID uint, resp *sender.SendResponse) {
+func (h *MessageHandler) handleSuccess(task *model.PushTask, node *selector.ChannelNode, resp *sender.SendResponse) {
+	providerAccountID := node.ProviderAccount.ID
+	fromStatus := task.Status
 	task.Status = resp.Status // 使用发送器返回的状态（processing=等待回调, success=直接成功）
 	h.taskDao.Update(task)
+	h.batchProgress.Transition(task.BatchID, fromStatus, task.Status)
 
 	// 记录日志（每次新增，便于观测请求链路），ProviderMsgID 保存在日志中用于回调匹配
 	h.logDao.Create(&model.PushLog{
@@ -192,22 +416,33 @@ func (h *MessageHandler) handleSuccess(task *model.PushTask, providerAccountID u
 	})
 
 	// 通知选择器成功
-	h.selector.ReportSuccess(providerAccountID)
+	h.selector.ReportSuccess(node)
+	h.bindingHealth.RecordSuccess(context.Background(), node.ChannelTemplateBinding)
 
 	h.logger.Info(fmt.Sprintf("message sent successfully task_id=%s provider_id=%s status=%s", task.TaskID, resp.ProviderID, resp.Status))
+
+	// 同步发送成功即视为已送达，否则等待服务商回执
+	event := constants.WebhookEventTaskSent
+	if task.Status == constants.TaskStatusSuccess {
+		event = constants.WebhookEventTaskDelivered
+	}
+	h.notifyTaskEvent(event, task, "", "", map[string]interface{}{
+		"provider":    node.ProviderAccount.ProviderCode,
+		"provider_id": resp.ProviderID,
+	})
 }
 
 // handleSendError 处理发送错误（使用规则引擎）
-func (h *MessageHandler) handleSendError(task *model.PushTask, providerAccountID uint, resp *sender.SendResponse) {
+func (h *MessageHandler) handleSendError(task *model.PushTask, node *selector.ChannelNode, resp *sender.SendResponse) {
+	providerAccountID := node.ProviderAccount.ID
+	providerCode := node.ProviderAccount.ProviderCode
+	fromStatus := task.Status
+
 	// 通知选择器失败
-	h.selector.ReportFailure(providerAccountID)
+	h.selector.ReportFailure(node)
 
-	// 获取供应商代码
-	providerCode := ""
-	providerAccountDao := dao.NewProviderAccountDAO()
-	if account, err := providerAccountDao.GetByID(providerAccountID); err == nil {
-		providerCode = account.ProviderCode
-	}
+	// 统计绑定连续失败次数（达到阈值自动停用）
+	h.bindingHealth.RecordFailure(context.Background(), node.ChannelTemplateBinding, resp.ErrorCode, resp.ErrorMessage)
 
 	// 使用规则引擎评估
 	evalReq := &service.EvaluateRequest{
@@ -236,13 +471,48 @@ func (h *MessageHandler) handleSendError(task *model.PushTask, providerAccountID
 
 	h.logger.Info(fmt.Sprintf("rule engine executed task_id=%s action=%s retry=%v",
 		task.TaskID, execResult.Action, execResult.ShouldRetry))
+
+	h.batchProgress.Transition(task.BatchID, fromStatus, task.Status)
+}
+
+// handleThrottled 处理本地限流：请求未发出，延迟重新发送
+// 不计入熔断器、绑定健康统计和重试次数，到期后由重试扫描器重新推送
+func (h *MessageHandler) handleThrottled(ctx context.Context, task *model.PushTask, node *selector.ChannelNode, resp *sender.SendResponse) {
+	h.deferSend(ctx, task, node.ProviderAccount.ID, resp.RetryAfter, fmt.Sprintf("throttled by provider=%s: %s", node.ProviderAccount.ProviderCode, resp.ErrorMessage))
+}
+
+// deferSend 任务回到 pending 并写入重试延迟队列，delay 后重新发送（不增加重试次数）
+func (h *MessageHandler) deferSend(ctx context.Context, task *model.PushTask, providerAccountID uint, delay time.Duration, reason string) {
+	fromStatus := task.Status
+	nextRetryAt := time.Now().Add(delay)
+	task.Status = constants.TaskStatusPending
+	task.NextRetryAt = &nextRetryAt
+	h.taskDao.Update(task)
+	h.batchProgress.Transition(task.BatchID, fromStatus, task.Status)
+
+	h.logDao.Create(&model.PushLog{
+		TaskID:            task.TaskID,
+		AppID:             task.AppID,
+		ProviderAccountID: providerAccountID,
+		Status:            "retry",
+		ErrorMessage:      reason,
+	})
+
+	if err := h.retryQueue.Add(ctx, task.TaskID, nextRetryAt); err != nil {
+		h.logger.Error(fmt.Sprintf("failed to schedule deferred task task_id=%s: %v", task.TaskID, err))
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("task deferred for %v task_id=%s: %s", delay, task.TaskID, reason))
 }
 
 // handleEarlyFailure 处理早期失败（发送前的错误，无供应商响应数据）
 // 早期失败不使用规则引擎，直接标记失败
 func (h *MessageHandler) handleEarlyFailure(task *model.PushTask, providerAccountID uint, errorMsg string) {
+	fromStatus := task.Status
 	task.Status = constants.TaskStatusFailed
 	h.taskDao.Update(task)
+	h.batchProgress.Transition(task.BatchID, fromStatus, task.Status)
 
 	// 记录日志（每次新增，便于观测请求链路）
 	if providerAccountID > 0 {
@@ -258,6 +528,28 @@ func (h *MessageHandler) handleEarlyFailure(task *model.PushTask, providerAccoun
 	}
 
 	h.logger.Error(fmt.Sprintf("message failed task_id=%s error=%s", task.TaskID, errorMsg))
+
+	h.notifyTaskEvent(constants.WebhookEventTaskFailed, task, "", errorMsg, nil)
+}
+
+// handleSuppressed 处理命中屏蔽名单的任务：标记为 suppressed 并通知 task.suppressed 事件
+func (h *MessageHandler) handleSuppressed(ctx context.Context, task *model.PushTask, suppressed map[string]*model.Suppression) {
+	fromStatus := task.Status
+	task.Status = constants.TaskStatusSuppressed
+	task.NextRetryAt = nil
+	h.taskDao.Update(task)
+	h.batchProgress.Transition(task.BatchID, fromStatus, task.Status)
+
+	h.logger.Info(fmt.Sprintf("receiver suppressed before sending task_id=%s app_id=%s", task.TaskID, task.AppID))
+
+	h.suppression.Notify(ctx, task.AppID, []*model.PushTask{task}, suppressed)
+}
+
+// notifyTaskEvent 向业务方发送任务生命周期事件
+func (h *MessageHandler) notifyTaskEvent(event string, task *model.PushTask, errorCode, errorMsg string, extra map[string]interface{}) {
+	if err := h.webhookService.NotifyTaskEvent(context.Background(), event, task, errorCode, errorMsg, extra); err != nil {
+		h.logger.Error(fmt.Sprintf("failed to notify webhook event=%s task_id=%s: %v", event, task.TaskID, err))
+	}
 }
 
 // parseUint 解析uint
@@ -0,0 +1,163 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/queue"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+	"github.com/redis/go-redis/v9"
+)
+
+// ReclaimerConfig 待确认消息回收配置
+type ReclaimerConfig struct {
+	Interval      time.Duration // 回收扫描间隔
+	MinIdle       time.Duration // 消息未确认超过该时长才回收
+	MaxDeliveries int64         // 最大投递次数，超过后移入死信队列
+	ConsumerTTL   time.Duration // 消费者无活动超过该时长且无待确认消息时删除
+}
+
+// Reclaimer 待确认消息回收器
+// 通过 XAUTOCLAIM 认领崩溃或下线实例遗留的消息并重新处理，同时清理失效的消费者
+type Reclaimer struct {
+	instanceID string
+	config     ReclaimerConfig
+	worker     *Worker
+	stopCh     chan struct{}
+	wg         sync.WaitGroup
+	logger     gsr.Logger
+}
+
+// NewReclaimer 创建回收器
+func NewReclaimer(instanceID string, redisClient *redis.Client, handler MessageHandlerFunc) *Reclaimer {
+	env := helper.GetHelper().GetEnv()
+	return &Reclaimer{
+		instanceID: instanceID,
+		config: ReclaimerConfig{
+			Interval:      time.Duration(env.GetInt("worker.reclaim_interval", 30)) * time.Second,
+			MinIdle:       time.Duration(env.GetInt("worker.reclaim_min_idle", 300)) * time.Second,
+			MaxDeliveries: int64(env.GetInt("worker.max_deliveries", 5)),
+			ConsumerTTL:   time.Duration(env.GetInt("worker.consumer_ttl", 3600)) * time.Second,
+		},
+		worker: NewWorker(0, fmt.Sprintf("%s-reclaimer", instanceID), redisClient, handler),
+		stopCh: make(chan struct{}),
+		logger: helper.GetHelper().GetLogger(),
+	}
+}
+
+// Start 启动回收器
+func (r *Reclaimer) Start(ctx context.Context) error {
+	if err := r.worker.consumer.CreateGroup(ctx); err != nil {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.reclaim(ctx)
+				r.cleanupConsumers(ctx)
+			case <-r.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	r.logger.Info(fmt.Sprintf("reclaimer started consumer=%s min_idle=%v max_deliveries=%d",
+		r.worker.consumerName, r.config.MinIdle, r.config.MaxDeliveries))
+	return nil
+}
+
+// Stop 停止回收器
+func (r *Reclaimer) Stop() {
+	close(r.stopCh)
+	r.wg.Wait()
+	r.logger.Info("reclaimer stopped")
+}
+
+// reclaim 回收长时间未确认的消息
+func (r *Reclaimer) reclaim(ctx context.Context) {
+	start := "0-0"
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		default:
+		}
+
+		messages, next, err := r.worker.consumer.AutoClaim(ctx, r.config.MinIdle, start, 50)
+		if err != nil {
+			r.logger.Error(fmt.Sprintf("failed to auto claim pending messages: %v", err))
+			return
+		}
+
+		for _, msg := range messages {
+			r.handleClaimed(ctx, msg)
+		}
+
+		if next == "" || next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+// handleClaimed 处理认领到的消息
+func (r *Reclaimer) handleClaimed(ctx context.Context, msg *queue.Message) {
+	// 消息体已被删除（如 Stream 被裁剪），直接确认
+	if len(msg.Data) == 0 {
+		r.worker.consumer.Ack(ctx, msg.ID)
+		return
+	}
+
+	deliveries, err := r.worker.consumer.DeliveryCount(ctx, msg.ID)
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("failed to get delivery count message_id=%s: %v", msg.ID, err))
+		return
+	}
+
+	// 超过最大投递次数，移入死信队列
+	if r.config.MaxDeliveries > 0 && deliveries > r.config.MaxDeliveries {
+		r.logger.Warn(fmt.Sprintf("message exceeded max deliveries, moving to dead letter message_id=%s task_id=%s deliveries=%d",
+			msg.ID, msg.TaskID, deliveries))
+		if err := r.worker.consumer.MoveToDeadLetter(ctx, msg, fmt.Sprintf("exceeded max deliveries: %d", deliveries)); err != nil {
+			r.logger.Error(fmt.Sprintf("failed to move to dead letter message_id=%s err=%v", msg.ID, err))
+			return
+		}
+		r.worker.consumer.Ack(ctx, msg.ID)
+		return
+	}
+
+	r.logger.Info(fmt.Sprintf("reclaimed pending message message_id=%s task_id=%s deliveries=%d", msg.ID, msg.TaskID, deliveries))
+	if err := r.worker.handleMessage(ctx, msg); err != nil {
+		r.logger.Error(fmt.Sprintf("failed to handle reclaimed message message_id=%s task_id=%s err=%v", msg.ID, msg.TaskID, err))
+	}
+}
+
+// cleanupConsumers 清理失效的消费者（已下线实例遗留的消费者）
+func (r *Reclaimer) cleanupConsumers(ctx context.Context) {
+	prefix := r.instanceID + "-"
+	removed, err := r.worker.consumer.CleanupConsumers(ctx, r.config.ConsumerTTL, func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+	if err != nil {
+		r.logger.Error(fmt.Sprintf("failed to cleanup dead consumers: %v", err))
+		return
+	}
+
+	if len(removed) > 0 {
+		r.logger.Info(fmt.Sprintf("removed %d dead consumers: %v", len(removed), removed))
+	}
+}
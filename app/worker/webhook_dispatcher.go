@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cnb.cool/mliev/push/message-push/app/dao"
+	"cnb.cool/mliev/push/message-push/app/model"
+	"cnb.cool/mliev/push/message-push/app/service"
+	"cnb.cool/mliev/push/message-push/internal/helper"
+	"github.com/muleiwu/gsr"
+)
+
+// webhookClaimLease 投递记录认领租约，需大于单次投递的最大超时时间
+const webhookClaimLease = 2 * time.Minute
+
+// WebhookDispatcher Webhook 投递器
+// 定期从 webhook_logs 发件箱认领到期的待投递记录，交给独立的协程池发送，与消息发送 worker 互不影响
+type WebhookDispatcher struct {
+	logger         gsr.Logger
+	webhookLogDao  *dao.WebhookLogDAO
+	webhookService *service.WebhookService
+	poolSize       int
+	interval       time.Duration // 轮询间隔
+	batchSize      int           // 单次认领数量
+	jobs           chan *model.WebhookLog
+	stopCh         chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewWebhookDispatcher 创建 Webhook 投递器
+func NewWebhookDispatcher() *WebhookDispatcher {
+	h := helper.GetHelper()
+	env := h.GetEnv()
+
+	poolSize := env.GetInt("webhook.pool_size", 5)
+	if poolSize <= 0 {
+		poolSize = 5
+	}
+	batchSize := env.GetInt("webhook.batch_size", 100)
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &WebhookDispatcher{
+		logger:         h.GetLogger(),
+		webhookLogDao:  dao.NewWebhookLogDAO(),
+		webhookService: service.NewWebhookService(),
+		poolSize:       poolSize,
+		interval:       time.Duration(env.GetInt("webhook.poll_interval", 1)) * time.Second,
+		batchSize:      batchSize,
+		jobs:           make(chan *model.WebhookLog, batchSize),
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Start 启动投递器
+func (d *WebhookDispatcher) Start(ctx context.Context) error {
+	for i := 0; i < d.poolSize; i++ {
+		d.wg.Add(1)
+		go d.work(ctx)
+	}
+
+	d.wg.Add(1)
+	go d.poll(ctx)
+
+	d.logger.Info(fmt.Sprintf("webhook dispatcher started pool_size=%d", d.poolSize))
+	return nil
+}
+
+// Stop 停止投递器，等待正在进行的投递完成
+// 已认领但未投递的记录在租约到期后会被重新认领
+func (d *WebhookDispatcher) Stop() {
+	close(d.stopCh)
+	d.wg.Wait()
+	d.logger.Info("webhook dispatcher stopped")
+}
+
+// poll 轮询并认领到期的投递记录
+func (d *WebhookDispatcher) poll(ctx context.Context) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.dispatch(ctx)
+		case <-d.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatch 认领一批到期记录并分发给投递协程
+func (d *WebhookDispatcher) dispatch(ctx context.Context) {
+	logs, err := d.webhookLogDao.ClaimDue(time.Now(), webhookClaimLease, d.batchSize)
+	if err != nil {
+		d.logger.Error(fmt.Sprintf("failed to claim webhook deliveries: %v", err))
+	}
+
+	for _, webhookLog := range logs {
+		select {
+		case d.jobs <- webhookLog:
+		case <-d.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// work 投递协程
+func (d *WebhookDispatcher) work(ctx context.Context) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case webhookLog := <-d.jobs:
+			// 停止时让进行中的投递完成，避免被取消的请求计入失败次数
+			d.webhookService.Deliver(context.WithoutCancel(ctx), webhookLog)
+		case <-d.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"cnb.cool/mliev/push/message-push/app/queue"
+	"cnb.cool/mliev/push/message-push/app/sender"
 	"cnb.cool/mliev/push/message-push/internal/helper"
 	"github.com/muleiwu/gsr"
 	"github.com/redis/go-redis/v9"
@@ -15,26 +16,47 @@ import (
 // MessageHandlerFunc 消息处理函数
 type MessageHandlerFunc func(ctx context.Context, message *queue.Message) error
 
+// BatchMessageHandlerFunc 批量消息处理函数，每条消息处理完成后立即调用 done 回调处理结果
+type BatchMessageHandlerFunc func(ctx context.Context, messages []*queue.Message, done func(msg *queue.Message, err error))
+
 // Worker 工作者
 type Worker struct {
-	id       int
-	consumer *queue.Consumer
-	handler  MessageHandlerFunc
-	stopCh   chan struct{}
-	wg       *sync.WaitGroup
-	logger   gsr.Logger
+	id           int
+	consumerName string
+	consumer     *queue.Consumer
+	handler      MessageHandlerFunc
+	batchHandler BatchMessageHandlerFunc // 可选，设置后一次读取的多条消息合并处理
+	readCount    int64                   // 单次读取的消息数量
+	stopCh       chan struct{}
+	wg           *sync.WaitGroup
+	logger       gsr.Logger
 }
 
 // NewWorker 创建工作者
-func NewWorker(id int, redisClient *redis.Client, handler MessageHandlerFunc) *Worker {
-	consumerName := fmt.Sprintf("worker-%d", id)
+// consumerName 为消费者组内的消费者名称，多实例部署时必须全局唯一
+func NewWorker(id int, consumerName string, redisClient *redis.Client, handler MessageHandlerFunc) *Worker {
+	env := helper.GetHelper().GetEnv()
+	readCount := env.GetInt("worker.read_count", 10)
+	if readCount <= 0 {
+		readCount = 10
+	}
+	// 一次读取的消息可能逐条发送，最后一条消息确认前的等待时间需远小于回收阈值，否则会被其他实例回收重复发送
+	// 按单次发送超时（连接 + 读取）的两倍留出余量限制单次读取数量
+	reclaimMinIdle := env.GetInt("worker.reclaim_min_idle", 300)
+	if limit := max(reclaimMinIdle/(2*(sender.DefaultConnectTimeout+sender.DefaultTimeout)), 1); readCount > limit {
+		helper.GetHelper().GetLogger().Warn(fmt.Sprintf("worker.read_count=%d exceeds the limit %d for worker.reclaim_min_idle=%ds, using %d", readCount, limit, reclaimMinIdle, limit))
+		readCount = limit
+	}
+
 	return &Worker{
-		id:       id,
-		consumer: queue.NewConsumer(redisClient, consumerName),
-		handler:  handler,
-		stopCh:   make(chan struct{}),
-		wg:       &sync.WaitGroup{},
-		logger:   helper.GetHelper().GetLogger(),
+		id:           id,
+		consumerName: consumerName,
+		consumer:     queue.NewConsumer(redisClient, consumerName),
+		handler:      handler,
+		readCount:    int64(readCount),
+		stopCh:       make(chan struct{}),
+		wg:           &sync.WaitGroup{},
+		logger:       helper.GetHelper().GetLogger(),
 	}
 }
 
@@ -48,7 +70,7 @@ func (w *Worker) Start(ctx context.Context) error {
 	w.wg.Add(1)
 	go w.run(ctx)
 
-	w.logger.Info(fmt.Sprintf("worker started id=%d", w.id))
+	w.logger.Info(fmt.Sprintf("worker started id=%d consumer=%s", w.id, w.consumerName))
 	return nil
 }
 
@@ -76,12 +98,18 @@ func (w *Worker) run(ctx context.Context) {
 // processMessages 处理消息
 func (w *Worker) processMessages(ctx context.Context) {
 	// 读取消息，阻塞5秒
-	messages, err := w.consumer.ReadMessages(ctx, 10, 5*time.Second)
+	messages, err := w.consumer.ReadMessages(ctx, w.readCount, 5*time.Second)
 	if err != nil {
 		w.logger.Error(fmt.Sprintf("failed to read messages worker_id=%d err=%v", w.id, err))
 		return
 	}
 
+	// 批量处理（按通道、模板、签名和参数合并为服务商批量调用）
+	if w.batchHandler != nil && len(messages) > 1 {
+		w.handleMessages(ctx, messages)
+		return
+	}
+
 	// 处理每条消息
 	for _, msg := range messages {
 		if err := w.handleMessage(ctx, msg); err != nil {
@@ -95,11 +123,27 @@ func (w *Worker) handleMessage(ctx context.Context, msg *queue.Message) error {
 	w.logger.Info(fmt.Sprintf("processing message worker_id=%d message_id=%s task_id=%s", w.id, msg.ID, msg.TaskID))
 
 	// 执行业务逻辑
-	if err := w.handler(ctx, msg); err != nil {
+	return w.finishMessage(ctx, msg, w.handler(ctx, msg))
+}
+
+// handleMessages 批量处理消息，每条消息处理完成后立即进入死信队列或确认
+func (w *Worker) handleMessages(ctx context.Context, messages []*queue.Message) {
+	w.logger.Info(fmt.Sprintf("processing messages worker_id=%d count=%d", w.id, len(messages)))
+
+	w.batchHandler(ctx, messages, func(msg *queue.Message, err error) {
+		if err := w.finishMessage(ctx, msg, err); err != nil {
+			w.logger.Error(fmt.Sprintf("failed to handle message worker_id=%d message_id=%s task_id=%s err=%v", w.id, msg.ID, msg.TaskID, err))
+		}
+	})
+}
+
+// finishMessage 根据处理结果确认消息，处理失败的消息先移入死信队列
+func (w *Worker) finishMessage(ctx context.Context, msg *queue.Message, err error) error {
+	if err != nil {
 		w.logger.Error(fmt.Sprintf("handler error worker_id=%d message_id=%s err=%v", w.id, msg.ID, err))
 
 		// 移入死信队列
-		if dlErr := w.consumer.MoveToDeadLetter(ctx, msg); dlErr != nil {
+		if dlErr := w.consumer.MoveToDeadLetter(ctx, msg, err.Error()); dlErr != nil {
 			w.logger.Error(fmt.Sprintf("failed to move to dead letter worker_id=%d message_id=%s err=%v", w.id, msg.ID, dlErr))
 		}
 
@@ -118,10 +162,13 @@ type WorkerPool struct {
 }
 
 // NewWorkerPool 创建工作者池
-func NewWorkerPool(size int, redisClient *redis.Client, handler MessageHandlerFunc) *WorkerPool {
+// instanceID 为实例标识（主机名/Pod 名），消费者名称为 {instanceID}-worker-{序号}
+// batchHandler 可为 nil，此时逐条处理消息
+func NewWorkerPool(size int, instanceID string, redisClient *redis.Client, handler MessageHandlerFunc, batchHandler BatchMessageHandlerFunc) *WorkerPool {
 	workers := make([]*Worker, size)
 	for i := 0; i < size; i++ {
-		workers[i] = NewWorker(i+1, redisClient, handler)
+		workers[i] = NewWorker(i+1, fmt.Sprintf("%s-worker-%d", instanceID, i+1), redisClient, handler)
+		workers[i].batchHandler = batchHandler
 	}
 
 	return &WorkerPool{
@@ -97,6 +97,16 @@ func preMigrationCleanup(db *gorm.DB) error {
 		return fmt.Errorf("failed to migrate provider_msg_id to push_logs: %w", err)
 	}
 
+	// 移除 webhook_configs 表 app_id 唯一索引（一个应用支持多个 Webhook）
+	if err := dropWebhookConfigAppUniqueIndex(db); err != nil {
+		return fmt.Errorf("failed to drop unique index on webhook_configs: %w", err)
+	}
+
+	// 清理上行消息的空序列号和重复序列号，以便创建 (服务商账号, 序列号) 唯一索引
+	if err := fixInboundMessageSequenceIDs(db); err != nil {
+		return fmt.Errorf("failed to fix sequence_id on inbound_messages: %w", err)
+	}
+
 	log.Println("Pre-migration cleanup completed!")
 	return nil
 }
@@ -105,6 +115,11 @@ func preMigrationCleanup(db *gorm.DB) error {
 func customMigrations(db *gorm.DB) error {
 	log.Println("Running custom migrations...")
 
+	// 将应用旧的 webhook_url 迁移为 Webhook 配置
+	if err := migrateApplicationWebhookURL(db); err != nil {
+		return fmt.Errorf("failed to migrate application webhook_url: %w", err)
+	}
+
 	log.Println("Custom migrations completed!")
 	return nil
 }
@@ -268,6 +283,81 @@ func removeMessageTemplateCodeColumn(db *gorm.DB) error {
 	return nil
 }
 
+// dropWebhookConfigAppUniqueIndex 移除 webhook_configs 表 app_id 上的唯一索引
+func dropWebhookConfigAppUniqueIndex(db *gorm.DB) error {
+	if !db.Migrator().HasTable("webhook_configs") {
+		return nil
+	}
+
+	if !db.Migrator().HasIndex(&model.WebhookConfig{}, "uk_app_id") {
+		return nil
+	}
+
+	log.Println("Dropping unique index uk_app_id on webhook_configs...")
+	return db.Migrator().DropIndex(&model.WebhookConfig{}, "uk_app_id")
+}
+
+// fixInboundMessageSequenceIDs 空序列号置为 NULL（不参与唯一约束），重复推送的序列号仅保留最早一条
+func fixInboundMessageSequenceIDs(db *gorm.DB) error {
+	if !db.Migrator().HasTable("inbound_messages") {
+		return nil
+	}
+
+	if db.Migrator().HasIndex(&model.InboundMessage{}, "uk_provider_sequence") {
+		return nil
+	}
+
+	log.Println("Fixing sequence_id on inbound_messages...")
+	if err := db.Exec("UPDATE inbound_messages SET sequence_id = NULL WHERE sequence_id = ''").Error; err != nil {
+		return err
+	}
+	return db.Exec(`
+		UPDATE inbound_messages SET sequence_id = NULL
+		WHERE sequence_id IS NOT NULL AND id NOT IN (
+			SELECT id FROM (
+				SELECT MIN(id) AS id FROM inbound_messages
+				WHERE sequence_id IS NOT NULL
+				GROUP BY provider_account_id, sequence_id
+			) AS kept
+		)
+	`).Error
+}
+
+// migrateApplicationWebhookURL 为设置了 webhook_url 但没有 Webhook 配置的应用创建配置
+func migrateApplicationWebhookURL(db *gorm.DB) error {
+	var apps []*model.Application
+	if err := db.Where("webhook_url IS NOT NULL AND webhook_url != ''").Find(&apps).Error; err != nil {
+		return err
+	}
+
+	for _, app := range apps {
+		var count int64
+		if err := db.Model(&model.WebhookConfig{}).Where("app_id = ?", app.AppID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		config := &model.WebhookConfig{
+			AppID:       app.AppID,
+			Name:        "default",
+			WebhookURL:  app.WebhookURL,
+			Events:      "delivered,failed,rejected",
+			Status:      1,
+			RetryCount:  3,
+			Timeout:     5,
+			Description: "migrated from application webhook_url",
+		}
+		if err := db.Create(config).Error; err != nil {
+			return err
+		}
+		log.Printf("Created webhook config for app_id=%s from webhook_url", app.AppID)
+	}
+
+	return nil
+}
+
 // migrateProviderMsgIDToPushLogs 将 provider_msg_id 从 push_tasks 迁移到 push_logs
 func migrateProviderMsgIDToPushLogs(db *gorm.DB) error {
 	// 检查 push_tasks 表是否存在
@@ -26,6 +26,7 @@ func (receiver Migration) Get() []any {
 		&model.ProviderTemplate{},
 		&model.ChannelTemplateBinding{},  // 通道模板绑定配置表
 		&model.ChannelSignatureMapping{}, // 通道签名映射表
+		&model.ChannelBindingEvent{},     // 通道绑定事件表
 
 		// 健康检查和配额统计
 		&model.ChannelHealthHistory{},
@@ -44,6 +45,13 @@ func (receiver Migration) Get() []any {
 
 		// 规则引擎
 		&model.FailureRule{},
+
+		// 频率限制白名单
+		&model.FrequencyAllowlist{},
+
+		// 屏蔽名单
+		&model.Suppression{},
+		&model.InboundMessage{},
 	}
 }
 
@@ -48,6 +48,8 @@ func (receiver Router) InitConfig(helper envInterface.HelperInterface) map[strin
 				// 动态路由，支持所有账号的回调
 				callback.POST("/:id", deps.WrapHandler(controller.CallbackController{}.Handle))
 				callback.GET("/:id", deps.WrapHandler(controller.CallbackController{}.Handle))
+				callback.POST("/:id/inbound", deps.WrapHandler(controller.CallbackController{}.HandleInbound))
+				callback.GET("/:id/inbound", deps.WrapHandler(controller.CallbackController{}.HandleInbound))
 			}
 
 			// API v1 - 需要认证、限流、配额检查
@@ -62,6 +64,13 @@ func (receiver Router) InitConfig(helper envInterface.HelperInterface) map[strin
 
 				// 任务查询接口
 				v1.GET("/messages/:task_id", deps.WrapHandler(controller.MessageController{}.QueryTask))
+
+				// 批次查询接口
+				v1.GET("/batches/:batch_id", deps.WrapHandler(controller.MessageController{}.QueryBatch))
+
+				// 验证码接口
+				v1.POST("/otp/send", deps.WrapHandler(controller.OTPController{}.Send))
+				v1.POST("/otp/verify", deps.WrapHandler(controller.OTPController{}.Verify))
 			}
 
 			// Admin API - 管理后台认证接口（不需要认证）
@@ -100,6 +109,26 @@ func (receiver Router) InitConfig(helper envInterface.HelperInterface) map[strin
 					apps.DELETE("/:id", deps.WrapHandler(admin.ApplicationController{}.DeleteApplication))
 					apps.POST("/regenerate-secret", deps.WrapHandler(admin.ApplicationController{}.RegenerateSecret))
 					apps.GET("/:id/quota-usage", deps.WrapHandler(admin.ApplicationController{}.GetQuotaUsage))
+
+					// 应用 Webhook 配置（一个应用可配置多个回调地址）
+					apps.GET("/:id/webhooks", deps.WrapHandler(admin.WebhookController{}.GetWebhookList))
+					apps.POST("/:id/webhooks", deps.WrapHandler(admin.WebhookController{}.CreateWebhook))
+					apps.PUT("/:id/webhooks/:webhook_id", deps.WrapHandler(admin.WebhookController{}.UpdateWebhook))
+					apps.DELETE("/:id/webhooks/:webhook_id", deps.WrapHandler(admin.WebhookController{}.DeleteWebhook))
+					apps.POST("/:id/webhooks/:webhook_id/test", deps.WrapHandler(admin.WebhookController{}.TestWebhook))
+					apps.GET("/:id/webhooks/:webhook_id/deliveries", deps.WrapHandler(admin.WebhookController{}.GetWebhookDeliveries))
+
+					// 频率限制白名单
+					apps.GET("/:id/frequency-allowlist", deps.WrapHandler(admin.FrequencyController{}.GetAllowlist))
+					apps.POST("/:id/frequency-allowlist", deps.WrapHandler(admin.FrequencyController{}.AddAllowlist))
+					apps.DELETE("/:id/frequency-allowlist/:entry_id", deps.WrapHandler(admin.FrequencyController{}.DeleteAllowlist))
+				}
+
+				// Webhook 投递记录重新投递
+				webhookLogs := adminGroup.Group("/webhook-logs")
+				{
+					webhookLogs.POST("/redeliver", deps.WrapHandler(admin.WebhookController{}.RedeliverFailedWebhookLogs))
+					webhookLogs.POST("/:id/redeliver", deps.WrapHandler(admin.WebhookController{}.RedeliverWebhookLog))
 				}
 
 				// 服务商账号配置管理（新版）
@@ -143,6 +172,10 @@ func (receiver Router) InitConfig(helper envInterface.HelperInterface) map[strin
 					channels.GET("/:id/bindings/:bindingId", deps.WrapHandler(admin.ChannelController{}.GetChannelBinding))
 					channels.PUT("/:id/bindings/:bindingId", deps.WrapHandler(admin.ChannelController{}.UpdateChannelBinding))
 					channels.DELETE("/:id/bindings/:bindingId", deps.WrapHandler(admin.ChannelController{}.DeleteChannelBinding))
+					channels.GET("/:id/bindings/:bindingId/circuit-breaker", deps.WrapHandler(admin.ChannelController{}.GetBindingCircuitBreaker))
+					channels.POST("/:id/bindings/:bindingId/circuit-breaker/reset", deps.WrapHandler(admin.ChannelController{}.ResetBindingCircuitBreaker))
+					channels.POST("/:id/bindings/:bindingId/enable", deps.WrapHandler(admin.ChannelController{}.EnableChannelBinding))
+					channels.GET("/:id/binding-events", deps.WrapHandler(admin.ChannelController{}.GetChannelBindingEvents))
 					// 签名映射路由
 					channels.GET("/:id/available-signatures", deps.WrapHandler(admin.ChannelController{}.GetAvailableProviderSignatures))
 					channels.GET("/:id/signature-mappings", deps.WrapHandler(admin.ChannelController{}.GetChannelSignatureMappings))
@@ -186,6 +219,35 @@ func (receiver Router) InitConfig(helper envInterface.HelperInterface) map[strin
 					batchTasks.GET("/:id/tasks", deps.WrapHandler(admin.TaskController{}.GetBatchTaskDetails))
 				}
 
+				// 死信队列管理
+				deadLetters := adminGroup.Group("/dead-letters")
+				{
+					deadLetters.GET("", deps.WrapHandler(admin.DeadLetterController{}.GetDeadLetterList))
+					deadLetters.DELETE("", deps.WrapHandler(admin.DeadLetterController{}.PurgeDeadLetters))
+					deadLetters.POST("/replay", deps.WrapHandler(admin.DeadLetterController{}.ReplayDeadLetters))
+					deadLetters.POST("/trim", deps.WrapHandler(admin.DeadLetterController{}.TrimDeadLetters))
+					deadLetters.GET("/:id", deps.WrapHandler(admin.DeadLetterController{}.GetDeadLetter))
+					deadLetters.DELETE("/:id", deps.WrapHandler(admin.DeadLetterController{}.DeleteDeadLetter))
+					deadLetters.POST("/:id/replay", deps.WrapHandler(admin.DeadLetterController{}.ReplayDeadLetter))
+				}
+
+				// 屏蔽名单管理
+				suppressions := adminGroup.Group("/suppressions")
+				{
+					suppressions.POST("/import", deps.WrapHandler(admin.SuppressionController{}.ImportSuppressions))
+					suppressions.GET("", deps.WrapHandler(admin.SuppressionController{}.GetSuppressionList))
+					suppressions.POST("", deps.WrapHandler(admin.SuppressionController{}.CreateSuppression))
+					suppressions.GET("/:id", deps.WrapHandler(admin.SuppressionController{}.GetSuppression))
+					suppressions.PUT("/:id", deps.WrapHandler(admin.SuppressionController{}.UpdateSuppression))
+					suppressions.DELETE("/:id", deps.WrapHandler(admin.SuppressionController{}.DeleteSuppression))
+				}
+
+				// 上行消息（用户回复）
+				adminGroup.GET("/inbound-messages", deps.WrapHandler(admin.InboundController{}.GetInboundMessageList))
+
+				// 调度器
+				adminGroup.GET("/scheduler/leader", deps.WrapHandler(admin.SchedulerController{}.GetLeader))
+
 				// 模板管理
 				templates := adminGroup.Group("/templates")
 				{
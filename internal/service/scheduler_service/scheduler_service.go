@@ -3,16 +3,23 @@ package scheduler_service
 import (
 	"context"
 
+	apphelper "cnb.cool/mliev/push/message-push/app/helper"
 	"cnb.cool/mliev/push/message-push/app/scheduler"
+	"cnb.cool/mliev/push/message-push/app/service"
 	"cnb.cool/mliev/push/message-push/internal/interfaces"
 )
 
 // SchedulerService 调度服务
 type SchedulerService struct {
 	Helper            interfaces.HelperInterface
+	leaderElector     *scheduler.LeaderElector
 	scanner           *scheduler.ScheduledTaskScanner
+	retryScanner      *scheduler.RetryTaskScanner
 	quotaSyncer       *scheduler.QuotaSyncer
 	smsTimeoutScanner *scheduler.SMSTimeoutScanner
+	statusReconciler  *scheduler.StatusReconciler
+	mockCallbacks     *scheduler.MockCallbackDispatcher
+	otpFallbacks      *scheduler.OTPFallbackDispatcher
 	ctx               context.Context
 	cancel            context.CancelFunc
 }
@@ -29,24 +36,56 @@ func (receiver *SchedulerService) Run() error {
 	// 创建上下文
 	receiver.ctx, receiver.cancel = context.WithCancel(context.Background())
 
+	// 创建并启动 leader 选举（仅 leader 执行全局性的定时任务）
+	receiver.leaderElector = scheduler.NewLeaderElector(apphelper.ResolveInstanceID())
+	if err := receiver.leaderElector.Start(receiver.ctx); err != nil {
+		return err
+	}
+
 	// 创建并启动扫描器
 	receiver.scanner = scheduler.NewScheduledTaskScanner()
 	if err := receiver.scanner.Start(receiver.ctx); err != nil {
 		return err
 	}
 
+	// 创建并启动重试任务扫描器
+	receiver.retryScanner = scheduler.NewRetryTaskScanner()
+	if err := receiver.retryScanner.Start(receiver.ctx); err != nil {
+		return err
+	}
+
 	// 创建并启动配额同步器
-	receiver.quotaSyncer = scheduler.NewQuotaSyncer()
+	receiver.quotaSyncer = scheduler.NewQuotaSyncer(receiver.leaderElector)
 	if err := receiver.quotaSyncer.Start(receiver.ctx); err != nil {
 		return err
 	}
 
 	// 创建并启动短信超时扫描器
-	receiver.smsTimeoutScanner = scheduler.NewSMSTimeoutScanner()
+	receiver.smsTimeoutScanner = scheduler.NewSMSTimeoutScanner(receiver.leaderElector, service.NewWebhookService().NotifyTaskExpired)
 	if err := receiver.smsTimeoutScanner.Start(receiver.ctx); err != nil {
 		return err
 	}
 
+	// 创建并启动状态对账器（主动查询/拉取未收到回执的发送状态）
+	if receiver.Helper.GetEnv().GetBool("reconcile.enabled", true) {
+		receiver.statusReconciler = scheduler.NewStatusReconciler(receiver.leaderElector, service.NewCallbackService().HandleStatusResults)
+		if err := receiver.statusReconciler.Start(receiver.ctx); err != nil {
+			return err
+		}
+	}
+
+	// 创建并启动模拟回调投递器（模拟服务商、沙箱应用的送达回调）
+	receiver.mockCallbacks = scheduler.NewMockCallbackDispatcher(service.NewCallbackService().HandleCallback)
+	if err := receiver.mockCallbacks.Start(receiver.ctx); err != nil {
+		return err
+	}
+
+	// 创建并启动验证码备用通道调度器（首次发送未送达时通过备用通道重发）
+	receiver.otpFallbacks = scheduler.NewOTPFallbackDispatcher(service.NewOTPService().ProcessDueFallbacks)
+	if err := receiver.otpFallbacks.Start(receiver.ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -60,6 +99,10 @@ func (receiver *SchedulerService) Stop() error {
 		receiver.scanner.Stop()
 	}
 
+	if receiver.retryScanner != nil {
+		receiver.retryScanner.Stop()
+	}
+
 	if receiver.quotaSyncer != nil {
 		receiver.quotaSyncer.Stop()
 	}
@@ -68,5 +111,22 @@ func (receiver *SchedulerService) Stop() error {
 		receiver.smsTimeoutScanner.Stop()
 	}
 
+	if receiver.statusReconciler != nil {
+		receiver.statusReconciler.Stop()
+	}
+
+	if receiver.mockCallbacks != nil {
+		receiver.mockCallbacks.Stop()
+	}
+
+	if receiver.otpFallbacks != nil {
+		receiver.otpFallbacks.Stop()
+	}
+
+	// 最后释放 leader 租约，使其他实例尽快接管
+	if receiver.leaderElector != nil {
+		receiver.leaderElector.Stop()
+	}
+
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	apphelper "cnb.cool/mliev/push/message-push/app/helper"
 	"cnb.cool/mliev/push/message-push/app/worker"
 	"cnb.cool/mliev/push/message-push/internal/interfaces"
 )
@@ -12,6 +13,8 @@ import (
 type WorkerService struct {
 	Helper     interfaces.HelperInterface
 	workerPool *worker.WorkerPool
+	reclaimer  *worker.Reclaimer
+	dispatcher *worker.WebhookDispatcher
 	ctx        context.Context
 	cancel     context.CancelFunc
 }
@@ -31,12 +34,17 @@ func (receiver *WorkerService) Run() error {
 	// 创建消息处理器
 	handler := worker.NewMessageHandler()
 
+	// 实例标识，用于生成消费者组内唯一的消费者名称
+	instanceID := apphelper.ResolveInstanceID()
+
 	// 创建WorkerPool (默认10个worker)
-	poolSize := 10
+	poolSize := receiver.Helper.GetEnv().GetInt("worker.pool_size", 10)
 	receiver.workerPool = worker.NewWorkerPool(
 		poolSize,
+		instanceID,
 		receiver.Helper.GetRedis(),
 		handler.Handle,
+		handler.HandleBatch,
 	)
 
 	// 启动WorkerPool
@@ -44,7 +52,19 @@ func (receiver *WorkerService) Run() error {
 		return fmt.Errorf("failed to start worker pool: %w", err)
 	}
 
-	receiver.Helper.GetLogger().Info(fmt.Sprintf("worker pool started with %d workers", poolSize))
+	receiver.Helper.GetLogger().Info(fmt.Sprintf("worker pool started with %d workers instance=%s", poolSize, instanceID))
+
+	// 启动待确认消息回收器
+	receiver.reclaimer = worker.NewReclaimer(instanceID, receiver.Helper.GetRedis(), handler.Handle)
+	if err := receiver.reclaimer.Start(receiver.ctx); err != nil {
+		return fmt.Errorf("failed to start reclaimer: %w", err)
+	}
+
+	// 启动 Webhook 投递器
+	receiver.dispatcher = worker.NewWebhookDispatcher()
+	if err := receiver.dispatcher.Start(receiver.ctx); err != nil {
+		return fmt.Errorf("failed to start webhook dispatcher: %w", err)
+	}
 
 	return nil
 }
@@ -55,6 +75,14 @@ func (receiver *WorkerService) Stop() error {
 		receiver.cancel()
 	}
 
+	if receiver.dispatcher != nil {
+		receiver.dispatcher.Stop()
+	}
+
+	if receiver.reclaimer != nil {
+		receiver.reclaimer.Stop()
+	}
+
 	if receiver.workerPool != nil {
 		receiver.workerPool.Stop()
 	}